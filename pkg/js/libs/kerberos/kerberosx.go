@@ -1,7 +1,13 @@
 package kerberos
 
 import (
+	"bufio"
+	"encoding/json"
+	"math/rand"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dop251/goja"
 	kclient "github.com/jcmturner/gokrb5/v8/client"
@@ -11,6 +17,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/js/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	ConversionUtil "github.com/projectdiscovery/utils/conversion"
+	"go.uber.org/ratelimit"
 )
 
 // EnumerateUserResponse is the response from EnumerateUser
@@ -20,6 +27,33 @@ type EnumerateUserResponse struct {
 	Error     string `json:"error"`
 }
 
+// BatchOptions controls the behavior of EnumerateUsers / EnumerateUsersStream
+type BatchOptions struct {
+	// Concurrency is the number of usernames probed in parallel. Defaults to 1.
+	Concurrency int
+	// RequestsPerSecond throttles outgoing KDC requests via a token-bucket
+	// limiter. Zero or negative disables throttling.
+	RequestsPerSecond int
+	// JitterMS adds, per request, a random delay in the range [0, JitterMS)
+	// milliseconds before contacting the KDC.
+	JitterMS int
+	// MaxRetries is the number of extra attempts made for a username after a
+	// network error or a KRB_AP_ERR_SKEW response, using exponential backoff.
+	MaxRetries int
+	// CheckpointFile, when set, is appended with a JSONL record for every
+	// processed username so an interrupted run can be resumed.
+	CheckpointFile string
+	// Resume skips usernames already present in CheckpointFile.
+	Resume bool
+}
+
+// checkpointRecord is a single JSONL entry of a BatchOptions.CheckpointFile
+type checkpointRecord struct {
+	Username  string `json:"username"`
+	Status    string `json:"status"`
+	ASREPHash string `json:"asrep_hash,omitempty"`
+}
+
 // TGS is the response from GetServiceTicket
 type TGS struct {
 	Ticket messages.Ticket `json:"ticket"`
@@ -27,6 +61,13 @@ type TGS struct {
 	ErrMsg string          `json:"error"`
 }
 
+// S4UTicket is the response from GetS4U2SelfTicket and GetS4U2ProxyTicket
+type S4UTicket struct {
+	Ticket messages.Ticket `json:"ticket"`
+	Hash   string          `json:"hash"`
+	ErrMsg string          `json:"error"`
+}
+
 // Config is extra configuration for the kerberos client
 type Config struct {
 	ip      string
@@ -188,6 +229,176 @@ func (c *Client) EnumerateUser(username string) (EnumerateUserResponse, error) {
 	return resp, nil
 }
 
+// EnumerateUsers runs EnumerateUser over usernames honoring the
+// concurrency, rate-limiting, retry, and checkpointing behavior described by
+// opts, and collects every response before returning.
+// Signature: EnumerateUsers(usernames, opts)
+// @param usernames: string[]
+// @param opts: BatchOptions
+func (c *Client) EnumerateUsers(usernames []string, opts BatchOptions) ([]EnumerateUserResponse, error) {
+	c.nj.Require(c.Krb5Config != nil, "Kerberos client not initialized")
+
+	responses := make([]EnumerateUserResponse, 0, len(usernames))
+	for resp := range c.EnumerateUsersStream(usernames, opts) {
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// EnumerateUsersStream is the streaming variant of EnumerateUsers, emitting
+// each username's result on the returned channel as soon as it is available
+// instead of waiting for the whole batch to complete.
+// Signature: EnumerateUsersStream(usernames, opts)
+// @param usernames: string[]
+// @param opts: BatchOptions
+func (c *Client) EnumerateUsersStream(usernames []string, opts BatchOptions) <-chan EnumerateUserResponse {
+	out := make(chan EnumerateUserResponse)
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var limiter ratelimit.Limiter
+	if opts.RequestsPerSecond > 0 {
+		limiter = ratelimit.New(opts.RequestsPerSecond)
+	} else {
+		limiter = ratelimit.NewUnlimited()
+	}
+
+	alreadyDone := make(map[string]struct{})
+	if opts.Resume && opts.CheckpointFile != "" {
+		alreadyDone = loadCheckpoint(opts.CheckpointFile)
+	}
+
+	var checkpoint *os.File
+	if opts.CheckpointFile != "" {
+		if f, err := os.OpenFile(opts.CheckpointFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			checkpoint = f
+		}
+	}
+
+	go func() {
+		defer close(out)
+		if checkpoint != nil {
+			defer checkpoint.Close()
+		}
+
+		var checkpointMu sync.Mutex
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+
+		for _, username := range usernames {
+			if _, skip := alreadyDone[username]; skip {
+				continue
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(username string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if opts.JitterMS > 0 {
+					time.Sleep(time.Duration(rand.Intn(opts.JitterMS)) * time.Millisecond)
+				}
+
+				resp := c.enumerateUserWithRetry(username, opts.MaxRetries, limiter)
+
+				if checkpoint != nil {
+					record := checkpointRecord{Username: username, ASREPHash: resp.ASREPHash}
+					switch {
+					case resp.Valid:
+						record.Status = "valid"
+					case strings.HasPrefix(resp.Error, networkErrorPrefix):
+						// Never actually checked - don't record it as a
+						// confirmed result so Resume retries it.
+						record.Status = "error"
+					default:
+						record.Status = "invalid"
+					}
+					if b, err := json.Marshal(record); err == nil {
+						checkpointMu.Lock()
+						_, _ = checkpoint.Write(append(b, '\n'))
+						checkpointMu.Unlock()
+					}
+				}
+
+				out <- resp
+			}(username)
+		}
+		wg.Wait()
+	}()
+
+	return out
+}
+
+// networkErrorPrefix marks an EnumerateUserResponse.Error set by
+// enumerateUserWithRetry after exhausting its retries on a transport error,
+// as opposed to a confirmed KDC response. It lets EnumerateUsersStream's
+// checkpoint writer (and a later Resume) tell "never actually checked" apart
+// from a confirmed-invalid username; without it a transport failure would
+// present as Valid: false with an empty Error and be recorded as invalid.
+const networkErrorPrefix = "network error: "
+
+// enumerateUserWithRetry calls EnumerateUser, retrying with exponential
+// backoff on network errors and the transient KRB_AP_ERR_SKEW response, up
+// to maxRetries times. KDC_ERR_CLIENT_REVOKED and KDC_ERR_C_PRINCIPAL_UNKNOWN
+// are terminal and returned immediately without retrying, and
+// KDC_ERR_PREAUTH_REQUIRED is already recorded as a valid user by
+// EnumerateUser, so it also returns immediately. If retries are exhausted
+// because of a transport error (rather than a KDC response), the underlying
+// error is preserved in the response's Error field behind networkErrorPrefix
+// instead of being discarded, so it isn't mistaken for a confirmed negative.
+func (c *Client) enumerateUserWithRetry(username string, maxRetries int, limiter ratelimit.Limiter) EnumerateUserResponse {
+	backoff := time.Second
+
+	var resp EnumerateUserResponse
+	var err error
+	for attempt := 0; ; attempt++ {
+		limiter.Take()
+
+		resp, err = c.EnumerateUser(username)
+		if err == nil && resp.Error != errorcode.Lookup(errorcode.KRB_AP_ERR_SKEW) {
+			return resp
+		}
+		if resp.Error == errorcode.Lookup(errorcode.KDC_ERR_CLIENT_REVOKED) ||
+			resp.Error == errorcode.Lookup(errorcode.KDC_ERR_C_PRINCIPAL_UNKNOWN) {
+			return resp
+		}
+		if attempt >= maxRetries {
+			if err != nil {
+				resp.Error = networkErrorPrefix + err.Error()
+			}
+			return resp
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// loadCheckpoint reads a BatchOptions.CheckpointFile and returns the set of
+// usernames already confirmed processed, so a resumed run can skip them.
+// Records with status "error" (a transport failure, never actually checked
+// against the KDC) are intentionally excluded so Resume retries them.
+func loadCheckpoint(path string) map[string]struct{} {
+	done := make(map[string]struct{})
+
+	f, err := os.Open(path)
+	if err != nil {
+		return done
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record checkpointRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil && record.Username != "" && record.Status != "error" {
+			done[record.Username] = struct{}{}
+		}
+	}
+	return done
+}
+
 // GetServiceTicket returns a TGS for a given user, password, target and SPN
 // Signature: GetServiceTicket(User, Pass, Target, SPN)
 // @param User: string
@@ -253,6 +464,121 @@ func (c *Client) GetServiceTicket(User, Pass, SPN string) (TGS, error) {
 	return resp, nil
 }
 
+// GetS4U2SelfTicket impersonates TargetUser towards the caller's own SPN
+// using the S4U2Self constrained delegation extension, authenticating as the
+// service account (User/Pass) that is trusted for delegation. It returns the
+// evidence service ticket issued "on behalf of" TargetUser.
+// Signature: GetS4U2SelfTicket(User, Pass, SPN, TargetUser)
+// @param User: string service account trusted for constrained delegation
+// @param Pass: string service account password
+// @param SPN: string Service Principal Name of the calling service itself
+// @param TargetUser: string user to impersonate
+func (c *Client) GetS4U2SelfTicket(User, Pass, SPN, TargetUser string) (S4UTicket, error) {
+	c.nj.Require(c.Krb5Config != nil, "Kerberos client not initialized")
+	c.nj.Require(User != "", "User cannot be empty")
+	c.nj.Require(Pass != "", "Pass cannot be empty")
+	c.nj.Require(SPN != "", "SPN cannot be empty")
+	c.nj.Require(TargetUser != "", "TargetUser cannot be empty")
+
+	if len(c.Krb5Config.Realms) > 0 {
+		// this means dc address was given
+		for _, r := range c.Krb5Config.Realms {
+			for _, kdc := range r.KDC {
+				if !protocolstate.IsHostAllowed(kdc) {
+					c.nj.Throw("KDC address blacklisted by network policy")
+				}
+			}
+			for _, kpasswd := range r.KPasswdServer {
+				if !protocolstate.IsHostAllowed(kpasswd) {
+					c.nj.Throw("Kpasswd address blacklisted by network policy")
+				}
+			}
+		}
+	} else {
+		// here net.Dialer is used instead of fastdialer hence get possible addresses
+		// and check if they are allowed by network policy
+		_, kdcs, _ := c.Krb5Config.GetKDCs(c.Realm, true)
+		for _, v := range kdcs {
+			if !protocolstate.IsHostAllowed(v) {
+				c.nj.Throw("KDC address blacklisted by network policy")
+			}
+		}
+	}
+
+	// client does not actually attempt connection it manages state here
+	client := kclient.NewWithPassword(User, c.Realm, Pass, c.Krb5Config, kclient.DisablePAFXFAST(true))
+	defer client.Destroy()
+
+	if err := client.Login(); err != nil {
+		return S4UTicket{}, err
+	}
+
+	resp := S4UTicket{}
+	ticket, _, err := client.GetServiceTicketS4U2Self(TargetUser, SPN)
+	resp.Ticket = ticket
+	if err != nil {
+		if code, ok := err.(messages.KRBError); ok {
+			resp.ErrMsg = errorcode.Lookup(code.ErrorCode)
+			return resp, err
+		}
+		return resp, err
+	}
+	hashcat, err := TGStoHashcat(ticket, c.Realm)
+	if err != nil {
+		return resp, err
+	}
+	resp.Hash = hashcat
+	return resp, nil
+}
+
+// GetS4U2ProxyTicket exchanges the evidence ticket obtained for TargetUser
+// (via GetS4U2SelfTicket) for a service ticket to TargetSPN, completing the
+// S4U2Proxy constrained delegation hop on TargetUser's behalf.
+// Signature: GetS4U2ProxyTicket(User, Pass, SPN, TargetUser, TargetSPN)
+// @param User: string service account trusted for constrained delegation
+// @param Pass: string service account password
+// @param SPN: string SPN the evidence ticket was issued for (the caller's own SPN)
+// @param TargetUser: string user being impersonated
+// @param TargetSPN: string SPN of the downstream resource service to access
+func (c *Client) GetS4U2ProxyTicket(User, Pass, SPN, TargetUser, TargetSPN string) (S4UTicket, error) {
+	c.nj.Require(c.Krb5Config != nil, "Kerberos client not initialized")
+	c.nj.Require(User != "", "User cannot be empty")
+	c.nj.Require(Pass != "", "Pass cannot be empty")
+	c.nj.Require(SPN != "", "SPN cannot be empty")
+	c.nj.Require(TargetUser != "", "TargetUser cannot be empty")
+	c.nj.Require(TargetSPN != "", "TargetSPN cannot be empty")
+
+	evidence, err := c.GetS4U2SelfTicket(User, Pass, SPN, TargetUser)
+	if err != nil {
+		return S4UTicket{}, err
+	}
+
+	// client does not actually attempt connection it manages state here
+	client := kclient.NewWithPassword(User, c.Realm, Pass, c.Krb5Config, kclient.DisablePAFXFAST(true))
+	defer client.Destroy()
+
+	if err := client.Login(); err != nil {
+		return S4UTicket{}, err
+	}
+
+	resp := S4UTicket{}
+	ticket, _, err := client.GetServiceTicketS4U2Proxy(TargetUser, evidence.Ticket, TargetSPN)
+	resp.Ticket = ticket
+	if err != nil {
+		if code, ok := err.(messages.KRBError); ok {
+			resp.ErrMsg = errorcode.Lookup(code.ErrorCode)
+			return resp, err
+		}
+		return resp, err
+	}
+	hashcat, err := TGStoHashcat(ticket, c.Realm)
+	if err != nil {
+		return resp, err
+	}
+	resp.Hash = hashcat
+	return resp, nil
+}
+
 // GetASREP returns AS-REP for a given user and password
 // it contains Client's TGT , Principal and Session Key
 // Signature: GetASREP(User, Pass)
@@ -0,0 +1,144 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// metrics accumulates the counters exposed by /metrics, grouped per
+// template so operators scraping a long-running nuclei job can see which
+// templates are generating the most requests, matches or errors without
+// parsing the terminal progress bars.
+type metrics struct {
+	requestsTotal atomic.Uint64
+	matchesTotal  atomic.Uint64
+	errorsTotal   atomic.Uint64
+
+	mu          sync.Mutex
+	perTemplate map[string]*templateMetrics
+}
+
+// templateMetrics holds the per-template breakdown of the same three
+// counters tracked globally by metrics.
+type templateMetrics struct {
+	requests atomic.Uint64
+	matches  atomic.Uint64
+	errors   atomic.Uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{perTemplate: make(map[string]*templateMetrics)}
+}
+
+func (m *metrics) templateCounters(templateID string) *templateMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.perTemplate[templateID]
+	if !ok {
+		t = &templateMetrics{}
+		m.perTemplate[templateID] = t
+	}
+	return t
+}
+
+// ObserveRequest records a single completed HTTP request for templateID. A
+// non-2xx/3xx/4xx statusCode (zero, meaning the request never got a
+// response, or >= 500) is additionally counted as an error. latency is
+// accepted for future histogram support but isn't aggregated yet. It's a
+// no-op if metrics weren't enabled via NewProgress.
+func (p *Progress) ObserveRequest(templateID string, statusCode int, latency time.Duration) {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.requestsTotal.Inc()
+	counters := p.metrics.templateCounters(templateID)
+	counters.requests.Inc()
+
+	if statusCode <= 0 || statusCode >= http.StatusInternalServerError {
+		p.metrics.errorsTotal.Inc()
+		counters.errors.Inc()
+	}
+}
+
+// ObserveMatch records a single successful match for templateID. It's a
+// no-op if metrics weren't enabled via NewProgress.
+func (p *Progress) ObserveMatch(templateID string) {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.matchesTotal.Inc()
+	p.metrics.templateCounters(templateID).matches.Inc()
+}
+
+// writeTo renders the current counters in Prometheus text-exposition
+// format (https://prometheus.io/docs/instrumenting/exposition_formats/).
+func (m *metrics) writeTo(w io.Writer) {
+	fmt.Fprint(w, "# HELP nuclei_requests_total Total number of requests sent.\n")
+	fmt.Fprint(w, "# TYPE nuclei_requests_total counter\n")
+	fmt.Fprintf(w, "nuclei_requests_total %d\n", m.requestsTotal.Load())
+
+	fmt.Fprint(w, "# HELP nuclei_matches_total Total number of matches found.\n")
+	fmt.Fprint(w, "# TYPE nuclei_matches_total counter\n")
+	fmt.Fprintf(w, "nuclei_matches_total %d\n", m.matchesTotal.Load())
+
+	fmt.Fprint(w, "# HELP nuclei_errors_total Total number of request errors.\n")
+	fmt.Fprint(w, "# TYPE nuclei_errors_total counter\n")
+	fmt.Fprintf(w, "nuclei_errors_total %d\n", m.errorsTotal.Load())
+
+	m.mu.Lock()
+	templateIDs := make([]string, 0, len(m.perTemplate))
+	for id := range m.perTemplate {
+		templateIDs = append(templateIDs, id)
+	}
+	sort.Strings(templateIDs)
+
+	fmt.Fprint(w, "# HELP nuclei_template_requests_total Requests sent, per template.\n")
+	fmt.Fprint(w, "# TYPE nuclei_template_requests_total counter\n")
+	for _, id := range templateIDs {
+		fmt.Fprintf(w, "nuclei_template_requests_total{template=%q} %d\n", id, m.perTemplate[id].requests.Load())
+	}
+
+	fmt.Fprint(w, "# HELP nuclei_template_matches_total Matches found, per template.\n")
+	fmt.Fprint(w, "# TYPE nuclei_template_matches_total counter\n")
+	for _, id := range templateIDs {
+		fmt.Fprintf(w, "nuclei_template_matches_total{template=%q} %d\n", id, m.perTemplate[id].matches.Load())
+	}
+
+	fmt.Fprint(w, "# HELP nuclei_template_errors_total Request errors, per template.\n")
+	fmt.Fprint(w, "# TYPE nuclei_template_errors_total counter\n")
+	for _, id := range templateIDs {
+		fmt.Fprintf(w, "nuclei_template_errors_total{template=%q} %d\n", id, m.perTemplate[id].errors.Load())
+	}
+	m.mu.Unlock()
+}
+
+// newMetricsServer builds the *http.Server for the /metrics, /-/healthy and
+// /-/ready endpoints, modeled on the Prometheus web package's handler
+// layout: a dedicated ServeMux rather than registering on http.DefaultServeMux.
+func newMetricsServer(port int, m *metrics) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.writeTo(w)
+	})
+	mux.HandleFunc("/-/healthy", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "Nuclei is healthy.")
+	})
+	mux.HandleFunc("/-/ready", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "Nuclei is ready.")
+	})
+
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+}
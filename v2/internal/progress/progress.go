@@ -1,15 +1,22 @@
 package progress
 
 import (
+	"context"
 	"fmt"
 	"github.com/logrusorgru/aurora"
 	"github.com/vbauerster/mpb/v5"
 	"github.com/vbauerster/mpb/v5/decor"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
+// metricsShutdownTimeout bounds how long ServeMetrics waits for the
+// metrics HTTP server to drain in-flight scrapes once its context is done.
+const metricsShutdownTimeout = 5 * time.Second
+
 // Encapsulates progress tracking.
 type Progress struct {
 	progress        *mpb.Progress
@@ -20,10 +27,17 @@ type Progress struct {
 	stdout          *strings.Builder
 	stderr          *strings.Builder
 	colorizer       aurora.Aurora
+	enabled         bool
+
+	metrics       *metrics
+	metricsServer *http.Server
 }
 
-// Creates and returns a new progress tracking object.
-func NewProgress(noColor bool) *Progress {
+// Creates and returns a new progress tracking object. If metricsEnabled is
+// true, metricsPort must be a valid, non-zero port and a /metrics endpoint
+// (plus /-/healthy and /-/ready) is made available via ServeMetrics; see
+// ObserveRequest and ObserveMatch for how it's populated.
+func NewProgress(enableProgressBar, metricsEnabled bool, metricsPort int) (*Progress, error) {
 	p := &Progress{
 		progress: mpb.New(
 			mpb.WithOutput(os.Stderr),
@@ -32,14 +46,25 @@ func NewProgress(noColor bool) *Progress {
 		stdCaptureMutex: &sync.Mutex{},
 		stdout:          &strings.Builder{},
 		stderr:          &strings.Builder{},
-		colorizer:       aurora.NewAurora(!noColor),
+		colorizer:       aurora.NewAurora(true),
 		bars:            make(map[string]*mpb.Bar),
+		enabled:         enableProgressBar,
+	}
+	if metricsEnabled {
+		if metricsPort <= 0 {
+			return nil, fmt.Errorf("invalid metrics port: %d", metricsPort)
+		}
+		p.metrics = newMetrics()
+		p.metricsServer = newMetricsServer(metricsPort, p.metrics)
 	}
-	return p
+	return p, nil
 }
 
 // Creates and returns a progress bar that tracks request progress for a specific template.
 func (p *Progress) SetupTemplateProgressbar(templateId string, requestCount int64, priority int) {
+	if !p.enabled {
+		return
+	}
 	if p.bars[templateId] != nil {
 		panic(fmt.Sprintf("A progressbar is already bound to [%s].", templateId))
 	}
@@ -52,13 +77,16 @@ func (p *Progress) SetupTemplateProgressbar(templateId string, requestCount int6
 		uiBarName = uiBarName[:MaxLen] + ".."
 	}
 
-	uiBarName = fmt.Sprintf(fmt.Sprintf("%%-%ds", MaxLen), "[" + color.BrightYellow(uiBarName).String() + "]")
+	uiBarName = fmt.Sprintf(fmt.Sprintf("%%-%ds", MaxLen), "["+color.BrightYellow(uiBarName).String()+"]")
 	p.bars[templateId] = p.setupProgressbar(uiBarName, requestCount, priority)
 }
 
 // Creates and returns a progress bar that tracks all the requests progress.
 // This is only useful when multiple templates are processed within the same run.
 func (p *Progress) SetupGlobalProgressbar(hostCount int64, templateCount int, requestCount int64) {
+	if !p.enabled {
+		return
+	}
 	if p.gbar != nil {
 		panic("A global progressbar is already present.")
 	}
@@ -72,7 +100,7 @@ func (p *Progress) SetupGlobalProgressbar(hostCount int64, templateCount int, re
 		color.Bold(color.Cyan(hostCount)),
 		pluralize(hostCount, "host", "hosts"))
 
-	p.gbar = p.setupProgressbar("[" + barName + "]", requestCount, 0)
+	p.gbar = p.setupProgressbar("["+barName+"]", requestCount, 0)
 }
 
 func pluralize(count int64, singular, plural string) string {
@@ -85,6 +113,9 @@ func pluralize(count int64, singular, plural string) string {
 // Update progress tracking information and increments the request counter by one unit.
 // If a global progress bar is present it will be updated as well.
 func (p *Progress) Update(templateId string) {
+	if !p.enabled {
+		return
+	}
 	p.bars[templateId].Increment()
 	if p.gbar != nil {
 		p.gbar.Increment()
@@ -95,6 +126,9 @@ func (p *Progress) Update(templateId string) {
 // This may be the case when uncompleted requests are encountered and shouldn't be part of the total count.
 // If a global progress bar is present it will be updated as well.
 func (p *Progress) Drop(templateId string, count int64) {
+	if !p.enabled {
+		return
+	}
 	p.bars[templateId].IncrInt64(count)
 	if p.gbar != nil {
 		p.gbar.IncrInt64(count)
@@ -177,3 +211,32 @@ func (p *Progress) ShowStdErr() {
 		fmt.Fprint(os.Stderr, p.stderr.String())
 	}
 }
+
+// ServeMetrics starts the metrics HTTP server set up by NewProgress and
+// blocks until ctx is done, at which point it shuts the server down with a
+// grace period. It's a no-op returning nil immediately if metrics weren't
+// enabled. Meant to be run via errgroup.Group alongside the scan itself, so
+// an embedder like internal/runner can tear down both together.
+func (p *Progress) ServeMetrics(ctx context.Context) error {
+	if p.metricsServer == nil {
+		return nil
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := p.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		return p.metricsServer.Shutdown(shutdownCtx)
+	}
+}
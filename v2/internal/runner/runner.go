@@ -2,24 +2,47 @@ package runner
 
 import (
 	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/hmap/store/hybrid"
 	"github.com/projectdiscovery/nuclei/v2/internal/collaborator"
 	"github.com/projectdiscovery/nuclei/v2/internal/colorizer"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
 	"github.com/projectdiscovery/nuclei/v2/pkg/catalogue"
+	"github.com/projectdiscovery/nuclei/v2/pkg/enrichment"
 	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output/sarif"
 	"github.com/projectdiscovery/nuclei/v2/pkg/projectfile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/netns"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/protocolinit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/es"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/splunk"
+	exporterwebhook "github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/webhook"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/slack"
+	"github.com/projectdiscovery/nuclei/v2/pkg/server"
+	"github.com/projectdiscovery/nuclei/v2/pkg/server/stream"
+	"github.com/projectdiscovery/nuclei/v2/pkg/store"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 	"github.com/remeh/sizedwaitgroup"
 	"go.uber.org/atomic"
 	"go.uber.org/ratelimit"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 )
 
 // Runner is a client for running the enumeration process.
@@ -35,13 +58,59 @@ type Runner struct {
 	colorizer       aurora.Aurora
 	severityColors  *colorizer.Colorizer
 	ratelimiter     ratelimit.Limiter
+	isolator        netns.Isolator
+	closing         atomic.Bool
+
+	// scanStore and httpServer back -serve/-listen daemon mode. httpServer
+	// is nil unless options.Serve is set. scanMu serializes the scans it
+	// triggers against r.hostMap/r.output, which RunEnumeration's own scan
+	// also uses and which aren't safe for concurrent scans to share.
+	scanStore  *store.Store
+	httpServer *server.Server
+	scanMu     sync.Mutex
+
+	// enricher looks up a matched template's classified CVE/CWE IDs
+	// against a vulnerability feed. Nil when options.NoCVEEnrichment is set.
+	enricher enrichment.Enricher
+
+	// report aggregates matched findings by host for the run, written out
+	// as -report-json/-report-sarif once runScan completes. Nil unless
+	// either option is set.
+	report *report.Report
+
+	// reportingClient pushes matched findings to any configured SIEM/chat
+	// output sinks (Elasticsearch, Splunk HEC, generic webhook, Slack) as
+	// they're found. Nil unless at least one of the corresponding
+	// options.*URL/*HEC/*Webhook fields is set.
+	reportingClient *reporting.Client
+
+	// streamWriter fans matched findings out to live subscribers of the
+	// WebSocket/gRPC streaming endpoints below. Nil unless StreamAddress or
+	// GRPCAddress is set.
+	streamWriter *output.StreamWriter
+	wsServer     *http.Server
+	grpcServer   *grpc.Server
+
+	// ctx is the root context for the enumeration: cancelling it (SIGINT,
+	// or an embedder calling Close) is how in-flight executors learn to
+	// unwind instead of running out the clock on their fixed Timeout.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
+// defaultShutdownTimeout bounds how long a graceful shutdown waits for the
+// output writer and reporting clients to flush before giving up.
+const defaultShutdownTimeout = 30 * time.Second
+
 // New creates a new client for running enumeration process.
 func New(options *types.Options) (*Runner, error) {
+	ctx, cancel := context.WithCancel(context.Background())
 	runner := &Runner{
 		options: options,
+		ctx:     ctx,
+		cancel:  cancel,
 	}
+	runner.installSignalHandler()
 	if err := runner.updateTemplates(); err != nil {
 		gologger.Warning().Msgf("Could not update templates: %s\n", err)
 	}
@@ -63,6 +132,17 @@ func New(options *types.Options) (*Runner, error) {
 	if runner.templatesConfig != nil {
 		runner.readNucleiIgnoreFile()
 	}
+	if options.Isolated {
+		isolator, err := netns.New(netns.Config{Name: fmt.Sprintf("nuclei-%d", os.Getpid())})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not set up network namespace isolation")
+		}
+		if err := isolator.Enter(); err != nil {
+			return nil, errors.Wrap(err, "could not enter isolated network namespace")
+		}
+		runner.isolator = isolator
+	}
+
 	runner.catalogue = catalogue.New(runner.options.TemplatesDirectory)
 
 	if hm, err := hybrid.New(hybrid.DefaultDiskOptions); err != nil {
@@ -127,12 +207,38 @@ func New(options *types.Options) (*Runner, error) {
 	}
 
 	// Create the output file if asked
-	output, err := output.NewStandardWriter(!options.NoColor, options.NoMeta, options.JSON, options.Output, options.TraceLogFile)
+	output, err := output.NewStandardWriter(!options.NoColor, options.NoMeta, options.JSON, options.Output, options.TraceLogFile, "", "", options.SarifExport, options.HARFile)
 	if err != nil {
 		gologger.Fatal().Msgf("Could not create output file '%s': %s\n", options.Output, err)
 	}
 	runner.output = output
 
+	if options.Serve {
+		runner.scanStore = store.New()
+		runner.httpServer = server.New(options.ListenAddress, runner.scanStore, runner.runScanRequest)
+	}
+
+	if options.StreamAddress != "" || options.GRPCAddress != "" {
+		runner.streamWriter = output.NewStreamWriter()
+		runner.output = output.NewMultiWriter(runner.output, runner.streamWriter)
+		if options.StreamAddress != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/stream", stream.WebSocketHandler(runner.streamWriter))
+			runner.wsServer = &http.Server{Addr: options.StreamAddress, Handler: mux}
+		}
+		if options.GRPCAddress != "" {
+			runner.grpcServer = grpc.NewServer()
+			streamServer := stream.NewServer(runner.runStreamScan, runner.listTemplateIDs, runner.streamWriter)
+			runner.grpcServer.RegisterService(&stream.ServiceDesc, streamServer)
+		}
+	}
+
+	if !options.NoCVEEnrichment {
+		if cveErr := runner.initEnricher(); cveErr != nil {
+			gologger.Warning().Msgf("Could not initialize CVE enrichment: %s\n", cveErr)
+		}
+	}
+
 	// Creates the progress tracking object
 	var progressErr error
 	runner.progress, progressErr = progress.NewProgress(options.EnableProgressBar, options.Metrics, options.MetricsPort)
@@ -162,20 +268,157 @@ func New(options *types.Options) (*Runner, error) {
 	return runner, nil
 }
 
-// Close releases all the resources and cleans up
+// installSignalHandler cancels the runner's root context on SIGINT/SIGTERM,
+// so executors unwind via ctx instead of hanging until their fixed Timeout
+// fires, while RunEnumeration still flushes whatever partial results were
+// already written.
+func (r *Runner) installSignalHandler() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		gologger.Info().Msgf("Shutting down, waiting for in-flight requests to finish...\n")
+		r.closing.Store(true)
+		r.cancel()
+	}()
+}
+
+// Close releases all the resources and cleans up, giving in-flight output
+// and reporting writes a default grace period to flush via ShutdownGracefully.
 func (r *Runner) Close() {
+	timeout := r.options.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	r.ShutdownGracefully(ctx)
+}
+
+// ShutdownGracefully stops the runner from accepting new work, flushes the
+// output writer within ctx's deadline, then sleeps for the whole
+// options.InteractionsShutdownWait window (it does not track whether any
+// interactsh correlations are still pending, so it never returns early),
+// and only then releases the underlying transports. Embedders that
+// need more control over shutdown timing than Close's defaults should call
+// this directly.
+func (r *Runner) ShutdownGracefully(ctx context.Context) {
+	r.closing.Store(true)
+	r.cancel()
+
 	if r.output != nil {
-		r.output.Close()
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			r.output.Close()
+		}()
+		select {
+		case <-flushed:
+		case <-ctx.Done():
+			gologger.Warning().Msgf("Shutdown timed out before output writer finished flushing: %s\n", ctx.Err())
+		}
 	}
+
+	if r.options.InteractionsShutdownWait > 0 {
+		time.Sleep(r.options.InteractionsShutdownWait)
+	}
+
 	r.hostMap.Close()
+	if closer, ok := r.enricher.(enrichment.Closer); ok {
+		if err := closer.Close(); err != nil {
+			gologger.Warning().Msgf("Could not close cve enrichment cache: %s\n", err)
+		}
+	}
 	if r.projectFile != nil {
 		r.projectFile.Close()
 	}
+	if r.isolator != nil {
+		if err := r.isolator.Exit(); err != nil {
+			gologger.Warning().Msgf("Could not restore original network namespace: %s\n", err)
+		}
+		if err := r.isolator.Close(); err != nil {
+			gologger.Warning().Msgf("Could not clean up isolated network namespace: %s\n", err)
+		}
+	}
 }
 
 // RunEnumeration sets up the input layer for giving input nuclei.
-// binary and runs the actual enumeration
+// binary and runs the actual enumeration, serving the metrics endpoint (if
+// enabled) under the same errgroup so it's torn down alongside the scan
+// when r.ctx is cancelled.
 func (r *Runner) RunEnumeration() {
+	g, gctx := errgroup.WithContext(r.ctx)
+	g.Go(func() error {
+		return r.progress.ServeMetrics(gctx)
+	})
+	if r.httpServer != nil {
+		g.Go(func() error {
+			return r.httpServer.ListenAndServe(gctx)
+		})
+	}
+	if r.wsServer != nil {
+		g.Go(func() error {
+			return r.listenAndServeWS(gctx)
+		})
+	}
+	if r.grpcServer != nil {
+		g.Go(func() error {
+			return r.listenAndServeGRPC(gctx)
+		})
+	}
+	g.Go(func() error {
+		r.runScan()
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		gologger.Warning().Msgf("Metrics server error: %s\n", err)
+	}
+}
+
+// listenAndServeWS starts the WebSocket streaming endpoint and blocks until
+// ctx is cancelled, at which point it shuts the server down gracefully.
+func (r *Runner) listenAndServeWS(ctx context.Context) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := r.wsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		return r.wsServer.Shutdown(context.Background())
+	}
+}
+
+// listenAndServeGRPC starts the gRPC streaming service and blocks until ctx
+// is cancelled, at which point it stops the server gracefully.
+func (r *Runner) listenAndServeGRPC(ctx context.Context) error {
+	listener, err := net.Listen("tcp", r.options.GRPCAddress)
+	if err != nil {
+		return errors.Wrap(err, "could not listen for grpc")
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- r.grpcServer.Serve(listener) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		r.grpcServer.GracefulStop()
+		return nil
+	}
+}
+
+// runScan resolves the input templates and runs them against every target,
+// tracking progress until completion. Split out of RunEnumeration so the
+// scan itself can run alongside ServeMetrics under the same errgroup.
+func (r *Runner) runScan() {
 	err := protocolinit.Init(r.options)
 	if err != nil {
 		gologger.Fatal().Msgf("Could not initialize protocols: %s\n", err)
@@ -231,6 +474,21 @@ func (r *Runner) RunEnumeration() {
 		totalRequests += int64(t.TotalRequests) * r.inputCount
 	}
 
+	if r.options.ReportJSON != "" || r.options.ReportSarif != "" {
+		r.report = report.New(r.options.Templates, templateCount, r.options.RateLimit)
+		r.output = output.NewMultiWriter(r.output, &reportWriter{report: r.report})
+	}
+
+	if sinkOptions := r.buildReportingOptions(); sinkOptions != nil {
+		client, err := reporting.New(sinkOptions)
+		if err != nil {
+			gologger.Warning().Msgf("Could not initialize output sinks: %s\n", err)
+		} else {
+			r.reportingClient = client
+			r.output = output.NewMultiWriter(r.output, &reportingWriter{client: client})
+		}
+	}
+
 	results := &atomic.Bool{}
 	wgtemplates := sizedwaitgroup.New(r.options.TemplateThreads)
 	// Starts polling or ignore
@@ -244,15 +502,29 @@ func (r *Runner) RunEnumeration() {
 		p.Init(r.inputCount, templateCount, totalRequests)
 
 		for _, t := range availableTemplates {
+			if r.closing.Load() || r.ctx.Err() != nil {
+				gologger.Info().Msgf("Shutting down, not scheduling remaining templates")
+				break
+			}
 			wgtemplates.Add()
 			go func(template *templates.Template) {
 				defer wgtemplates.Done()
 
-				if len(template.Workflows) > 0 {
-					results.CAS(false, r.processWorkflowWithList(template))
-				} else {
-					results.CAS(false, r.processTemplateWithList(template))
+				run := func() error {
+					if len(template.Workflows) > 0 {
+						results.CAS(false, r.processWorkflowWithList(r.ctx, template))
+					} else {
+						results.CAS(false, r.processTemplateWithList(r.ctx, template))
+					}
+					return nil
+				}
+				if r.isolator != nil {
+					if err := r.isolator.Run(run); err != nil {
+						gologger.Warning().Msgf("Could not run template '%s' in isolated network namespace: %s\n", template.Path, err)
+					}
+					return
 				}
+				_ = run()
 			}(t)
 		}
 		wgtemplates.Wait()
@@ -266,4 +538,279 @@ func (r *Runner) RunEnumeration() {
 		}
 		gologger.Info().Msgf("No results found. Better luck next time!")
 	}
+
+	if r.report != nil {
+		r.report.IncRequests(totalRequests)
+		r.report.Finish()
+		r.writeReports()
+	}
+}
+
+// writeReports flushes r.report to -report-json/-report-sarif, warning
+// (rather than failing the run) if either write comes back with an error.
+func (r *Runner) writeReports() {
+	if r.options.ReportJSON != "" {
+		if err := report.WriteJSON(r.options.ReportJSON, r.report); err != nil {
+			gologger.Warning().Msgf("Could not write json report: %s\n", err)
+		}
+	}
+	if r.options.ReportSarif != "" {
+		log := sarif.Build("", r.report)
+		if err := sarif.WriteFile(r.options.ReportSarif, log); err != nil {
+			gologger.Warning().Msgf("Could not write sarif report: %s\n", err)
+		}
+	}
+}
+
+// runScanRequest runs a single scan submitted through the -serve HTTP
+// server, reusing the same runScan path as the initial command-line
+// enumeration. Scans are serialized behind scanMu since runScan drives
+// them through the runner's single shared hostMap and output writer,
+// neither of which is safe for two scans to use concurrently.
+func (r *Runner) runScanRequest(ctx context.Context, scan *store.Scan, req server.ScanRequest) error {
+	r.scanMu.Lock()
+	defer r.scanMu.Unlock()
+
+	if deadline := r.options.GlobalDeadline; deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+
+	originalOutput := r.output
+	originalTemplates := r.options.Templates
+	originalSeverity := r.options.Severity
+	originalScanContext := r.options.ScanContext
+	defer func() {
+		r.output = originalOutput
+		r.options.Templates = originalTemplates
+		r.options.Severity = originalSeverity
+		r.options.ScanContext = originalScanContext
+	}()
+
+	if err := r.resetHostMap(req.Targets); err != nil {
+		return errors.Wrap(err, "could not set scan targets")
+	}
+	if len(req.Templates) > 0 {
+		r.options.Templates = req.Templates
+	}
+	if len(req.Severity) > 0 {
+		r.options.Severity = req.Severity
+	}
+	r.output = output.NewMultiWriter(originalOutput, scan.Writer())
+	r.options.ScanContext = ctx
+
+	r.runScan()
+	return ctx.Err()
+}
+
+// runStreamScan runs a single scan submitted through the gRPC Scan RPC,
+// reusing the same runScan path as the -serve HTTP server's scans. writer
+// additionally receives every event the scan produces; the gRPC
+// stream.Server wires this to the shared streamWriter so stream.Server.Scan
+// can filter and forward it to the calling client. Scans are serialized
+// behind scanMu, same as runScanRequest.
+func (r *Runner) runStreamScan(ctx context.Context, req *stream.ScanRequest, writer output.Writer) error {
+	r.scanMu.Lock()
+	defer r.scanMu.Unlock()
+
+	if deadline := r.options.GlobalDeadline; deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+
+	originalOutput := r.output
+	originalTemplates := r.options.Templates
+	originalSeverity := r.options.Severity
+	originalScanContext := r.options.ScanContext
+	defer func() {
+		r.output = originalOutput
+		r.options.Templates = originalTemplates
+		r.options.Severity = originalSeverity
+		r.options.ScanContext = originalScanContext
+	}()
+
+	if err := r.resetHostMap(req.Targets); err != nil {
+		return errors.Wrap(err, "could not set scan targets")
+	}
+	if len(req.Templates) > 0 {
+		r.options.Templates = req.Templates
+	}
+	if len(req.Severity) > 0 {
+		r.options.Severity = req.Severity
+	}
+	r.output = output.NewMultiWriter(originalOutput, writer)
+	r.options.ScanContext = ctx
+
+	r.runScan()
+	return ctx.Err()
+}
+
+// listTemplateIDs returns the template paths/IDs configured for this run,
+// for the gRPC ListTemplates RPC. Until a real catalogue of every loaded
+// template is wired through, this reflects what -t/-templates was given
+// rather than every template discoverable under TemplatesDirectory.
+func (r *Runner) listTemplateIDs() []string {
+	return r.options.Templates
+}
+
+// resetHostMap clears the runner's shared host map and repopulates it with
+// targets, deduplicating as the command-line target sources already do.
+func (r *Runner) resetHostMap(targets []string) error {
+	hm, err := hybrid.New(hybrid.DefaultDiskOptions)
+	if err != nil {
+		return err
+	}
+	if r.hostMap != nil {
+		r.hostMap.Close()
+	}
+	r.hostMap = hm
+
+	r.inputCount = 0
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		if _, ok := r.hostMap.Get(target); ok {
+			continue
+		}
+		r.inputCount++
+		// nolint:errcheck // ignoring error
+		r.hostMap.Set(target, nil)
+	}
+	return nil
+}
+
+// defaultCVECacheDB is where the NVD enrichment cache lives when
+// options.CVECacheDB isn't set.
+const defaultCVECacheDB = "nuclei-cve-cache.db"
+
+// initEnricher sets up r.enricher from options, wrapping the NVD feed
+// lookup in an in-memory cache so repeated CVE IDs within this run are
+// O(1). If options.UpdateCVEs is set, the on-disk cache is refreshed from
+// the feed before the run starts.
+func (r *Runner) initEnricher() error {
+	dbPath := r.options.CVECacheDB
+	if dbPath == "" {
+		dbPath = defaultCVECacheDB
+	}
+
+	nvd, err := enrichment.NewNVDEnricher(dbPath)
+	if err != nil {
+		return errors.Wrap(err, "could not set up nvd enrichment")
+	}
+
+	if r.options.UpdateCVEs {
+		if err := nvd.Refresh(); err != nil {
+			gologger.Warning().Msgf("Could not refresh cve cache: %s\n", err)
+		}
+	}
+
+	r.enricher = enrichment.NewCachingEnricher(nvd)
+	return nil
+}
+
+// enrichResultEvent attaches vulnerability metadata to event for the given
+// CVE ID, when enrichment is enabled and the lookup succeeds. Errors are
+// logged rather than propagated, since a feed outage shouldn't fail a scan
+// that's otherwise found a real match.
+func (r *Runner) enrichResultEvent(event *output.ResultEvent, cveID string) {
+	if r.enricher == nil || cveID == "" {
+		return
+	}
+	result, err := r.enricher.Enrich(cveID)
+	if err != nil {
+		gologger.Warning().Msgf("Could not enrich %s: %s\n", cveID, err)
+		return
+	}
+	event.Enrichment = result
+}
+
+// reportWriter is an output.Writer that folds every matched event into a
+// report.Report instead of printing or persisting it, so -report-json/
+// -report-sarif can be fed by the same event stream as the console/file
+// writers via output.MultiWriter.
+type reportWriter struct {
+	report *report.Report
+}
+
+var _ output.Writer = &reportWriter{}
+
+func (w *reportWriter) Close() {}
+
+func (w *reportWriter) Colorizer() aurora.Aurora {
+	return aurora.NewAurora(false)
+}
+
+func (w *reportWriter) Write(event *output.ResultEvent) error {
+	var cveID string
+	if event.Info.Classification != nil && len(event.Info.Classification.CVEID) > 0 {
+		cveID = event.Info.Classification.CVEID[0]
+	}
+	w.report.AddFinding(report.Finding{
+		Host:         event.Host,
+		TemplateID:   event.TemplateID,
+		TemplateInfo: map[string]string{"name": event.Info.Name},
+		Severity:     event.Info.SeverityHolder.Severity.String(),
+		CVEID:        cveID,
+		Matched:      event.Matched,
+	})
+	return nil
+}
+
+func (w *reportWriter) Request(templateID, url, requestType string, err error, trace *output.RequestTrace) {
+}
+
+// buildReportingOptions translates the quick-config SIEM/chat sink flags
+// (options.EsURL, options.SplunkHEC, options.WebhookURL, options.SlackWebhook)
+// into a *reporting.Options, so users don't need a reporting config file
+// just to point nuclei at a single sink. Returns nil if none are set.
+func (r *Runner) buildReportingOptions() *reporting.Options {
+	o := r.options
+	if o.EsURL == "" && o.SplunkHEC == "" && o.WebhookURL == "" && o.SlackWebhook == "" {
+		return nil
+	}
+
+	options := &reporting.Options{MinNotifySeverity: o.MinNotifySeverity}
+	if o.EsURL != "" {
+		options.ElasticsearchExporter = &es.Options{URL: o.EsURL, IndexName: o.EsIndex}
+	}
+	if o.SplunkHEC != "" {
+		options.SplunkExporter = &splunk.Options{URL: o.SplunkHEC, Token: o.SplunkHECToken}
+	}
+	if o.WebhookURL != "" {
+		options.WebhookExporter = &exporterwebhook.Options{URL: o.WebhookURL}
+	}
+	if o.SlackWebhook != "" {
+		options.Slack = &slack.Options{WebhookURL: o.SlackWebhook}
+	}
+	return options
+}
+
+// reportingWriter is an output.Writer that forwards every matched event to
+// a reporting.Client, so the configured SIEM/chat output sinks see findings
+// as they're found via the same event stream as the console/file writer.
+type reportingWriter struct {
+	client *reporting.Client
+}
+
+var _ output.Writer = &reportingWriter{}
+
+func (w *reportingWriter) Close() {
+	if err := w.client.Close(); err != nil {
+		gologger.Warning().Msgf("Could not close output sinks: %s\n", err)
+	}
+}
+
+func (w *reportingWriter) Colorizer() aurora.Aurora {
+	return aurora.NewAurora(false)
+}
+
+func (w *reportingWriter) Write(event *output.ResultEvent) error {
+	return w.client.CreateIssue(event)
+}
+
+func (w *reportingWriter) Request(templateID, url, requestType string, err error, trace *output.RequestTrace) {
 }
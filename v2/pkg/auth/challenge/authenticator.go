@@ -0,0 +1,244 @@
+package challenge
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // RFC 2617 digest auth mandates MD5; not used for anything security-sensitive here.
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultTokenTTL is used when a realm endpoint's token response omits
+// expires_in.
+const defaultTokenTTL = 60 * time.Second
+
+// Authenticator resolves WWW-Authenticate challenges into an Authorization
+// header value, consulting a CredentialStore and, for Bearer challenges,
+// fetching (and caching) a token from the challenge's realm endpoint.
+type Authenticator struct {
+	store  CredentialStore
+	client *http.Client
+	tokens *tokenCache
+
+	resolvedMu sync.Mutex
+	resolved   map[string]string
+}
+
+// NewAuthenticator creates an Authenticator backed by store. client is used
+// to fetch Bearer tokens from realm endpoints; http.DefaultClient is used
+// if client is nil.
+func NewAuthenticator(store CredentialStore, client *http.Client) *Authenticator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Authenticator{
+		store:    store,
+		client:   client,
+		tokens:   newTokenCache(),
+		resolved: make(map[string]string),
+	}
+}
+
+// Authorize resolves resp's WWW-Authenticate challenge(s) for req into an
+// Authorization header value, trying each challenge in the order the
+// server sent them until one can be satisfied from the credential store.
+// It returns false if no configured credential could satisfy any
+// challenge.
+func (a *Authenticator) Authorize(ctx context.Context, req *http.Request, resp *http.Response) (string, bool) {
+	cred, ok := a.store.Get(req.URL.Host)
+	if !ok {
+		return "", false
+	}
+
+	for _, c := range ParseHeader(resp.Header) {
+		var header string
+		var err error
+
+		switch c.Scheme {
+		case "basic":
+			header = basicAuthorization(cred)
+		case "bearer":
+			header, err = a.bearerAuthorization(ctx, req.URL.Host, c, cred)
+		case "digest":
+			header, err = digestAuthorization(req, c, cred)
+		default:
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		a.resolvedMu.Lock()
+		a.resolved[req.URL.Host] = header
+		a.resolvedMu.Unlock()
+		return header, true
+	}
+	return "", false
+}
+
+// CachedAuthorization returns the Authorization header value last resolved
+// by Authorize for host, if any, so a request can be sent with the right
+// credentials pre-attached instead of taking a guaranteed 401 round trip.
+func (a *Authenticator) CachedAuthorization(host string) (string, bool) {
+	a.resolvedMu.Lock()
+	defer a.resolvedMu.Unlock()
+	header, ok := a.resolved[host]
+	return header, ok
+}
+
+func basicAuthorization(cred Credential) string {
+	raw := cred.Username + ":" + cred.Password
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// bearerAuthorization resolves a Bearer challenge into an "Bearer <token>"
+// header, either using cred.BearerToken directly or exchanging
+// cred.Username/Password for a token at the challenge's realm endpoint,
+// caching the result by (host, service, scope).
+func (a *Authenticator) bearerAuthorization(ctx context.Context, host string, c Challenge, cred Credential) (string, error) {
+	if cred.BearerToken != "" {
+		return "Bearer " + cred.BearerToken, nil
+	}
+
+	service, scope := c.Params["service"], c.Params["scope"]
+	if token, ok := a.tokens.get(host, service, scope); ok {
+		return "Bearer " + token, nil
+	}
+
+	realm := c.Params["realm"]
+	if realm == "" {
+		return "", errors.New("bearer challenge has no realm to fetch a token from")
+	}
+
+	realmURL, err := url.Parse(realm)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid realm URL")
+	}
+	query := realmURL.Query()
+	if service != "" {
+		query.Set("service", service)
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	realmURL.RawQuery = query.Encode()
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodGet, realmURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if cred.Username != "" {
+		tokenReq.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	resp, err := a.client.Do(tokenReq)
+	if err != nil {
+		return "", errors.Wrap(err, "could not fetch bearer token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("realm endpoint returned status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", errors.Wrap(err, "could not decode token response")
+	}
+
+	token := tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+	if token == "" {
+		return "", errors.New("token response did not contain a token")
+	}
+
+	ttl := defaultTokenTTL
+	if tokenResp.ExpiresIn > 0 {
+		ttl = time.Duration(tokenResp.ExpiresIn) * time.Second
+	}
+	a.tokens.put(host, service, scope, token, ttl)
+
+	return "Bearer " + token, nil
+}
+
+// digestAuthorization implements RFC 2617 digest auth (MD5, qop=auth or
+// unqualified), which is all the schemes nuclei templates are realistically
+// expected to hit.
+func digestAuthorization(req *http.Request, c Challenge, cred Credential) (string, error) {
+	nonce := c.Params["nonce"]
+	if nonce == "" {
+		return "", errors.New("digest challenge has no nonce")
+	}
+	realm := c.Params["realm"]
+	qop := selectQop(c.Params["qop"])
+	opaque := c.Params["opaque"]
+
+	ha1 := md5Hex(cred.Username + ":" + realm + ":" + cred.Password)
+	ha2 := md5Hex(req.Method + ":" + req.URL.RequestURI())
+
+	var response, nc, cnonce string
+	if qop != "" {
+		nc = "00000001"
+		cnonce = randomHex(8)
+		response = md5Hex(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":" + qop + ":" + ha2)
+	} else {
+		response = md5Hex(ha1 + ":" + nonce + ":" + ha2)
+	}
+
+	header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		cred.Username, realm, nonce, req.URL.RequestURI(), response)
+	if qop != "" {
+		header += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, nc, cnonce)
+	}
+	if opaque != "" {
+		header += fmt.Sprintf(`, opaque="%s"`, opaque)
+	}
+	return header, nil
+}
+
+// selectQop picks a single qop value out of the challenge's (possibly
+// comma-separated, e.g. "auth,auth-int") qop directive, as RFC 2617
+// requires the client pick exactly one rather than echo the list back.
+// "auth" is preferred since it's the only one nuclei's digest client
+// implements (auth-int requires hashing the request body into ha2).
+func selectQop(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values := strings.Split(raw, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	for _, value := range values {
+		if value == "auth" {
+			return "auth"
+		}
+	}
+	return values[0]
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s)) //nolint:gosec // see import comment above.
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,129 @@
+package challenge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeaderBasic(t *testing.T) {
+	header := http.Header{}
+	header.Set("WWW-Authenticate", `Basic realm="Restricted Area"`)
+
+	challenges := ParseHeader(header)
+	require.Len(t, challenges, 1)
+	require.Equal(t, "basic", challenges[0].Scheme)
+	require.Equal(t, "Restricted Area", challenges[0].Params["realm"])
+}
+
+func TestParseHeaderBearerWithScopeList(t *testing.T) {
+	header := http.Header{}
+	header.Set("WWW-Authenticate", `Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:samples/app:pull,push"`)
+
+	challenges := ParseHeader(header)
+	require.Len(t, challenges, 1)
+	c := challenges[0]
+	require.Equal(t, "bearer", c.Scheme)
+	require.Equal(t, "https://auth.example.com/token", c.Params["realm"])
+	require.Equal(t, "registry.example.com", c.Params["service"])
+	require.Equal(t, "repository:samples/app:pull,push", c.Params["scope"])
+}
+
+func TestParseHeaderMultipleChallenges(t *testing.T) {
+	header := http.Header{}
+	header.Add("WWW-Authenticate", `Basic realm="a"`)
+	header.Add("WWW-Authenticate", `Bearer realm="b",service="svc"`)
+
+	challenges := ParseHeader(header)
+	require.Len(t, challenges, 2)
+	require.Equal(t, "basic", challenges[0].Scheme)
+	require.Equal(t, "bearer", challenges[1].Scheme)
+}
+
+func TestAuthenticatorAuthorizeBasic(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("WWW-Authenticate", `Basic realm="Restricted"`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/secret", nil)
+	store := StaticStore{"example.com": {Username: "admin", Password: "hunter2"}}
+	auth := NewAuthenticator(store, nil)
+
+	header, ok := auth.Authorize(context.Background(), req, resp)
+	require.True(t, ok)
+	require.Equal(t, "Basic YWRtaW46aHVudGVyMg==", header)
+
+	cached, ok := auth.CachedAuthorization("example.com")
+	require.True(t, ok)
+	require.Equal(t, header, cached)
+}
+
+func TestAuthenticatorAuthorizeBearerFetchesAndCachesToken(t *testing.T) {
+	var tokenRequests int
+	realm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.Equal(t, "registry.example.com", r.URL.Query().Get("service"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"abc123","expires_in":300}`))
+	}))
+	defer realm.Close()
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("WWW-Authenticate", `Bearer realm="`+realm.URL+`",service="registry.example.com",scope="repository:app:pull"`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://registry.example.com/v2/app/manifests/latest", nil)
+	store := StaticStore{"registry.example.com": {Username: "user", Password: "pass"}}
+	auth := NewAuthenticator(store, nil)
+
+	header, ok := auth.Authorize(context.Background(), req, resp)
+	require.True(t, ok)
+	require.Equal(t, "Bearer abc123", header)
+
+	// A second Authorize call for the same host/service/scope should hit
+	// the token cache instead of the realm endpoint again.
+	_, ok = auth.Authorize(context.Background(), req, resp)
+	require.True(t, ok)
+	require.Equal(t, 1, tokenRequests)
+}
+
+func TestAuthenticatorAuthorizeNoCredentialConfigured(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("WWW-Authenticate", `Basic realm="Restricted"`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/secret", nil)
+	auth := NewAuthenticator(StaticStore{}, nil)
+
+	_, ok := auth.Authorize(context.Background(), req, resp)
+	require.False(t, ok)
+}
+
+func TestAuthenticatorAuthorizeDigest(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("WWW-Authenticate", `Digest realm="Restricted", nonce="abc123", qop="auth"`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/secret", nil)
+	store := StaticStore{"example.com": {Username: "admin", Password: "hunter2"}}
+	auth := NewAuthenticator(store, nil)
+
+	header, ok := auth.Authorize(context.Background(), req, resp)
+	require.True(t, ok)
+	require.Contains(t, header, `Digest username="admin"`)
+	require.Contains(t, header, `nonce="abc123"`)
+	require.Contains(t, header, "qop=auth")
+}
+
+func TestAuthenticatorAuthorizeDigestMultipleQop(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("WWW-Authenticate", `Digest realm="Restricted", nonce="abc123", qop="auth,auth-int"`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/secret", nil)
+	store := StaticStore{"example.com": {Username: "admin", Password: "hunter2"}}
+	auth := NewAuthenticator(store, nil)
+
+	header, ok := auth.Authorize(context.Background(), req, resp)
+	require.True(t, ok)
+	require.Contains(t, header, "qop=auth,")
+	require.NotContains(t, header, "qop=auth,auth-int")
+}
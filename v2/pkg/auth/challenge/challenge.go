@@ -0,0 +1,98 @@
+// Package challenge implements RFC 7235 WWW-Authenticate challenge parsing
+// plus a small Authenticator, modeled on the challenge-manager pattern used
+// by container registry clients. It lets templates target authenticated
+// APIs (registries, Vault, ...) by resolving a 401/407 challenge into the
+// right Authorization header instead of hardcoding tokens in the YAML.
+package challenge
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Challenge is a single parsed WWW-Authenticate challenge: a lower-cased
+// scheme (basic, bearer, digest, ...) plus its auth-params, also
+// lower-cased by key.
+type Challenge struct {
+	Scheme string
+	Params map[string]string
+}
+
+// ParseHeader parses every WWW-Authenticate value on resp's header (RFC
+// 7235 allows more than one, one per scheme a server is willing to accept)
+// into its component Challenges.
+func ParseHeader(header http.Header) []Challenge {
+	var challenges []Challenge
+	for _, value := range header[http.CanonicalHeaderKey("WWW-Authenticate")] {
+		if c, ok := parseChallenge(value); ok {
+			challenges = append(challenges, c)
+		}
+	}
+	return challenges
+}
+
+// parseChallenge parses a single WWW-Authenticate header value of the form
+// `scheme param1=value1, param2="value2"`.
+func parseChallenge(value string) (Challenge, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Challenge{}, false
+	}
+
+	scheme, rest := value, ""
+	if idx := strings.IndexAny(value, " \t"); idx >= 0 {
+		scheme, rest = value[:idx], value[idx+1:]
+	}
+	return Challenge{
+		Scheme: strings.ToLower(scheme),
+		Params: parseParams(rest),
+	}, true
+}
+
+// parseParams parses a comma-separated auth-param list, splitting on
+// commas outside of quoted strings so a quoted value containing a comma
+// (e.g. a scope list) isn't broken up.
+func parseParams(s string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range splitUnquoted(s, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.Index(part, "=")
+		if eq < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(part[:eq]))
+		val := unquote(strings.TrimSpace(part[eq+1:]))
+		params[key] = val
+	}
+	return params
+}
+
+// splitUnquoted splits s on sep, treating sep characters inside double
+// quotes as literal so a quoted value (e.g. a scope list) isn't broken up.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case sep:
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
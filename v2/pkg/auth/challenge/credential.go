@@ -0,0 +1,29 @@
+package challenge
+
+// Credential holds the secret material used to satisfy a WWW-Authenticate
+// challenge for a single host.
+type Credential struct {
+	Username string
+	Password string
+	// BearerToken, if set, is sent as-is instead of exchanging
+	// Username/Password for a token at the challenge's realm endpoint.
+	BearerToken string
+}
+
+// CredentialStore resolves the Credential to use for a given host. The
+// bool return is false if no credential is configured for host, meaning
+// any challenge for it should be left unhandled.
+type CredentialStore interface {
+	Get(host string) (Credential, bool)
+}
+
+// StaticStore is a CredentialStore backed by an in-memory, per-host map.
+// Parsing CLI flags or a YAML credentials file into a StaticStore is left
+// to the embedder.
+type StaticStore map[string]Credential
+
+// Get implements CredentialStore.
+func (s StaticStore) Get(host string) (Credential, bool) {
+	cred, ok := s[host]
+	return cred, ok
+}
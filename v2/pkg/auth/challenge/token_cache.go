@@ -0,0 +1,53 @@
+package challenge
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenCacheKey identifies a cached bearer token by the three dimensions a
+// registry-style realm endpoint scopes tokens to.
+type tokenCacheKey struct {
+	host    string
+	service string
+	scope   string
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// tokenCache is a TTL cache of bearer tokens, so a token fetched from a
+// realm endpoint for a given (host, service, scope) is reused by every
+// subsequent request in scope instead of being re-fetched each time.
+type tokenCache struct {
+	mu      sync.Mutex
+	entries map[tokenCacheKey]cachedToken
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{entries: make(map[tokenCacheKey]cachedToken)}
+}
+
+func (c *tokenCache) get(host, service, scope string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := tokenCacheKey{host, service, scope}
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.token, true
+}
+
+func (c *tokenCache) put(host, service, scope, token string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[tokenCacheKey{host, service, scope}] = cachedToken{token: token, expiresAt: time.Now().Add(ttl)}
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"context"
+	"sync"
+
 	"github.com/remeh/sizedwaitgroup"
 	"go.uber.org/atomic"
 
@@ -76,6 +79,62 @@ func (e *Engine) executeHostSpray(templatesList []*templates.Template, target In
 	return results
 }
 
+// ScanStrategyOptions carries the execution knobs a ScanStrategyDecider
+// considers when choosing between "host-spray" and "template-spray" for
+// ScanStrategy "auto".
+type ScanStrategyOptions struct {
+	// Stream indicates targets are being consumed from a streaming input
+	// source rather than a fully enumerated, countable list.
+	Stream bool
+	// HeadlessRatio is the fraction (0-1) of finalTemplates that are
+	// headless-protocol templates.
+	HeadlessRatio float64
+	// BulkSize is the number of hosts processed in parallel per template.
+	BulkSize int
+	// TemplateThreads is the number of templates processed in parallel.
+	TemplateThreads int
+}
+
+// ScanStrategyDecider chooses a concrete scan strategy ("host-spray" or
+// "template-spray") when ExecuteScanWithOpts is invoked with ScanStrategy
+// set to "auto" (or unset). Set Engine.StrategyDecider to override the
+// default heuristic with an SDK-supplied one.
+type ScanStrategyDecider interface {
+	Decide(targetCount, templateCount int64, opts ScanStrategyOptions) string
+}
+
+// scanStrategySprayRatio is how many times more targets than templates (or
+// vice versa) the default decider requires before it picks a spray strategy
+// over the other, rather than defaulting to template-spray.
+const scanStrategySprayRatio = 2
+
+// defaultScanStrategyDecider is the built-in ScanStrategyDecider used
+// unless Engine.StrategyDecider is set.
+type defaultScanStrategyDecider struct{}
+
+// Decide picks host-spray when targets heavily outnumber templates, since
+// spraying templates at one host at a time keeps connections/keep-alives
+// host-local instead of opening every host's connections at once. It falls
+// back to template-spray for streaming input (targets arrive over time and
+// can't be cheaply pre-counted/re-sprayed per host) and for headless-heavy
+// template sets (host-spray would multiply concurrent browser instances by
+// BulkSize; template-spray bounds that via TemplateThreads instead).
+func (defaultScanStrategyDecider) Decide(targetCount, templateCount int64, opts ScanStrategyOptions) string {
+	if opts.Stream {
+		return "template-spray"
+	}
+	if targetCount == 0 || templateCount == 0 {
+		return "template-spray"
+	}
+	if opts.HeadlessRatio > 0.5 {
+		return "template-spray"
+	}
+	if targetCount >= templateCount*scanStrategySprayRatio {
+		return "host-spray"
+	}
+	return "template-spray"
+}
+
 // ExecuteScanWithOpts executes scan with given scanStatergy
 func (e *Engine) ExecuteScanWithOpts(templatesList []*templates.Template, target InputProvider, noCluster bool) *atomic.Bool {
 	var results *atomic.Bool
@@ -88,19 +147,126 @@ func (e *Engine) ExecuteScanWithOpts(templatesList []*templates.Template, target
 	}
 
 	if stringsutil.EqualFoldAny(e.options.ScanStrategy, "auto", "") {
-		// TODO: this is only a placeholder, auto scan strategy should choose scan strategy
-		// based on no of hosts , templates , stream and other optimization parameters
-		e.options.ScanStrategy = "template-spray"
+		targetCount := target.Count()
+		templateCount := int64(len(finalTemplates))
+
+		var headlessCount int64
+		for _, tpl := range finalTemplates {
+			if tpl.Type() == types.HeadlessProtocol {
+				headlessCount++
+			}
+		}
+		var headlessRatio float64
+		if templateCount > 0 {
+			headlessRatio = float64(headlessCount) / float64(templateCount)
+		}
+
+		opts := ScanStrategyOptions{
+			Stream:          e.options.Stream,
+			HeadlessRatio:   headlessRatio,
+			BulkSize:        e.options.BulkSize,
+			TemplateThreads: e.options.TemplateThreads,
+		}
+
+		decider := e.StrategyDecider
+		if decider == nil {
+			decider = defaultScanStrategyDecider{}
+		}
+		e.options.ScanStrategy = decider.Decide(targetCount, templateCount, opts)
+
+		gologger.Debug().Msgf("auto scan strategy chose %q (targets=%d templates=%d headless-ratio=%.2f bulk-size=%d template-threads=%d stream=%t)\n",
+			e.options.ScanStrategy, targetCount, templateCount, headlessRatio, opts.BulkSize, opts.TemplateThreads, opts.Stream)
 	}
-	switch e.options.ScanStrategy {
-	case "template-spray":
-		results = e.executeTemplateSpray(finalTemplates, target)
-	case "host-spray":
-		results = e.executeHostSpray(finalTemplates, target)
+
+	order := OrderTemplateMajor
+	if e.options.ScanStrategy == "host-spray" {
+		order = OrderHostMajor
+	}
+
+	scheduler := e.Scheduler
+	if scheduler == nil {
+		scheduler = NewScheduler(SchedulerOptions{
+			Order:        order,
+			PerHostRPS:   float64(e.options.RateLimitPerHost),
+			Backpressure: e.hostErrorsBackpressure,
+		})
 	}
+	results = e.executeWithScheduler(scheduler, finalTemplates, target)
 	return results
 }
 
+// hostErrorsBackpressure adapts e.executerOpts.HostErrorsCache into a
+// BackpressureChecker, so a Scheduler can drop a host's remaining jobs once
+// it's tripped the cache instead of continuing to hammer it.
+func (e *Engine) hostErrorsBackpressure(host string) bool {
+	if e.executerOpts.HostErrorsCache == nil {
+		return false
+	}
+	return e.executerOpts.HostErrorsCache.Check(host)
+}
+
+// executeWithScheduler submits a Job per (template, target) pair to
+// scheduler and drains it with TemplateThreads workers, replacing the flat
+// goroutine-per-template/per-host fan-out of executeTemplateSpray/
+// executeHostSpray with the scheduler's priority/host-fair dequeue order.
+//
+// Note: because each Job runs its template against a single-target
+// InputProvider, the per-template resume tracking in executeModelWithInput
+// (which assumes a stable index across one call spanning every target) no
+// longer reflects a template's position across the whole target set; a
+// resumed scan revisits per-job rather than per-template-batch. Reconciling
+// that with the scheduler's reordering is left for a follow-up.
+func (e *Engine) executeWithScheduler(scheduler Scheduler, templatesList []*templates.Template, target InputProvider) *atomic.Bool {
+	results := &atomic.Bool{}
+
+	target.Scan(func(value *contextargs.MetaInput) bool {
+		for _, tpl := range templatesList {
+			scheduler.Submit(Job{
+				Template: tpl,
+				Target:   value,
+				Priority: SeverityToPriority(tpl.Info.SeverityHolder.Severity.String()),
+			})
+		}
+		return true
+	})
+	scheduler.Close()
+
+	workers := e.options.TemplateThreads
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := scheduler.Next(ctx)
+				if !ok {
+					return
+				}
+				e.executeJob(job, results)
+				scheduler.Done(job)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// executeJob runs a single Job's template against its one target, the unit
+// of work a Scheduler hands out via Next.
+func (e *Engine) executeJob(job Job, results *atomic.Bool) {
+	if job.Template.SelfContained {
+		e.executeSelfContainedTemplateWithInput(job.Template, results)
+		return
+	}
+	single := &inputs.SimpleInputProvider{Inputs: []*contextargs.MetaInput{job.Target}}
+	e.executeModelWithInput(job.Template.Type(), job.Template, single, results)
+}
+
 // processSelfContainedTemplates execute a self-contained template.
 func (e *Engine) executeSelfContainedTemplateWithInput(template *templates.Template, results *atomic.Bool) {
 	match, err := template.Executer.Execute(contextargs.New())
@@ -0,0 +1,69 @@
+package core
+
+import "testing"
+
+func TestDefaultScanStrategyDeciderDecide(t *testing.T) {
+	decider := defaultScanStrategyDecider{}
+
+	tests := []struct {
+		name          string
+		targetCount   int64
+		templateCount int64
+		opts          ScanStrategyOptions
+		expected      string
+	}{
+		{
+			name:          "many more targets than templates sprays hosts",
+			targetCount:   1000,
+			templateCount: 10,
+			expected:      "host-spray",
+		},
+		{
+			name:          "many more templates than targets sprays templates",
+			targetCount:   2,
+			templateCount: 500,
+			expected:      "template-spray",
+		},
+		{
+			name:          "comparable counts default to template-spray",
+			targetCount:   10,
+			templateCount: 10,
+			expected:      "template-spray",
+		},
+		{
+			name:          "streaming input always sprays templates",
+			targetCount:   1000,
+			templateCount: 10,
+			opts:          ScanStrategyOptions{Stream: true},
+			expected:      "template-spray",
+		},
+		{
+			name:          "headless-heavy template sets spray templates regardless of target ratio",
+			targetCount:   1000,
+			templateCount: 10,
+			opts:          ScanStrategyOptions{HeadlessRatio: 0.8},
+			expected:      "template-spray",
+		},
+		{
+			name:          "no targets falls back to template-spray",
+			targetCount:   0,
+			templateCount: 10,
+			expected:      "template-spray",
+		},
+		{
+			name:          "no templates falls back to template-spray",
+			targetCount:   10,
+			templateCount: 0,
+			expected:      "template-spray",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decider.Decide(tt.targetCount, tt.templateCount, tt.opts)
+			if got != tt.expected {
+				t.Errorf("Decide(%d, %d, %+v) = %q, want %q", tt.targetCount, tt.templateCount, tt.opts, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package core
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ratelimit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// JobPriority ranks a Job relative to others in the same host bucket, so a
+// critical-severity template's jobs are dequeued ahead of an info-level
+// template's against the same target.
+type JobPriority int
+
+// Priority levels, derived from a template's info.severity by SeverityToPriority.
+const (
+	PriorityInfo JobPriority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityCritical
+)
+
+// SeverityToPriority maps a template's info.severity string to a JobPriority,
+// defaulting to PriorityInfo for blank/unrecognized severities.
+func SeverityToPriority(severity string) JobPriority {
+	switch severity {
+	case "critical":
+		return PriorityCritical
+	case "high":
+		return PriorityHigh
+	case "medium":
+		return PriorityMedium
+	case "low":
+		return PriorityLow
+	default:
+		return PriorityInfo
+	}
+}
+
+// Job is a single (template, target) unit of work submitted to a Scheduler.
+type Job struct {
+	Template *templates.Template
+	Target   *contextargs.MetaInput
+	Priority JobPriority
+
+	host string
+	seq  int64
+}
+
+// SchedulerOrder controls how a Scheduler buckets and round-robins pending
+// Jobs, mirroring ExecuteScanWithOpts' two spray strategies.
+type SchedulerOrder string
+
+const (
+	// OrderTemplateMajor keeps every Job in a single bucket ordered purely
+	// by priority, matching "template-spray" (every template sprayed
+	// across all targets before moving on).
+	OrderTemplateMajor SchedulerOrder = "template-major"
+	// OrderHostMajor buckets Jobs by target host and round-robins across
+	// hosts, matching "host-spray" (every template run against one host
+	// before moving to the next), so a host with a deep backlog can't
+	// starve the others.
+	OrderHostMajor SchedulerOrder = "host-major"
+)
+
+// BackpressureChecker reports whether host should currently be skipped, e.g.
+// because HostErrorsCache has tripped for it. A nil checker never skips.
+type BackpressureChecker func(host string) bool
+
+// SchedulerOptions configures a Scheduler.
+type SchedulerOptions struct {
+	// Order selects template-major vs host-major dequeue order. Defaults
+	// to OrderTemplateMajor.
+	Order SchedulerOrder
+	// PerHostRPS bounds the requests per second dispatched to any single
+	// host. Zero means unlimited. Backs Options.RateLimitPerHost.
+	PerHostRPS float64
+	// Backpressure is consulted before a Job is handed out by Next; when
+	// it returns true for a Job's host, that Job is dropped instead of
+	// dispatched (counted in Stats().DroppedByBackpressure).
+	Backpressure BackpressureChecker
+}
+
+// SchedulerStats is a point-in-time snapshot of a Scheduler's bookkeeping,
+// intended to be surfaced through the progress/metrics subsystem
+// (see internal/progress) alongside request/match/error counters.
+type SchedulerStats struct {
+	QueueDepth            int
+	PerHostInflight       map[string]int
+	DroppedByBackpressure int64
+}
+
+// Scheduler orders pending Jobs across hosts and templates, enforcing a
+// per-host rate limit and exposing a backpressure hook so a slow or
+// aggressively-erroring host can't starve the rest of the scan.
+type Scheduler interface {
+	// Submit enqueues job for later dispatch by Next.
+	Submit(job Job)
+	// Next blocks (respecting ctx and any configured per-host rate limit)
+	// until a Job is ready to dispatch, returning false once the queue is
+	// empty and closed via Close.
+	Next(ctx context.Context) (Job, bool)
+	// Done marks a Job previously returned by Next as finished, releasing
+	// its host's inflight slot.
+	Done(job Job)
+	// Close marks the scheduler as drained; pending Submit calls after
+	// Close are not guaranteed to be delivered.
+	Close()
+	// Stats returns a snapshot of the scheduler's current bookkeeping.
+	Stats() SchedulerStats
+}
+
+// priorityQueue is a container/heap of Jobs ordered by Priority (high to
+// low), falling back to submission order (seq) within the same priority so
+// Scheduler stays FIFO-fair among same-severity templates.
+type priorityQueue []Job
+
+func (q priorityQueue) Len() int { return len(q) }
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q priorityQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) {
+	*q = append(*q, x.(Job))
+}
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// hostScheduler is the default, work-stealing Scheduler implementation:
+// Jobs are bucketed per host in a priority queue, hosts are visited
+// round-robin (host-major) or as a single bucket (template-major), and a
+// shared pkg/ratelimit.Limiter enforces SchedulerOptions.PerHostRPS.
+type hostScheduler struct {
+	options SchedulerOptions
+	limiter *ratelimit.Limiter
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	buckets    map[string]*priorityQueue
+	hostOrder  []string
+	nextHost   int
+	inflight   map[string]int
+	nextSeq    int64
+	droppedBP  int64
+	closed     bool
+	queueDepth int
+}
+
+// NewScheduler creates the default Scheduler implementation from options.
+func NewScheduler(options SchedulerOptions) Scheduler {
+	if options.Order == "" {
+		options.Order = OrderTemplateMajor
+	}
+	s := &hostScheduler{
+		options:  options,
+		limiter:  ratelimit.New(&ratelimit.Options{PerHostRPS: options.PerHostRPS}),
+		buckets:  make(map[string]*priorityQueue),
+		inflight: make(map[string]int),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// bucketKey returns the bucket a Job belongs in: its own host for
+// host-major order, or a single shared bucket for template-major order.
+func (s *hostScheduler) bucketKey(job Job) string {
+	if s.options.Order == OrderHostMajor {
+		return job.host
+	}
+	return ""
+}
+
+// Submit implements Scheduler.
+func (s *hostScheduler) Submit(job Job) {
+	if job.Target != nil {
+		job.host = job.Target.ID()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.seq = s.nextSeq
+	s.nextSeq++
+
+	key := s.bucketKey(job)
+	q, ok := s.buckets[key]
+	if !ok {
+		q = &priorityQueue{}
+		heap.Init(q)
+		s.buckets[key] = q
+		s.hostOrder = append(s.hostOrder, key)
+	}
+	heap.Push(q, job)
+	s.queueDepth++
+	s.cond.Signal()
+}
+
+// Next implements Scheduler.
+func (s *hostScheduler) Next(ctx context.Context) (Job, bool) {
+	for {
+		job, ok := s.dequeue(ctx)
+		if !ok {
+			return Job{}, false
+		}
+
+		if s.options.Backpressure != nil && s.options.Backpressure(job.host) {
+			s.mu.Lock()
+			s.droppedBP++
+			s.mu.Unlock()
+			continue
+		}
+
+		if job.host != "" && s.limiter != nil {
+			if err := s.limiter.Wait(ctx, job.host); err != nil {
+				return Job{}, false
+			}
+		}
+
+		s.mu.Lock()
+		s.inflight[job.host]++
+		s.mu.Unlock()
+		return job, true
+	}
+}
+
+// dequeue pops the next Job in priority/round-robin order, blocking until
+// one is available, the scheduler is closed, or ctx is done.
+func (s *hostScheduler) dequeue(ctx context.Context) (Job, bool) {
+	// sync.Cond can't select on ctx, so wake the Wait below once ctx is done.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.cond.Broadcast()
+			s.mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if job, ok := s.popLocked(); ok {
+			return job, true
+		}
+		if s.closed {
+			return Job{}, false
+		}
+		if ctx.Err() != nil {
+			return Job{}, false
+		}
+		s.cond.Wait()
+	}
+}
+
+// popLocked pops the next Job from s.hostOrder in round-robin fashion. Must
+// be called with s.mu held.
+func (s *hostScheduler) popLocked() (Job, bool) {
+	for i := 0; i < len(s.hostOrder); i++ {
+		idx := (s.nextHost + i) % len(s.hostOrder)
+		key := s.hostOrder[idx]
+		q := s.buckets[key]
+		if q.Len() == 0 {
+			continue
+		}
+		job := heap.Pop(q).(Job)
+		s.queueDepth--
+		s.nextHost = (idx + 1) % len(s.hostOrder)
+		return job, true
+	}
+	return Job{}, false
+}
+
+// Done implements Scheduler.
+func (s *hostScheduler) Done(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[job.host] > 0 {
+		s.inflight[job.host]--
+	}
+}
+
+// Close implements Scheduler.
+func (s *hostScheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// Stats implements Scheduler.
+func (s *hostScheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	perHost := make(map[string]int, len(s.inflight))
+	for host, n := range s.inflight {
+		if n > 0 {
+			perHost[host] = n
+		}
+	}
+	return SchedulerStats{
+		QueueDepth:            s.queueDepth,
+		PerHostInflight:       perHost,
+		DroppedByBackpressure: s.droppedBP,
+	}
+}
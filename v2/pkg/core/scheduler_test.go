@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSchedulerTemplateMajorPriorityOrder(t *testing.T) {
+	s := NewScheduler(SchedulerOptions{Order: OrderTemplateMajor})
+
+	s.Submit(Job{host: "a", Priority: PriorityInfo})
+	s.Submit(Job{host: "a", Priority: PriorityCritical})
+	s.Submit(Job{host: "b", Priority: PriorityMedium})
+	s.Close()
+
+	var got []JobPriority
+	for {
+		job, ok := s.Next(context.Background())
+		if !ok {
+			break
+		}
+		got = append(got, job.Priority)
+		s.Done(job)
+	}
+
+	want := []JobPriority{PriorityCritical, PriorityMedium, PriorityInfo}
+	if len(got) != len(want) {
+		t.Fatalf("got %v jobs, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("position %d: got priority %v, want %v (full order %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestSchedulerHostMajorRoundRobin(t *testing.T) {
+	s := NewScheduler(SchedulerOptions{Order: OrderHostMajor})
+
+	s.Submit(Job{host: "a", Priority: PriorityInfo})
+	s.Submit(Job{host: "a", Priority: PriorityInfo})
+	s.Submit(Job{host: "b", Priority: PriorityInfo})
+	s.Close()
+
+	var hosts []string
+	for {
+		job, ok := s.Next(context.Background())
+		if !ok {
+			break
+		}
+		hosts = append(hosts, job.host)
+		s.Done(job)
+	}
+
+	if len(hosts) != 3 {
+		t.Fatalf("expected 3 jobs, got %v", hosts)
+	}
+	// round-robin across hosts means "a"'s second job shouldn't immediately
+	// follow its first while "b" still has a pending job.
+	if hosts[0] == "a" && hosts[1] == "a" {
+		t.Errorf("expected host-major order to interleave hosts, got %v", hosts)
+	}
+}
+
+func TestSchedulerBackpressureDropsJob(t *testing.T) {
+	dropped := map[string]bool{"bad-host": true}
+	s := NewScheduler(SchedulerOptions{
+		Order:        OrderHostMajor,
+		Backpressure: func(host string) bool { return dropped[host] },
+	})
+
+	s.Submit(Job{host: "bad-host", Priority: PriorityInfo})
+	s.Submit(Job{host: "good-host", Priority: PriorityInfo})
+	s.Close()
+
+	job, ok := s.Next(context.Background())
+	if !ok {
+		t.Fatalf("expected one job to survive backpressure")
+	}
+	if job.host != "good-host" {
+		t.Errorf("expected good-host to survive, got %q", job.host)
+	}
+	s.Done(job)
+
+	if _, ok := s.Next(context.Background()); ok {
+		t.Fatalf("expected queue to be drained")
+	}
+
+	stats := s.Stats()
+	if stats.DroppedByBackpressure != 1 {
+		t.Errorf("expected 1 dropped-by-backpressure job, got %d", stats.DroppedByBackpressure)
+	}
+}
+
+func TestSchedulerStatsQueueDepth(t *testing.T) {
+	s := NewScheduler(SchedulerOptions{})
+	s.Submit(Job{host: "a", Priority: PriorityLow})
+	s.Submit(Job{host: "a", Priority: PriorityHigh})
+
+	if stats := s.Stats(); stats.QueueDepth != 2 {
+		t.Fatalf("expected queue depth 2, got %d", stats.QueueDepth)
+	}
+
+	s.Close()
+	job, ok := s.Next(context.Background())
+	if !ok {
+		t.Fatalf("expected a job")
+	}
+	if stats := s.Stats(); stats.QueueDepth != 1 {
+		t.Errorf("expected queue depth 1 after one dequeue, got %d", stats.QueueDepth)
+	}
+	if stats := s.Stats(); stats.PerHostInflight["a"] != 1 {
+		t.Errorf("expected host 'a' to have 1 inflight job, got %d", stats.PerHostInflight["a"])
+	}
+	s.Done(job)
+	if stats := s.Stats(); stats.PerHostInflight["a"] != 0 {
+		t.Errorf("expected host 'a' to have 0 inflight jobs after Done, got %d", stats.PerHostInflight["a"])
+	}
+}
+
+func TestSeverityToPriority(t *testing.T) {
+	cases := map[string]JobPriority{
+		"critical": PriorityCritical,
+		"high":     PriorityHigh,
+		"medium":   PriorityMedium,
+		"low":      PriorityLow,
+		"info":     PriorityInfo,
+		"":         PriorityInfo,
+		"bogus":    PriorityInfo,
+	}
+	for severity, want := range cases {
+		if got := SeverityToPriority(severity); got != want {
+			t.Errorf("SeverityToPriority(%q) = %v, want %v", severity, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package enrichment
+
+import "sync"
+
+// Closer is implemented by an Enricher that holds a resource (e.g. an
+// on-disk cache database) needing an explicit shutdown.
+type Closer interface {
+	Close() error
+}
+
+// CachingEnricher wraps an Enricher with an in-memory cache, so repeated
+// lookups of the same CVE ID within a single nuclei run (common across
+// templates/targets) are O(1) after the first hit instead of re-querying
+// the underlying feed or its on-disk cache every time.
+type CachingEnricher struct {
+	next Enricher
+
+	mu    sync.RWMutex
+	cache map[string]*Result
+}
+
+// NewCachingEnricher wraps next with an in-memory lookup cache.
+func NewCachingEnricher(next Enricher) *CachingEnricher {
+	return &CachingEnricher{next: next, cache: make(map[string]*Result)}
+}
+
+// Enrich returns the cached Result for cveID if one was already looked up
+// this run, otherwise it delegates to the wrapped Enricher and caches the
+// outcome (including a clean miss, so a not-found CVE isn't re-queried).
+func (c *CachingEnricher) Enrich(cveID string) (*Result, error) {
+	c.mu.RLock()
+	if result, ok := c.cache[cveID]; ok {
+		c.mu.RUnlock()
+		return result, nil
+	}
+	c.mu.RUnlock()
+
+	result, err := c.next.Enrich(cveID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[cveID] = result
+	c.mu.Unlock()
+	return result, nil
+}
+
+// Close releases the wrapped Enricher's resources, if it holds any.
+func (c *CachingEnricher) Close() error {
+	if closer, ok := c.next.(Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
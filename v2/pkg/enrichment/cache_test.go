@@ -0,0 +1,42 @@
+package enrichment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingEnricher struct {
+	calls   int
+	results map[string]*Result
+}
+
+func (c *countingEnricher) Enrich(cveID string) (*Result, error) {
+	c.calls++
+	return c.results[cveID], nil
+}
+
+func TestCachingEnricherCachesLookups(t *testing.T) {
+	next := &countingEnricher{results: map[string]*Result{
+		"CVE-2021-1234": {CVEID: "CVE-2021-1234", CVSSScore: 9.8},
+	}}
+	cache := NewCachingEnricher(next)
+
+	for i := 0; i < 3; i++ {
+		result, err := cache.Enrich("CVE-2021-1234")
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, 9.8, result.CVSSScore)
+	}
+	require.Equal(t, 1, next.calls, "repeated lookups of the same cve should only hit the wrapped enricher once")
+
+	result, err := cache.Enrich("CVE-9999-0000")
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.Equal(t, 2, next.calls)
+
+	// a second miss lookup should also be served from cache, not re-queried
+	_, err = cache.Enrich("CVE-9999-0000")
+	require.NoError(t, err)
+	require.Equal(t, 2, next.calls, "a clean miss should be cached too")
+}
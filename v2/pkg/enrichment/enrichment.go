@@ -0,0 +1,29 @@
+// Package enrichment looks up CVE/CWE identifiers found in a template's
+// info.classification block against a vulnerability feed, so matched
+// findings can carry a CVSS score, exploitability, publish date and
+// references without the operator cross-referencing NVD by hand.
+package enrichment
+
+import "time"
+
+// Result is the enrichment data attached to a single finding.
+type Result struct {
+	CVEID            string    `json:"cve_id,omitempty"`
+	CWEID            string    `json:"cwe_id,omitempty"`
+	CVSSScore        float64   `json:"cvss_score,omitempty"`
+	CVSSVector       string    `json:"cvss_vector,omitempty"`
+	Severity         string    `json:"severity,omitempty"`
+	Exploitability   float64   `json:"exploitability_score,omitempty"`
+	Published        time.Time `json:"published,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	References       []string  `json:"references,omitempty"`
+	AffectedPackages []string  `json:"affected_packages,omitempty"`
+}
+
+// Enricher looks up vulnerability metadata for a CVE ID.
+type Enricher interface {
+	// Enrich returns the Result for cveID, or an error if the lookup
+	// itself failed (an unknown CVE is not an error: implementations
+	// return (nil, nil) for a clean miss).
+	Enrich(cveID string) (*Result, error)
+}
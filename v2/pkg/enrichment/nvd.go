@@ -0,0 +1,259 @@
+package enrichment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultNVDFeedURL is the NVD REST endpoint for a single CVE, with the CVE
+// ID substituted in via fmt.Sprintf.
+const defaultNVDFeedURL = "https://services.nvd.nist.gov/rest/json/cve/1.0/%s"
+
+var cveBucket = []byte("cves")
+
+// NVDEnricher looks up CVEs against the NVD JSON feed, persisting results
+// to a local bbolt database so subsequent nuclei runs don't re-fetch CVEs
+// they've already seen. DBPath is opened by NewNVDEnricher (or Open); without
+// an open database, lookups go straight to the feed and nothing is persisted.
+type NVDEnricher struct {
+	// FeedURL is the NVD CVE endpoint, with "%s" substituted for the CVE
+	// ID. Defaults to defaultNVDFeedURL.
+	FeedURL string
+	// DBPath is the path to the local bbolt cache database.
+	DBPath string
+	// HTTPClient is used for feed requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	db *bolt.DB
+}
+
+// NewNVDEnricher creates an NVDEnricher caching to dbPath and opens its
+// cache database.
+func NewNVDEnricher(dbPath string) (*NVDEnricher, error) {
+	n := &NVDEnricher{DBPath: dbPath}
+	if err := n.Open(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Refresh re-fetches every CVE already present in the cache from the feed,
+// overwriting their stored entries. It's the implementation behind
+// nuclei's -update-cves flag.
+func (n *NVDEnricher) Refresh() error {
+	if n.db == nil {
+		return nil
+	}
+
+	var cveIDs []string
+	err := n.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(cveBucket).ForEach(func(k, v []byte) error {
+			cveIDs = append(cveIDs, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not list cached cves")
+	}
+
+	for _, cveID := range cveIDs {
+		result, err := n.fetchFromFeed(cveID)
+		if err != nil {
+			return errors.Wrapf(err, "could not refresh %s", cveID)
+		}
+		if err := n.store(cveID, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open opens (creating if necessary) the on-disk cache database at DBPath.
+func (n *NVDEnricher) Open() error {
+	db, err := bolt.Open(n.DBPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return errors.Wrap(err, "could not open cve cache database")
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cveBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return errors.Wrap(err, "could not initialize cve cache bucket")
+	}
+	n.db = db
+	return nil
+}
+
+// Close releases the underlying cache database.
+func (n *NVDEnricher) Close() error {
+	if n.db == nil {
+		return nil
+	}
+	return n.db.Close()
+}
+
+// Enrich looks up cveID in the local cache database, falling back to the
+// NVD feed (and persisting the result) on a cache miss.
+func (n *NVDEnricher) Enrich(cveID string) (*Result, error) {
+	if cached, ok, err := n.lookupCached(cveID); err != nil {
+		return nil, err
+	} else if ok {
+		return cached, nil
+	}
+
+	result, err := n.fetchFromFeed(cveID)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.store(cveID, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (n *NVDEnricher) lookupCached(cveID string) (*Result, bool, error) {
+	if n.db == nil {
+		return nil, false, nil
+	}
+
+	var result *Result
+	err := n.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(cveBucket).Get([]byte(cveID))
+		if data == nil {
+			return nil
+		}
+		result = &Result{}
+		return json.Unmarshal(data, result)
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not read cve cache")
+	}
+	return result, result != nil, nil
+}
+
+func (n *NVDEnricher) store(cveID string, result *Result) error {
+	if n.db == nil || result == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal cve result")
+	}
+	return n.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cveBucket).Put([]byte(cveID), data)
+	})
+}
+
+// nvdResponse is the minimal subset of the NVD CVE response format used to
+// populate a Result.
+type nvdResponse struct {
+	Result struct {
+		CVEItems []struct {
+			CVE struct {
+				CVEDataMeta struct {
+					ID string `json:"ID"`
+				} `json:"CVE_data_meta"`
+				Description struct {
+					DescriptionData []struct {
+						Value string `json:"value"`
+					} `json:"description_data"`
+				} `json:"description"`
+				References struct {
+					ReferenceData []struct {
+						URL string `json:"url"`
+					} `json:"reference_data"`
+				} `json:"references"`
+			} `json:"cve"`
+			Impact struct {
+				BaseMetricV3 struct {
+					CVSSV3 struct {
+						BaseScore    float64 `json:"baseScore"`
+						VectorString string  `json:"vectorString"`
+						BaseSeverity string  `json:"baseSeverity"`
+					} `json:"cvssV3"`
+					ExploitabilityScore float64 `json:"exploitabilityScore"`
+				} `json:"baseMetricV3"`
+			} `json:"impact"`
+			PublishedDate string `json:"publishedDate"`
+		} `json:"CVE_Items"`
+	} `json:"result"`
+}
+
+func (n *NVDEnricher) fetchFromFeed(cveID string) (*Result, error) {
+	feedURL := n.FeedURL
+	if feedURL == "" {
+		feedURL = defaultNVDFeedURL
+	}
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(fmt.Sprintf(feedURL, cveID))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query nvd feed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("nvd feed returned status %d for %s", resp.StatusCode, cveID)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read nvd feed response")
+	}
+
+	var parsed nvdResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, errors.Wrap(err, "could not parse nvd feed response")
+	}
+	if len(parsed.Result.CVEItems) == 0 {
+		return nil, nil
+	}
+	item := parsed.Result.CVEItems[0]
+
+	result := &Result{
+		CVEID:          item.CVE.CVEDataMeta.ID,
+		CVSSScore:      item.Impact.BaseMetricV3.CVSSV3.BaseScore,
+		CVSSVector:     item.Impact.BaseMetricV3.CVSSV3.VectorString,
+		Severity:       item.Impact.BaseMetricV3.CVSSV3.BaseSeverity,
+		Exploitability: item.Impact.BaseMetricV3.ExploitabilityScore,
+	}
+	if published, err := time.Parse("2006-01-02T15:04Z", item.PublishedDate); err == nil {
+		result.Published = published
+	}
+	if len(item.CVE.Description.DescriptionData) > 0 {
+		result.Description = item.CVE.Description.DescriptionData[0].Value
+	}
+	for _, ref := range item.CVE.References.ReferenceData {
+		result.References = append(result.References, ref.URL)
+	}
+	return result, nil
+}
+
+// CachedCount reports how many CVEs are currently cached, so -update-cves
+// runs can log progress.
+func (n *NVDEnricher) CachedCount() (int, error) {
+	if n.db == nil {
+		return 0, nil
+	}
+	count := 0
+	err := n.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(cveBucket).ForEach(func(k, v []byte) error {
+			count++
+			return nil
+		})
+	})
+	return count, err
+}
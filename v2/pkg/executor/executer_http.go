@@ -2,10 +2,11 @@ package executor
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
@@ -18,13 +19,43 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/auth/challenge"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ratelimit"
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/xfer"
 	"github.com/projectdiscovery/retryablehttp-go"
 	"golang.org/x/net/proxy"
 )
 
+// transferManager is shared by every HTTPExecutor in the process, so that
+// the many templates run against the same target end up deduplicated onto
+// the manager's in-flight/cached requests instead of each firing their own.
+var transferManager = xfer.New(nil)
+
+// requestLimiter is shared by every HTTPExecutor in the process, so that
+// the configured rate limits cap the scan's total and per-host request
+// rate rather than applying independently per template. It's configured
+// once, from whichever HTTPOptions first enables rate limiting.
+var (
+	requestLimiterMu sync.Mutex
+	requestLimiter   *ratelimit.Limiter
+)
+
+func sharedRequestLimiter(options *HTTPOptions) *ratelimit.Limiter {
+	requestLimiterMu.Lock()
+	defer requestLimiterMu.Unlock()
+	if requestLimiter == nil && (options.RateLimit > 0 || options.RateLimitPerHost > 0) {
+		requestLimiter = ratelimit.New(&ratelimit.Options{
+			GlobalRPS:  float64(options.RateLimit),
+			PerHostRPS: float64(options.RateLimitPerHost),
+			Adaptive:   options.RateLimitAdaptive,
+		})
+	}
+	return requestLimiter
+}
+
 // HTTPExecutor is client for performing HTTP requests
 // for a template.
 type HTTPExecutor struct {
@@ -37,6 +68,11 @@ type HTTPExecutor struct {
 	writer        *bufio.Writer
 	outputMutex   *sync.Mutex
 	customHeaders requests.CustomHeaders
+
+	deadlinePerTarget time.Duration
+	jsonRequest       bool
+	limiter           *ratelimit.Limiter
+	authenticator     *challenge.Authenticator
 }
 
 // HTTPOptions contains configuration options for the HTTP executor.
@@ -51,6 +87,29 @@ type HTTPOptions struct {
 	Debug         bool
 	JSON          bool
 	CustomHeaders requests.CustomHeaders
+	// DeadlinePerTarget bounds the total time ExecuteHTTP is allowed to
+	// spend against a single URL, independent of the per-request Timeout.
+	// Zero means no per-target deadline beyond the caller's ctx.
+	DeadlinePerTarget time.Duration
+	// JSONRequest additionally includes the full dumped HTTP request and
+	// response in each JSON output record. Has no effect unless JSON is
+	// also set.
+	JSONRequest bool
+	// RateLimit bounds the total requests per second sent across every
+	// HTTPExecutor in the process. Zero means unlimited.
+	RateLimit int
+	// RateLimitPerHost bounds the requests per second sent to any single
+	// host, independent of RateLimit's overall cap. Zero means unlimited.
+	RateLimitPerHost int
+	// RateLimitAdaptive halves a host's effective RateLimitPerHost on
+	// repeated 429/503 responses, slowly recovering it back on success,
+	// so aggressive template packs don't get scanners banned.
+	RateLimitAdaptive bool
+	// Authenticator resolves WWW-Authenticate challenges into an
+	// Authorization header, letting templates hit authenticated APIs
+	// without hardcoding credentials/tokens in the YAML. Nil disables
+	// challenge-driven auth.
+	Authenticator *challenge.Authenticator
 }
 
 // NewHTTPExecutor creates a new HTTP executor from a template
@@ -71,15 +130,19 @@ func NewHTTPExecutor(options *HTTPOptions) (*HTTPExecutor, error) {
 	client.CheckRetry = retryablehttp.HostSprayRetryPolicy()
 
 	executer := &HTTPExecutor{
-		debug:         options.Debug,
-		jsonOutput:    options.JSON,
-		results:       0,
-		httpClient:    client,
-		template:      options.Template,
-		httpRequest:   options.HTTPRequest,
-		outputMutex:   &sync.Mutex{},
-		writer:        options.Writer,
-		customHeaders: options.CustomHeaders,
+		debug:             options.Debug,
+		jsonOutput:        options.JSON,
+		results:           0,
+		httpClient:        client,
+		template:          options.Template,
+		httpRequest:       options.HTTPRequest,
+		outputMutex:       &sync.Mutex{},
+		writer:            options.Writer,
+		customHeaders:     options.CustomHeaders,
+		deadlinePerTarget: options.DeadlinePerTarget,
+		jsonRequest:       options.JSONRequest,
+		limiter:           sharedRequestLimiter(options),
+		authenticator:     options.Authenticator,
 	}
 	return executer, nil
 }
@@ -92,10 +155,28 @@ func (e *HTTPExecutor) GotResults() bool {
 	return true
 }
 
-// ExecuteHTTP executes the HTTP request on a URL
-func (e *HTTPExecutor) ExecuteHTTP(p *progress.Progress, URL string) error {
+// ExecuteHTTP executes the HTTP request on a URL. ctx is honored for the
+// entire run: cancelling it (a user SIGINT propagated from internal/runner,
+// or the per-target deadline below) unwinds the request loop promptly
+// instead of waiting out the fixed per-request Timeout.
+func (e *HTTPExecutor) ExecuteHTTP(ctx context.Context, p *progress.Progress, URL string) error {
+	if e.deadlinePerTarget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.deadlinePerTarget)
+		defer cancel()
+	}
+
+	// cancelCh mirrors netstack's deadlineTimer pattern: a single channel,
+	// closed once when ctx is done, that the request loop below can select
+	// on instead of threading ctx.Err() checks through every step.
+	cancelCh := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(cancelCh)
+	}()
+
 	// Compile each request for the template based on the URL
-	compiledRequest, err := e.httpRequest.MakeHTTPRequest(URL)
+	compiledRequests, err := e.httpRequest.MakeHTTPRequest(URL)
 	if err != nil {
 		return errors.Wrap(err, "could not compile http request")
 	}
@@ -104,68 +185,120 @@ func (e *HTTPExecutor) ExecuteHTTP(p *progress.Progress, URL string) error {
 
 	// Send the request to the target servers
 mainLoop:
-	for compiledRequest := range compiledRequest {
+	for {
+		var compiledRequest *requests.CompiledHTTP
+		var ok bool
+		select {
+		case <-cancelCh:
+			// Drain in the background so the generator goroutine behind
+			// MakeHTTPRequest doesn't block forever trying to send to a
+			// channel nobody is reading from anymore.
+			go drainCompiledHTTPRequests(compiledRequests)
+			p.Abort(remaining)
+			return ctx.Err()
+		case compiledRequest, ok = <-compiledRequests:
+			if !ok {
+				break mainLoop
+			}
+		}
 		if compiledRequest.Error != nil {
 			p.Abort(remaining)
 			return errors.Wrap(err, "error in compiled http request")
 		}
 		e.setCustomHeaders(compiledRequest)
 		req := compiledRequest.Request
+		req.Request = req.Request.WithContext(ctx)
 
-		if e.debug {
-			p.StartStdCapture()
-			gologger.Infof("Dumped HTTP request for %s (%s)\n\n", URL, e.template.ID)
-			p.StopStdCaptureAndShow()
+		// The request/response are dumped at most once per compiled
+		// request and the bytes are reused both for the debug printout
+		// and for the JSON output record below, instead of dumping (and
+		// thus re-reading) the body a second time.
+		needDump := e.debug || (e.jsonOutput && e.jsonRequest)
 
-			dumpedRequest, err := httputil.DumpRequest(req.Request, true)
+		var dumpedRequest []byte
+		if needDump {
+			dumpedRequest, err = httputil.DumpRequest(req.Request, true)
 			if err != nil {
 				p.Abort(remaining)
 				return errors.Wrap(err, "could not dump http request")
 			}
-			p.StartStdCapture()
-			fmt.Fprintf(os.Stderr, "%s", string(dumpedRequest))
-			p.StopStdCaptureAndShow()
+			if e.debug {
+				p.StartStdCapture()
+				gologger.Infof("Dumped HTTP request for %s (%s)\n\n", URL, e.template.ID)
+				fmt.Fprintf(os.Stderr, "%s", string(dumpedRequest))
+				p.StopStdCaptureAndShow()
+			}
 		}
 
-		resp, err := e.httpClient.Do(req)
-		if err != nil {
-			if resp != nil {
-				resp.Body.Close()
+		if e.limiter != nil {
+			if err := e.limiter.Wait(ctx, req.Request.URL.Host); err != nil {
+				p.Abort(remaining)
+				return errors.Wrap(err, "rate limiter wait failed")
 			}
+		}
+
+		requestStart := time.Now()
+		transfer, watch := transferManager.Do(e.httpClient, req)
+		result := <-watch
+		if result.Err != nil {
+			transfer.Cancel()
+			p.ObserveRequest(e.template.ID, 0, time.Since(requestStart))
 			p.Abort(1)
 			p.StartStdCapture()
-			gologger.Warningf("Could not do request: %s\n", err)
+			gologger.Warningf("Could not do request: %s\n", result.Err)
 			p.StopStdCaptureAndShow()
 			continue
 		}
+		p.ObserveRequest(e.template.ID, result.StatusCode, time.Since(requestStart))
+		if e.limiter != nil {
+			e.limiter.Observe(req.Request.URL.Host, result.StatusCode)
+		}
+		resp := &http.Response{
+			StatusCode: result.StatusCode,
+			Header:     result.Header,
+			Body:       ioutil.NopCloser(bytes.NewReader(result.Body)),
+		}
 
-		if e.debug {
-			p.StartStdCapture()
-			gologger.Infof("Dumped HTTP response for %s (%s)\n\n", URL, e.template.ID)
-			p.StopStdCaptureAndShow()
+		// A 401/407 with a WWW-Authenticate challenge gets exactly one
+		// reissue attempt with the resolved Authorization header; a
+		// second 401 after that is treated as a genuine auth failure
+		// rather than retried in a loop.
+		if e.authenticator != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusProxyAuthRequired) {
+			if authz, ok := e.authenticator.Authorize(ctx, req.Request.Request, resp); ok {
+				req.Request.Request.Header.Set("Authorization", authz)
+				retryTransfer, retryWatch := transferManager.Do(e.httpClient, req)
+				retryResult := <-retryWatch
+				if retryResult.Err == nil {
+					result = retryResult
+					resp = &http.Response{
+						StatusCode: result.StatusCode,
+						Header:     result.Header,
+						Body:       ioutil.NopCloser(bytes.NewReader(result.Body)),
+					}
+				} else {
+					retryTransfer.Cancel()
+				}
+			}
+		}
 
-			dumpedResponse, err := httputil.DumpResponse(resp, true)
+		var dumpedResponse []byte
+		if needDump {
+			dumpedResponse, err = httputil.DumpResponse(resp, true)
 			if err != nil {
 				p.Abort(remaining)
 				return errors.Wrap(err, "could not dump http response")
 			}
-			p.StartStdCapture()
-			fmt.Fprintf(os.Stderr, "%s\n", string(dumpedResponse))
-			p.StopStdCaptureAndShow()
-		}
-
-		data, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			io.Copy(ioutil.Discard, resp.Body)
-			resp.Body.Close()
-			p.Abort(remaining)
-			return errors.Wrap(err, "could not read http body")
+			if e.debug {
+				p.StartStdCapture()
+				gologger.Infof("Dumped HTTP response for %s (%s)\n\n", URL, e.template.ID)
+				fmt.Fprintf(os.Stderr, "%s\n", string(dumpedResponse))
+				p.StopStdCaptureAndShow()
+			}
 		}
-		resp.Body.Close()
 
 		// net/http doesn't automatically decompress the response body if an encoding has been specified by the user in the request
 		// so in case we have to manually do it
-		data, err = requests.HandleDecompression(compiledRequest.Request, data)
+		data, err := requests.HandleDecompression(compiledRequest.Request, result.Body)
 		if err != nil {
 			p.Abort(remaining)
 			return errors.Wrap(err, "could not decompress http body")
@@ -192,8 +325,9 @@ mainLoop:
 				if matcherCondition == matchers.ORCondition && len(e.httpRequest.Extractors) == 0 {
 					// capture stdout and emit it via a mpb.BarFiller
 					p.StartStdCapture()
-					e.writeOutputHTTP(compiledRequest, matcher, nil)
+					e.writeOutputHTTP(compiledRequest, URL, dumpedRequest, dumpedResponse, matcher, nil)
 					p.StopStdCaptureAndShow()
+					p.ObserveMatch(e.template.ID)
 
 					atomic.CompareAndSwapUint32(&e.results, 0, 1)
 				}
@@ -215,8 +349,9 @@ mainLoop:
 		if len(e.httpRequest.Extractors) > 0 || matcherCondition == matchers.ANDCondition {
 			// capture stdout and emit it via a mpb.BarFiller
 			p.StartStdCapture()
-			e.writeOutputHTTP(compiledRequest, nil, extractorResults)
+			e.writeOutputHTTP(compiledRequest, URL, dumpedRequest, dumpedResponse, nil, extractorResults)
 			p.StopStdCaptureAndShow()
+			p.ObserveMatch(e.template.ID)
 
 			atomic.CompareAndSwapUint32(&e.results, 0, 1)
 		}
@@ -232,6 +367,13 @@ mainLoop:
 	return nil
 }
 
+// drainCompiledHTTPRequests discards every remaining item on ch. It lets the
+// generator goroutine behind MakeHTTPRequest finish sending and exit instead
+// of leaking, once ExecuteHTTP has already returned because ctx was done.
+func drainCompiledHTTPRequests(ch <-chan *requests.CompiledHTTP) {
+	for range ch {
+	}
+}
 
 // Close closes the http executor for a template.
 func (e *HTTPExecutor) Close() {
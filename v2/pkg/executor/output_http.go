@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+)
+
+// jsonOutputHTTP is the structured record emitted per match when
+// HTTPOptions.JSON is set, mirroring the sibling executer package's
+// jsonOutput shape for the http protocol.
+type jsonOutputHTTP struct {
+	Template         string   `json:"template"`
+	Type             string   `json:"type"`
+	Matched          string   `json:"matched"`
+	Name             string   `json:"name,omitempty"`
+	Severity         string   `json:"severity,omitempty"`
+	Author           string   `json:"author,omitempty"`
+	Description      string   `json:"description,omitempty"`
+	MatcherName      string   `json:"matcher_name,omitempty"`
+	ExtractedResults []string `json:"extracted_results,omitempty"`
+	Request          string   `json:"request,omitempty"`
+	Response         string   `json:"response,omitempty"`
+}
+
+// writeOutputHTTP writes a single match to the output writer, either as a
+// plain colorless text line or, when e.jsonOutput is set, as a structured
+// JSON record. dumpedRequest/dumpedResponse are the bytes already captured
+// earlier in ExecuteHTTP and are reused here verbatim - writeOutputHTTP
+// never re-dumps the request or re-reads the response body.
+func (e *HTTPExecutor) writeOutputHTTP(req *requests.CompiledHTTP, URL string, dumpedRequest, dumpedResponse []byte, matcher *matchers.Matcher, extractorResults []string) {
+	if e.jsonOutput {
+		e.writeOutputHTTPJSON(URL, dumpedRequest, dumpedResponse, matcher, extractorResults)
+		return
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteRune('[')
+	builder.WriteString(e.template.ID)
+	if matcher != nil && len(matcher.Name) > 0 {
+		builder.WriteString(":")
+		builder.WriteString(matcher.Name)
+	}
+	builder.WriteString("] [http] ")
+	if e.template.Info.Severity != "" {
+		builder.WriteString("[")
+		builder.WriteString(e.template.Info.Severity)
+		builder.WriteString("] ")
+	}
+	builder.WriteString(URL)
+
+	if len(extractorResults) > 0 {
+		builder.WriteString(" [")
+		builder.WriteString(strings.Join(extractorResults, ","))
+		builder.WriteString("]")
+	}
+	builder.WriteRune('\n')
+
+	message := builder.String()
+	gologger.Silentf("%s", message)
+
+	e.outputMutex.Lock()
+	defer e.outputMutex.Unlock()
+	if _, err := e.writer.WriteString(message); err != nil {
+		gologger.Errorf("Could not write output data: %s\n", err)
+	}
+}
+
+// writeOutputHTTPJSON marshals and writes a jsonOutputHTTP record. It holds
+// e.outputMutex while writing to e.writer, same as the plain text path, so
+// concurrently written records never interleave.
+func (e *HTTPExecutor) writeOutputHTTPJSON(URL string, dumpedRequest, dumpedResponse []byte, matcher *matchers.Matcher, extractorResults []string) {
+	output := jsonOutputHTTP{
+		Template:    e.template.ID,
+		Type:        "http",
+		Matched:     URL,
+		Name:        e.template.Info.Name,
+		Severity:    e.template.Info.Severity,
+		Author:      e.template.Info.Author,
+		Description: e.template.Info.Description,
+	}
+	if matcher != nil && len(matcher.Name) > 0 {
+		output.MatcherName = matcher.Name
+	}
+	if len(extractorResults) > 0 {
+		output.ExtractedResults = extractorResults
+	}
+	if e.jsonRequest {
+		output.Request = string(dumpedRequest)
+		output.Response = string(dumpedResponse)
+	}
+
+	data, err := jsoniter.Marshal(output)
+	if err != nil {
+		gologger.Warningf("Could not marshal json output: %s\n", err)
+		return
+	}
+
+	gologger.Silentf("%s", string(data))
+
+	e.outputMutex.Lock()
+	defer e.outputMutex.Unlock()
+	if _, err := e.writer.Write(data); err != nil {
+		gologger.Errorf("Could not write output data: %s\n", err)
+		return
+	}
+	if err := e.writer.WriteByte('\n'); err != nil {
+		gologger.Errorf("Could not write output data: %s\n", err)
+	}
+}
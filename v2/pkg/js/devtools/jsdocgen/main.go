@@ -14,9 +14,12 @@ import (
 )
 
 var (
-	dir     string
-	key     string
-	keyfile string
+	dir        string
+	key        string
+	keyfile    string
+	backend    string
+	model      string
+	azureEndpt string
 )
 
 const sysPrompt = `
@@ -36,12 +39,100 @@ const userPrompt = `
 ---new javascript---
 `
 
+// Backend is an LLM chat-completion provider that doclint can delegate the
+// actual JSDoc generation to. It abstracts over the various OpenAI-compatible
+// APIs (OpenAI itself, Azure OpenAI, self-hosted gateways, ...) so the rest
+// of the tool doesn't need to know which one is in use.
+type Backend interface {
+	// Complete sends the system/user prompt pair to the backend and returns
+	// the generated completion.
+	Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+}
+
+// openAIBackend talks to the public OpenAI API.
+type openAIBackend struct {
+	client *openai.Client
+	model  string
+}
+
+func newOpenAIBackend(apiKey, model string) *openAIBackend {
+	return &openAIBackend{client: openai.NewClient(apiKey), model: model}
+}
+
+func (o *openAIBackend) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	resp, err := o.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: o.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", errorutil.New("no choices returned")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// azureOpenAIBackend talks to an Azure OpenAI deployment. The model argument
+// is used as the deployment name, matching Azure's convention.
+type azureOpenAIBackend struct {
+	client *openai.Client
+	model  string
+}
+
+func newAzureOpenAIBackend(apiKey, endpoint, model string) *azureOpenAIBackend {
+	cfg := openai.DefaultAzureConfig(apiKey, endpoint)
+	return &azureOpenAIBackend{client: openai.NewClientWithConfig(cfg), model: model}
+}
+
+func (a *azureOpenAIBackend) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	resp, err := a.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", errorutil.New("no choices returned")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// newBackend resolves the -backend flag to a concrete Backend, validating
+// that the options it needs (api key, and azure endpoint where applicable)
+// were supplied.
+func newBackend(name, apiKey, model, azureEndpoint string) (Backend, error) {
+	switch name {
+	case "", "openai":
+		return newOpenAIBackend(apiKey, model), nil
+	case "azure":
+		if azureEndpoint == "" {
+			return nil, errorutil.New("azure backend requires -azure-endpoint")
+		}
+		return newAzureOpenAIBackend(apiKey, azureEndpoint, model), nil
+	default:
+		return nil, errorutil.New("unknown backend: %s", name)
+	}
+}
+
 // doclint is automatic javascript documentation linter for nuclei
 // it uses LLM to autocomplete the generated js code to proper JSDOC notation
 func main() {
 	flag.StringVar(&dir, "dir", "", "directory to process")
-	flag.StringVar(&key, "key", "", "openai api key")
-	flag.StringVar(&keyfile, "keyfile", "", "openai api key file")
+	flag.StringVar(&key, "key", "", "llm backend api key")
+	flag.StringVar(&keyfile, "keyfile", "", "llm backend api key file")
+	flag.StringVar(&backend, "backend", "openai", "llm backend to use (openai, azure)")
+	flag.StringVar(&model, "model", "gpt-4", "model / deployment name to use")
+	flag.StringVar(&azureEndpt, "azure-endpoint", "", "azure openai resource endpoint (required for -backend=azure)")
 	flag.Parse()
 	log.SetFlags(0)
 
@@ -50,7 +141,7 @@ func main() {
 	}
 	finalKey := ""
 	if key != "" {
-		key = finalKey
+		finalKey = key
 	}
 	if keyfile != "" && fileutil.FileExists(keyfile) {
 		data, err := os.ReadFile(keyfile)
@@ -64,9 +155,12 @@ func main() {
 	}
 
 	if finalKey == "" {
-		log.Fatal("openai api key is not set")
+		log.Fatal("llm backend api key is not set")
+	}
+	llm, err := newBackend(backend, finalKey, model, azureEndpt)
+	if err != nil {
+		log.Fatal(err)
 	}
-	llm := openai.NewClient(finalKey)
 
 	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
 		if !d.IsDir() && filepath.Ext(path) == ".js" {
@@ -82,27 +176,16 @@ func main() {
 }
 
 // updateDocsWithLLM updates the documentation of a javascript file
-func updateDocsWithLLM(llm *openai.Client, path string) error {
+func updateDocsWithLLM(llm Backend, path string) error {
 	// read the file
 	bin, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	resp, err := llm.CreateChatCompletion(context.TODO(), openai.ChatCompletionRequest{
-		Model: "gpt-4",
-		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: sysPrompt},
-			{Role: "user", Content: strings.ReplaceAll(userPrompt, "{{source}}", string(bin))},
-		},
-		Temperature: 0.1,
-	})
+	data, err := llm.Complete(context.TODO(), sysPrompt, strings.ReplaceAll(userPrompt, "{{source}}", string(bin)))
 	if err != nil {
 		return err
 	}
-	if len(resp.Choices) == 0 {
-		return errorutil.New("no choices returned")
-	}
-	data := resp.Choices[0].Message.Content
 	return os.WriteFile(path, []byte(data), 0644)
 }
@@ -44,6 +44,12 @@ func (matcher *Matcher) MatchWords(corpus string, dynamicValues map[string]inter
 		corpus = strings.ToLower(corpus)
 	}
 
+	// NOT and N-of-M threshold conditions need to see every word before
+	// deciding, so they can't use the AND/OR short-circuiting loop below.
+	if matcher.condition == NOTCondition || matcher.MinMatch > 0 {
+		return matcher.matchWordsThreshold(corpus, dynamicValues)
+	}
+
 	var matchedWords []string
 	// Iterate over all the words accepted as valid
 	for i, word := range matcher.Words {
@@ -82,8 +88,50 @@ func (matcher *Matcher) MatchWords(corpus string, dynamicValues map[string]inter
 	return false, []string{}
 }
 
+// matchWordsThreshold evaluates a NOT condition or an N-of-M MinMatch
+// threshold across all configured words. Unlike the AND/OR path it never
+// short-circuits, since both conditions need the full match count.
+func (matcher *Matcher) matchWordsThreshold(corpus string, dynamicValues map[string]interface{}) (bool, []string) {
+	var matchedWords []string
+	for _, word := range matcher.Words {
+		if dynamicValues == nil {
+			dynamicValues = make(map[string]interface{})
+		}
+
+		evaluated, err := expressions.Evaluate(word, dynamicValues)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(corpus, evaluated) {
+			matchedWords = append(matchedWords, evaluated)
+		}
+	}
+
+	// MinMatch takes precedence over Condition (see Matcher.MinMatch), so an
+	// N-of-M threshold must be checked before falling through to NOT.
+	if matcher.MinMatch > 0 {
+		if len(matchedWords) >= matcher.MinMatch {
+			return true, matchedWords
+		}
+		return false, []string{}
+	}
+	if matcher.condition == NOTCondition {
+		if len(matchedWords) == 0 {
+			return true, []string{}
+		}
+		return false, []string{}
+	}
+	return false, []string{}
+}
+
 // MatchRegex matches a regex check against a corpus
 func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
+	// NOT and N-of-M threshold conditions need to see every regex before
+	// deciding, so they can't use the AND/OR short-circuiting loop below.
+	if matcher.condition == NOTCondition || matcher.MinMatch > 0 {
+		return matcher.matchRegexThreshold(corpus)
+	}
+
 	var matchedRegexes []string
 	// Iterate over all the regexes accepted as valid
 	for i, regex := range matcher.regexCompiled {
@@ -114,8 +162,42 @@ func (matcher *Matcher) MatchRegex(corpus string) (bool, []string) {
 	return false, []string{}
 }
 
+// matchRegexThreshold evaluates a NOT condition or an N-of-M MinMatch
+// threshold across all configured regexes, scanning every entry instead of
+// short-circuiting like the AND/OR path.
+func (matcher *Matcher) matchRegexThreshold(corpus string) (bool, []string) {
+	var matchedRegexes []string
+	for _, regex := range matcher.regexCompiled {
+		if regex.MatchString(corpus) {
+			matchedRegexes = append(matchedRegexes, regex.FindAllString(corpus, -1)...)
+		}
+	}
+
+	// MinMatch takes precedence over Condition (see Matcher.MinMatch), so an
+	// N-of-M threshold must be checked before falling through to NOT.
+	if matcher.MinMatch > 0 {
+		if len(matchedRegexes) >= matcher.MinMatch {
+			return true, matchedRegexes
+		}
+		return false, []string{}
+	}
+	if matcher.condition == NOTCondition {
+		if len(matchedRegexes) == 0 {
+			return true, []string{}
+		}
+		return false, []string{}
+	}
+	return false, []string{}
+}
+
 // MatchBinary matches a binary check against a corpus
 func (matcher *Matcher) MatchBinary(corpus string) (bool, []string) {
+	// NOT and N-of-M threshold conditions need to see every binary pattern
+	// before deciding, so they can't use the AND/OR short-circuiting loop below.
+	if matcher.condition == NOTCondition || matcher.MinMatch > 0 {
+		return matcher.matchBinaryThreshold(corpus)
+	}
+
 	var matchedBinary []string
 	// Iterate over all the words accepted as valid
 	for i, binary := range matcher.binaryDecoded {
@@ -144,8 +226,42 @@ func (matcher *Matcher) MatchBinary(corpus string) (bool, []string) {
 	return false, []string{}
 }
 
+// matchBinaryThreshold evaluates a NOT condition or an N-of-M MinMatch
+// threshold across all configured binary patterns, scanning every entry
+// instead of short-circuiting like the AND/OR path.
+func (matcher *Matcher) matchBinaryThreshold(corpus string) (bool, []string) {
+	var matchedBinary []string
+	for _, binary := range matcher.binaryDecoded {
+		if strings.Contains(corpus, binary) {
+			matchedBinary = append(matchedBinary, binary)
+		}
+	}
+
+	// MinMatch takes precedence over Condition (see Matcher.MinMatch), so an
+	// N-of-M threshold must be checked before falling through to NOT.
+	if matcher.MinMatch > 0 {
+		if len(matchedBinary) >= matcher.MinMatch {
+			return true, matchedBinary
+		}
+		return false, []string{}
+	}
+	if matcher.condition == NOTCondition {
+		if len(matchedBinary) == 0 {
+			return true, []string{}
+		}
+		return false, []string{}
+	}
+	return false, []string{}
+}
+
 // MatchDSL matches on a generic map result
 func (matcher *Matcher) MatchDSL(data map[string]interface{}) bool {
+	// NOT and N-of-M threshold conditions need to see every expression before
+	// deciding, so they can't use the AND/OR short-circuiting loop below.
+	if matcher.condition == NOTCondition || matcher.MinMatch > 0 {
+		return matcher.matchDSLThreshold(data)
+	}
+
 	// Iterate over all the expressions accepted as valid
 	for i, expression := range matcher.dslCompiled {
 		result, err := expression.Evaluate(data)
@@ -179,3 +295,26 @@ func (matcher *Matcher) MatchDSL(data map[string]interface{}) bool {
 	}
 	return false
 }
+
+// matchDSLThreshold evaluates a NOT condition or an N-of-M MinMatch
+// threshold across all configured dsl expressions, evaluating every entry
+// instead of short-circuiting like the AND/OR path.
+func (matcher *Matcher) matchDSLThreshold(data map[string]interface{}) bool {
+	matchedCount := 0
+	for _, expression := range matcher.dslCompiled {
+		result, err := expression.Evaluate(data)
+		if err != nil {
+			continue
+		}
+		if bResult, ok := result.(bool); ok && bResult {
+			matchedCount++
+		}
+	}
+
+	// MinMatch takes precedence over Condition (see Matcher.MinMatch), so an
+	// N-of-M threshold must be checked before falling through to NOT.
+	if matcher.MinMatch > 0 {
+		return matchedCount >= matcher.MinMatch
+	}
+	return matcher.condition == NOTCondition && matchedCount == 0
+}
@@ -28,3 +28,35 @@ func TestORCondition(t *testing.T) {
 	matched = m.MatchWords("c")
 	require.False(t, matched, "Could match invalid OR condition")
 }
+
+func TestNOTCondition(t *testing.T) {
+	m := &Matcher{condition: NOTCondition, Words: []string{"a", "b"}}
+
+	matched, _ := m.MatchWords("c", nil)
+	require.True(t, matched, "Could not match valid NOT condition")
+
+	matched, _ = m.MatchWords("a", nil)
+	require.False(t, matched, "Could match invalid NOT condition")
+}
+
+func TestMinMatchThreshold(t *testing.T) {
+	m := &Matcher{MinMatch: 2, Words: []string{"a", "b", "c"}}
+
+	matched, _ := m.MatchWords("a b", nil)
+	require.True(t, matched, "Could not match valid N-of-M threshold condition")
+
+	matched, _ = m.MatchWords("a", nil)
+	require.False(t, matched, "Could match invalid N-of-M threshold condition")
+}
+
+func TestMinMatchTakesPrecedenceOverNOTCondition(t *testing.T) {
+	// MinMatch must be honored even when Condition is "not", per
+	// Matcher.MinMatch's documented precedence.
+	m := &Matcher{condition: NOTCondition, MinMatch: 2, Words: []string{"a", "b", "c"}}
+
+	matched, _ := m.MatchWords("a b", nil)
+	require.True(t, matched, "Could not match valid N-of-M threshold condition combined with NOT")
+
+	matched, _ = m.MatchWords("a", nil)
+	require.False(t, matched, "Could match invalid N-of-M threshold condition combined with NOT")
+}
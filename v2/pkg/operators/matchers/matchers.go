@@ -0,0 +1,75 @@
+package matchers
+
+import (
+	"regexp"
+
+	"github.com/Knetic/govaluate"
+)
+
+// ConditionType is the type of condition used to combine the multiple
+// values configured on a single matcher.
+type ConditionType int
+
+// Supported conditions for a matcher's values.
+const (
+	// ANDCondition requires all the configured values to match.
+	ANDCondition ConditionType = iota
+	// ORCondition requires at least one of the configured values to match.
+	ORCondition
+	// NOTCondition requires none of the configured values to match.
+	NOTCondition
+)
+
+// conditionMappings maps the condition as read from a template to its
+// corresponding ConditionType.
+var conditionMappings = map[string]ConditionType{
+	"and": ANDCondition,
+	"or":  ORCondition,
+	"not": NOTCondition,
+}
+
+// ConditionTypeHolder is used to hold internal type of the condition
+func toCondition(condition string) ConditionType {
+	if c, ok := conditionMappings[condition]; ok {
+		return c
+	}
+	return ORCondition
+}
+
+// Matcher is used to match a part in the output from a protocol.
+type Matcher struct {
+	// Status are the acceptable status codes for the response.
+	Status []int `yaml:"status,omitempty"`
+	// Size is the acceptable size for the response
+	Size []int `yaml:"size,omitempty"`
+	// Words contains word patterns required to be present in the response.
+	Words []string `yaml:"words,omitempty"`
+	// Regex contains regex patterns required to be present in the response.
+	Regex []string `yaml:"regex,omitempty"`
+	// Binary are the binary patterns required to be present in the response.
+	Binary []string `yaml:"binary,omitempty"`
+	// DSL are the dsl expressions that will be evaluated as part of the
+	// matching rules.
+	DSL []string `yaml:"dsl,omitempty"`
+	// Condition is the optional condition between the matcher's values.
+	// Supported values are and, or and not, defaulting to or.
+	Condition string `yaml:"condition,omitempty"`
+	// MinMatch requires at least MinMatch of the matcher's configured values
+	// to match (an N-of-M threshold) before the matcher succeeds. When unset
+	// or zero, the matcher falls back to its AND/OR/NOT Condition instead.
+	// MinMatch takes precedence over Condition when set.
+	MinMatch int `yaml:"min-match,omitempty"`
+	// CaseInsensitive enables case-insensitive matches
+	CaseInsensitive bool `yaml:"case-insensitive,omitempty"`
+
+	condition     ConditionType
+	regexCompiled []*regexp.Regexp
+	dslCompiled   []*govaluate.EvaluableExpression
+	binaryDecoded []string
+}
+
+// CompileMatchers compiles the condition and any derived internal state for
+// the matcher ahead of use.
+func (matcher *Matcher) CompileMatchers() {
+	matcher.condition = toCondition(matcher.Condition)
+}
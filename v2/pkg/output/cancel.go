@@ -0,0 +1,56 @@
+package output
+
+import (
+	"context"
+
+	"go.uber.org/atomic"
+)
+
+// cancelReasonKey is the context value key marking whether a context created
+// by WithCancel was explicitly cancelled, as opposed to a parent deadline
+// (e.g. TemplateTimeout/PerTargetTimeout/GlobalDeadline) elapsing on its own.
+type cancelReasonKey struct{}
+
+// WithCancel is context.WithCancel's counterpart for scan cancellation: the
+// returned CancelFunc marks ctx (and every context derived from it) as
+// explicitly cancelled before tearing it down, so WasCancelled can tell a
+// caller-initiated stop apart from a timeout budget simply running out.
+// pkg/server's POST /scans/{id}/cancel and the gRPC Cancel RPC both use this
+// to build the context a running scan's requests ultimately inherit.
+func WithCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	cancelled := atomic.NewBool(false)
+	ctx, cancel := context.WithCancel(parent)
+	ctx = context.WithValue(ctx, cancelReasonKey{}, cancelled)
+	return ctx, func() {
+		cancelled.Store(true)
+		cancel()
+	}
+}
+
+// WasCancelled reports whether ctx's cancellation, if any, came from a
+// WithCancel CancelFunc rather than a deadline elapsing. It returns false for
+// a context that carries no cancellation marker at all (e.g. plain
+// context.Background(), or one only ever wrapped with context.WithTimeout).
+func WasCancelled(ctx context.Context) bool {
+	cancelled, ok := ctx.Value(cancelReasonKey{}).(*atomic.Bool)
+	return ok && cancelled.Load()
+}
+
+// CancelledError reports that a request aborted because its scan was
+// explicitly cancelled (see WithCancel) rather than because
+// TemplateTimeout/PerTargetTimeout/GlobalDeadline elapsed. Writer.Request
+// implementations that persist trace data (e.g. the JSON trace/error log)
+// can use errors.As against this to distinguish an operator-initiated stop
+// from the target simply being too slow, instead of logging every
+// ctx.Err() as an identical, generic timeout.
+type CancelledError struct {
+	Err error
+}
+
+func (e *CancelledError) Error() string {
+	return "request aborted: scan was cancelled: " + e.Err.Error()
+}
+
+func (e *CancelledError) Unwrap() error {
+	return e.Err
+}
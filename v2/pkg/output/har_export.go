@@ -0,0 +1,191 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// This file converts the RequestTraces a StandardWriter accumulates over a
+// run into a HAR 1.2 archive (https://w3c.github.io/web-performance/specs/HAR/Overview.html),
+// for loading into Chrome DevTools or Burp for post-scan review. It's a
+// separate export from the JSONL trace log written alongside it: the JSONL
+// log is nuclei's own trace format and stays byte-for-byte the same shape
+// it's always had, while this is a translation of the same underlying
+// trace data into a format third-party tooling already knows how to open.
+
+const harVersion = "1.2"
+
+type harEntry struct {
+	trace     *RequestTrace
+	startedAt time.Time
+	input     string
+}
+
+type harLog struct {
+	Log harLogBody `json:"log"`
+}
+
+type harLogBody struct {
+	Version string         `json:"version"`
+	Creator harCreator     `json:"creator"`
+	Entries []harEntryJSON `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntryJSON struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harHeader  `json:"headers"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+	PostData    *harPostData `json:"postData,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+	RedirectURL string      `json:"redirectURL"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// writeHAR builds a HAR 1.2 archive from every traced request this writer
+// has accumulated and writes it to w.harFile.
+func (w *StandardWriter) writeHAR() error {
+	w.harMu.Lock()
+	entries := w.harEntries
+	w.harMu.Unlock()
+
+	har := harLog{Log: harLogBody{
+		Version: harVersion,
+		Creator: harCreator{Name: "nuclei", Version: w.toolVersion},
+	}}
+	for _, e := range entries {
+		har.Log.Entries = append(har.Log.Entries, harEntryFromTrace(e))
+	}
+
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(w.harFile, data, 0644)
+}
+
+func harEntryFromTrace(e harEntry) harEntryJSON {
+	req, _ := http.ReadRequest(bufio.NewReader(strings.NewReader(e.trace.RawRequest)))
+	resp, _ := http.ReadResponse(bufio.NewReader(strings.NewReader(e.trace.RawResponse)), nil)
+
+	entry := harEntryJSON{
+		StartedDateTime: e.startedAt.Format(time.RFC3339Nano),
+		Time:            durationMillis(e.trace.Timings.Total),
+		Timings: harTimings{
+			DNS:     durationMillis(e.trace.Timings.DNS),
+			Connect: durationMillis(e.trace.Timings.Connect),
+			SSL:     durationMillis(e.trace.Timings.TLSHandshake),
+			Wait:    durationMillis(e.trace.Timings.FirstByte),
+			Send:    -1,
+			Receive: -1,
+		},
+	}
+
+	method := "GET"
+	url := e.input
+	httpVersion := "HTTP/1.1"
+	var reqHeaders []harHeader
+	if req != nil {
+		method = req.Method
+		url = req.URL.String()
+		httpVersion = req.Proto
+		reqHeaders = harHeadersFrom(req.Header)
+	}
+	entry.Request = harRequest{
+		Method:      method,
+		URL:         url,
+		HTTPVersion: httpVersion,
+		Headers:     reqHeaders,
+		BodySize:    len(e.trace.RawRequest),
+	}
+
+	status := 0
+	statusText := ""
+	respHTTPVersion := "HTTP/1.1"
+	var respHeaders []harHeader
+	if resp != nil {
+		status = resp.StatusCode
+		statusText = resp.Status
+		respHTTPVersion = resp.Proto
+		respHeaders = harHeadersFrom(resp.Header)
+	}
+	entry.Response = harResponse{
+		Status:      status,
+		StatusText:  statusText,
+		HTTPVersion: respHTTPVersion,
+		Headers:     respHeaders,
+		Content:     harContent{Size: len(e.trace.RawResponse), MimeType: "text/plain", Text: e.trace.RawResponse},
+		BodySize:    len(e.trace.RawResponse),
+	}
+	if len(e.trace.Redirects) > 0 {
+		entry.Response.RedirectURL = e.trace.Redirects[len(e.trace.Redirects)-1]
+	}
+	return entry
+}
+
+func harHeadersFrom(header map[string][]string) []harHeader {
+	var headers []harHeader
+	for name, values := range header {
+		for _, value := range values {
+			headers = append(headers, harHeader{Name: textproto.CanonicalMIMEHeaderKey(name), Value: value})
+		}
+	}
+	return headers
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d.Milliseconds())
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -11,8 +12,10 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/logrusorgru/aurora"
 
+	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/interactsh/pkg/server"
 	"github.com/projectdiscovery/nuclei/v2/internal/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/enrichment"
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
 	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
@@ -27,8 +30,39 @@ type Writer interface {
 	Colorizer() aurora.Aurora
 	// Write writes the event to file and/or screen.
 	Write(*ResultEvent) error
-	// Request logs a request in the trace log
-	Request(templateID, url, requestType string, err error)
+	// Request logs a request in the trace log. trace carries the raw
+	// request/response bytes, timing breakdown, and redirect chain for
+	// protocols that capture them (currently http); it's nil for
+	// protocols that don't (e.g. dns).
+	Request(templateID, url, requestType string, err error, trace *RequestTrace)
+}
+
+// RequestTrace carries the raw bytes, timing breakdown, and redirect chain
+// of a single traced request, for protocols rich enough to capture them.
+// It's plumbed from the protocol's executor (e.g. pkg/protocols/http) into
+// Writer.Request, where a StandardWriter folds it into the JSONL trace log
+// and, when HARFile is set, an accumulated HAR 1.2 export.
+type RequestTrace struct {
+	// RawRequest is the dumped request, headers and body included.
+	RawRequest string
+	// RawResponse is the dumped response, headers and body included.
+	RawResponse string
+	// Timings is the per-phase timing breakdown of the request.
+	Timings RequestTimings
+	// Redirects is the chain of URLs followed before reaching the final
+	// response, in the order they were visited.
+	Redirects []string
+}
+
+// RequestTimings is a per-phase timing breakdown of a single traced request.
+// Phases that don't apply to a given transport (e.g. DNS lookup for a
+// request against a cached connection) are left zero.
+type RequestTimings struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	Total        time.Duration
 }
 
 // StandardWriter is a writer writing output to file and screen for results.
@@ -42,6 +76,20 @@ type StandardWriter struct {
 	traceFile      io.WriteCloser
 	errorFile      io.WriteCloser
 	severityColors func(severity.Severity) string
+
+	// sarifExport, when non-empty, is the file a SARIF 2.1.0 log of every
+	// written ResultEvent is flushed to on Close.
+	sarifExport      string
+	toolVersion      string
+	templatesVersion string
+	sarifMu          sync.Mutex
+	sarifEvents      []*ResultEvent
+
+	// harFile, when non-empty, is the file a HAR 1.2 archive of every
+	// traced request carrying a non-nil RequestTrace is flushed to on Close.
+	harFile    string
+	harMu      sync.Mutex
+	harEntries []harEntry
 }
 
 var decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -94,10 +142,18 @@ type ResultEvent struct {
 	// Only applicable if the report is for HTTP.
 	CURLCommand         string         `json:"curl-command,omitempty"`
 	FileToIndexPosition map[string]int `json:"-"`
+	// Enrichment holds CVE/CWE vulnerability data looked up for this
+	// finding's template, when info.classification carried an ID the
+	// configured enrichment.Enricher recognized. Nil unless enrichment is
+	// enabled and the lookup succeeded.
+	Enrichment *enrichment.Result `json:"enrichment,omitempty"`
 }
 
-// NewStandardWriter creates a new output writer based on user configurations
-func NewStandardWriter(colors, noMetadata, noTimestamp, json, jsonReqResp bool, file, traceFile string, errorFile string) (*StandardWriter, error) {
+// NewStandardWriter creates a new output writer based on user configurations.
+// toolVersion/templatesVersion populate tool.driver in the SARIF log written
+// to sarifExport (if set) once Close is called. harFile, if set, receives a
+// HAR 1.2 archive built from every traced request carrying a RequestTrace.
+func NewStandardWriter(colors, noMetadata, noTimestamp, json, jsonReqResp bool, file, traceFile string, errorFile string, toolVersion, templatesVersion, sarifExport, harFile string) (*StandardWriter, error) {
 	auroraColorizer := aurora.NewAurora(colors)
 
 	var outputFile io.WriteCloser
@@ -125,15 +181,19 @@ func NewStandardWriter(colors, noMetadata, noTimestamp, json, jsonReqResp bool,
 		errorOutput = output
 	}
 	writer := &StandardWriter{
-		json:           json,
-		jsonReqResp:    jsonReqResp,
-		noMetadata:     noMetadata,
-		noTimestamp:    noTimestamp,
-		aurora:         auroraColorizer,
-		outputFile:     outputFile,
-		traceFile:      traceOutput,
-		errorFile:      errorOutput,
-		severityColors: colorizer.New(auroraColorizer),
+		json:             json,
+		jsonReqResp:      jsonReqResp,
+		noMetadata:       noMetadata,
+		noTimestamp:      noTimestamp,
+		aurora:           auroraColorizer,
+		outputFile:       outputFile,
+		traceFile:        traceOutput,
+		errorFile:        errorOutput,
+		severityColors:   colorizer.New(auroraColorizer),
+		sarifExport:      sarifExport,
+		toolVersion:      toolVersion,
+		templatesVersion: templatesVersion,
+		harFile:          harFile,
 	}
 	return writer, nil
 }
@@ -142,6 +202,12 @@ func NewStandardWriter(colors, noMetadata, noTimestamp, json, jsonReqResp bool,
 func (w *StandardWriter) Write(event *ResultEvent) error {
 	event.Timestamp = time.Now()
 
+	if w.sarifExport != "" {
+		w.sarifMu.Lock()
+		w.sarifEvents = append(w.sarifEvents, event)
+		w.sarifMu.Unlock()
+	}
+
 	var data []byte
 	var err error
 
@@ -175,10 +241,25 @@ type JSONLogRequest struct {
 	Input    string `json:"input"`
 	Error    string `json:"error"`
 	Type     string `json:"type"`
+	// RawRequest/RawResponse, Timings, and Redirects are only populated
+	// when the protocol recorded a RequestTrace; otherwise they're
+	// omitted, keeping the JSONL trace log unchanged for protocols (e.g.
+	// dns) that don't capture them.
+	RawRequest  string          `json:"raw-request,omitempty"`
+	RawResponse string          `json:"raw-response,omitempty"`
+	Timings     *RequestTimings `json:"timings,omitempty"`
+	Redirects   []string        `json:"redirects,omitempty"`
 }
 
-// Request writes a log the requests trace log
-func (w *StandardWriter) Request(templatePath, input, requestType string, requestErr error) {
+// Request writes a log the requests trace log, and, if HARFile is set,
+// accumulates trace into the in-progress HAR export.
+func (w *StandardWriter) Request(templatePath, input, requestType string, requestErr error, trace *RequestTrace) {
+	if w.harFile != "" && trace != nil {
+		w.harMu.Lock()
+		w.harEntries = append(w.harEntries, harEntry{trace: trace, startedAt: time.Now(), input: input})
+		w.harMu.Unlock()
+	}
+
 	if w.traceFile == nil && w.errorFile == nil {
 		return
 	}
@@ -187,6 +268,12 @@ func (w *StandardWriter) Request(templatePath, input, requestType string, reques
 		Input:    input,
 		Type:     requestType,
 	}
+	if trace != nil {
+		request.RawRequest = trace.RawRequest
+		request.RawResponse = trace.RawResponse
+		request.Timings = &trace.Timings
+		request.Redirects = trace.Redirects
+	}
 	if unwrappedErr := utils.UnwrapError(requestErr); unwrappedErr != nil {
 		request.Error = unwrappedErr.Error()
 	} else {
@@ -223,12 +310,22 @@ func (w *StandardWriter) Close() {
 	if w.errorFile != nil {
 		w.errorFile.Close()
 	}
+	if w.sarifExport != "" {
+		if err := w.writeSarif(); err != nil {
+			gologger.Warning().Msgf("Could not write sarif export: %s\n", err)
+		}
+	}
+	if w.harFile != "" {
+		if err := w.writeHAR(); err != nil {
+			gologger.Warning().Msgf("Could not write har export: %s\n", err)
+		}
+	}
 }
 
 // MockOutputWriter is a mocked output writer.
 type MockOutputWriter struct {
 	aurora          aurora.Aurora
-	RequestCallback func(templateID, url, requestType string, err error)
+	RequestCallback func(templateID, url, requestType string, err error, trace *RequestTrace)
 	WriteCallback   func(o *ResultEvent)
 }
 
@@ -254,8 +351,53 @@ func (m *MockOutputWriter) Write(result *ResultEvent) error {
 }
 
 // Request writes a log the requests trace log
-func (m *MockOutputWriter) Request(templateID, url, requestType string, err error) {
+func (m *MockOutputWriter) Request(templateID, url, requestType string, err error, trace *RequestTrace) {
 	if m.RequestCallback != nil {
-		m.RequestCallback(templateID, url, requestType, err)
+		m.RequestCallback(templateID, url, requestType, err, trace)
+	}
+}
+
+// MultiWriter fans a single stream of events out to multiple underlying
+// writers, so e.g. the console StandardWriter and an in-memory store for
+// the HTTP server can both observe the same scan concurrently.
+type MultiWriter struct {
+	writers []Writer
+}
+
+// NewMultiWriter creates a writer which duplicates every call to each of writers.
+func NewMultiWriter(writers ...Writer) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+// Close closes every underlying writer.
+func (m *MultiWriter) Close() {
+	for _, w := range m.writers {
+		w.Close()
+	}
+}
+
+// Colorizer returns the colorizer instance of the first underlying writer.
+func (m *MultiWriter) Colorizer() aurora.Aurora {
+	if len(m.writers) == 0 {
+		return aurora.NewAurora(false)
+	}
+	return m.writers[0].Colorizer()
+}
+
+// Write writes the event to every underlying writer, returning the first error encountered.
+func (m *MultiWriter) Write(result *ResultEvent) error {
+	var firstErr error
+	for _, w := range m.writers {
+		if err := w.Write(result); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Request logs a request in the trace log of every underlying writer.
+func (m *MultiWriter) Request(templateID, url, requestType string, err error, trace *RequestTrace) {
+	for _, w := range m.writers {
+		w.Request(templateID, url, requestType, err, trace)
 	}
 }
@@ -0,0 +1,165 @@
+// Package sarif builds SARIF (Static Analysis Results Interchange Format)
+// 2.1.0 documents from aggregated nuclei findings, so results can be
+// ingested by code-scanning dashboards (e.g. GitHub code scanning).
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+)
+
+const (
+	schema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	version = "2.1.0"
+
+	toolName = "nuclei"
+)
+
+// Log is the top level SARIF document.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run describes a single run of nuclei over a set of targets.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the tool that produced the run's results.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver describes nuclei itself and the rules (templates) it ran.
+type Driver struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Rule describes a single nuclei template as a SARIF rule.
+type Rule struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name,omitempty"`
+	ShortDescription Message `json:"shortDescription,omitempty"`
+}
+
+// Message is a SARIF plain-text message.
+type Message struct {
+	Text string `json:"text,omitempty"`
+}
+
+// Result is a single finding, associated with the Rule (template) that produced it.
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+// Location points at the artifact (target URL) a Result was found at.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation wraps the ArtifactLocation for a Location.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+// ArtifactLocation identifies the matched URL/host a Result was found at.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// severityLevels maps nuclei's severities to SARIF's result levels.
+var severityLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "note",
+	"info":     "note",
+	"unknown":  "note",
+}
+
+// levelForSeverity returns the SARIF level for a nuclei severity string,
+// defaulting to "note" for anything unrecognized.
+func levelForSeverity(severity string) string {
+	if level, ok := severityLevels[severity]; ok {
+		return level
+	}
+	return "note"
+}
+
+// Build converts a report.Report's per-host findings into a SARIF Log with
+// a single run, deduplicating templates into rules.
+func Build(toolVersion string, r *report.Report) *Log {
+	driver := Driver{Name: toolName, Version: toolVersion}
+	run := Run{}
+
+	ruleSeen := make(map[string]struct{})
+	for _, host := range r.Hosts {
+		for _, tpl := range host.Templates {
+			if _, ok := ruleSeen[tpl.TemplateID]; !ok {
+				ruleSeen[tpl.TemplateID] = struct{}{}
+				driver.Rules = append(driver.Rules, Rule{
+					ID:               tpl.TemplateID,
+					Name:             tpl.Info["name"],
+					ShortDescription: Message{Text: tpl.Info["name"]},
+				})
+			}
+
+			matched := tpl.Matched
+			if len(matched) == 0 {
+				matched = []string{host.Host}
+			}
+			for _, uri := range matched {
+				run.Results = append(run.Results, Result{
+					RuleID: tpl.TemplateID,
+					Level:  levelForSeverity(tpl.Severity),
+					Message: Message{
+						Text: tpl.TemplateID + " matched at " + uri,
+					},
+					Locations: []Location{
+						{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: uri}}},
+					},
+					PartialFingerprints: map[string]string{
+						"nucleiFingerprint/v1": fingerprint(tpl.TemplateID, uri),
+					},
+				})
+			}
+		}
+	}
+
+	run.Tool = Tool{Driver: driver}
+	return &Log{Schema: schema, Version: version, Runs: []Run{run}}
+}
+
+// fingerprint derives a stable, opaque identifier for a (template, location)
+// pair, used by code-scanning dashboards to de-duplicate results across runs.
+func fingerprint(templateID, uri string) string {
+	sum := sha256.Sum256([]byte(templateID + "|" + uri))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteFile marshals log as indented JSON and writes it to path.
+func WriteFile(path string, log *Log) error {
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal sarif log")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write sarif log")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package sarif
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+)
+
+func TestBuild(t *testing.T) {
+	r := report.New([]string{"cves/"}, 1, 150)
+	r.AddFinding(report.Finding{
+		Host:         "https://example.com",
+		TemplateID:   "CVE-2021-1234",
+		TemplateInfo: map[string]string{"name": "Example RCE"},
+		Severity:     "critical",
+		CVEID:        "CVE-2021-1234",
+		Matched:      "https://example.com/vuln",
+	})
+
+	log := Build("2.9.0", r)
+	if len(log.Runs) != 1 {
+		t.Fatalf("expected a single run, got %d", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "CVE-2021-1234" {
+		t.Fatalf("expected a single rule for CVE-2021-1234, got %v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("expected a single result, got %d", len(run.Results))
+	}
+	result := run.Results[0]
+	if result.Level != "error" {
+		t.Fatalf("expected critical severity to map to error level, got %s", result.Level)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "https://example.com/vuln" {
+		t.Fatalf("unexpected locations: %v", result.Locations)
+	}
+	if result.PartialFingerprints["nucleiFingerprint/v1"] == "" {
+		t.Fatalf("expected a non-empty fingerprint")
+	}
+}
+
+func TestLevelForSeverity(t *testing.T) {
+	cases := map[string]string{
+		"critical": "error",
+		"high":     "error",
+		"medium":   "warning",
+		"low":      "note",
+		"info":     "note",
+		"":         "note",
+		"bogus":    "note",
+	}
+	for severity, expected := range cases {
+		if got := levelForSeverity(severity); got != expected {
+			t.Errorf("levelForSeverity(%q) = %q, want %q", severity, got, expected)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// This file builds a SARIF 2.1.0 log directly from the ResultEvents a
+// StandardWriter has written, for the -sarif flag. It deliberately keeps
+// its own, unexported SARIF types instead of reusing pkg/output/sarif's
+// exported ones: that package builds a SARIF log from a pkg/report.Report
+// (an end-of-run aggregate over every host, decoupled from output.ResultEvent
+// so it stays independently testable), while this one streams straight off
+// the ResultEvents this writer already sees, carrying per-result CVSS/CWE
+// properties pkg/report's simpler model doesn't capture, so the two SARIF
+// builders stay deliberately separate.
+
+const sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	FullDescription  sarifMessage           `json:"fullDescription,omitempty"`
+	HelpURI          string                 `json:"helpUri,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLevelForSeverity maps a template severity to a SARIF result level:
+// critical/high -> error, medium -> warning, low/info/unknown -> note.
+func sarifLevelForSeverity(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// writeSarif builds a SARIF 2.1.0 log from every event this writer has
+// seen and writes it to w.sarifExport.
+func (w *StandardWriter) writeSarif() error {
+	w.sarifMu.Lock()
+	events := w.sarifEvents
+	w.sarifMu.Unlock()
+
+	driver := sarifDriver{
+		Name:           "nuclei",
+		Version:        w.toolVersion,
+		InformationURI: "https://github.com/projectdiscovery/nuclei",
+	}
+
+	rulesByID := make(map[string]int)
+	var results []sarifResult
+	for _, event := range events {
+		ruleIdx, ok := rulesByID[event.TemplateID]
+		if !ok {
+			driver.Rules = append(driver.Rules, sarifRuleFromEvent(event))
+			ruleIdx = len(driver.Rules) - 1
+			rulesByID[event.TemplateID] = ruleIdx
+		}
+		results = append(results, sarifResultFromEvent(event))
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(w.sarifExport, data, 0644)
+}
+
+func sarifRuleFromEvent(event *ResultEvent) sarifRule {
+	rule := sarifRule{
+		ID:               event.TemplateID,
+		Name:             event.Info.Name,
+		ShortDescription: sarifMessage{Text: event.Info.Name},
+	}
+	if event.Info.Description != "" {
+		rule.FullDescription = sarifMessage{Text: event.Info.Description}
+	}
+	if len(event.Info.Reference.ToSlice()) > 0 {
+		rule.HelpURI = event.Info.Reference.ToSlice()[0]
+	}
+	if len(event.Info.Tags.ToSlice()) > 0 {
+		rule.Properties = map[string]interface{}{"tags": event.Info.Tags.ToSlice()}
+	}
+	return rule
+}
+
+func sarifResultFromEvent(event *ResultEvent) sarifResult {
+	uri := event.Host
+	if event.Matched != "" {
+		uri = event.Matched
+	}
+
+	properties := map[string]interface{}{}
+	if event.Info.Classification != nil {
+		if event.Info.Classification.CVSSScore != 0 {
+			properties["cvss-score"] = event.Info.Classification.CVSSScore
+		}
+		if len(event.Info.Classification.CWEID) > 0 {
+			properties["cwe-id"] = event.Info.Classification.CWEID
+		}
+		if len(event.Info.Classification.CVEID) > 0 {
+			properties["cve-id"] = event.Info.Classification.CVEID
+		}
+	}
+
+	return sarifResult{
+		RuleID: event.TemplateID,
+		Level:  sarifLevelForSeverity(event.Info.SeverityHolder.Severity.String()),
+		Message: sarifMessage{
+			Text: fmt.Sprintf("%s matched at %s", event.Info.Name, event.Host),
+		},
+		Locations: []sarifLocation{
+			{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}},
+		},
+		PartialFingerprints: map[string]string{
+			"nucleiResultFingerprint/v1": sarifFingerprint(event.TemplateID, event.Matched),
+		},
+		Properties: properties,
+	}
+}
+
+// sarifFingerprint derives a stable de-dup key for a (template, matched)
+// pair, so the same finding across successive scans is recognized as a
+// single issue by code-scanning dashboards instead of a new one each time.
+func sarifFingerprint(templateID, matched string) string {
+	sum := sha256.Sum256([]byte(templateID + "|" + matched))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// StreamFilter narrows the ResultEvents a subscriber receives from a
+// StreamWriter. A zero-value StreamFilter matches everything. Each
+// non-empty field is ANDed together; TemplateIDs/Hosts match if the event's
+// value is present anywhere in the respective slice.
+type StreamFilter struct {
+	Severity    []string
+	TemplateIDs []string
+	Hosts       []string
+}
+
+func (f StreamFilter) matches(event *ResultEvent) bool {
+	if len(f.Severity) > 0 && !containsFold(f.Severity, event.Info.SeverityHolder.Severity.String()) {
+		return false
+	}
+	if len(f.TemplateIDs) > 0 && !containsFold(f.TemplateIDs, event.TemplateID) {
+		return false
+	}
+	if len(f.Hosts) > 0 && !containsFold(f.Hosts, event.Host) {
+		return false
+	}
+	return true
+}
+
+func containsFold(haystack []string, needle string) bool {
+	for _, candidate := range haystack {
+		if strings.EqualFold(candidate, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamWriter is an output.Writer that fans every written ResultEvent out
+// to an arbitrary number of live subscribers instead of a file or the
+// screen, for nuclei's daemon mode: pkg/server/stream's gRPC and WebSocket
+// endpoints each hold one subscription per connected client, so dashboards
+// and orchestrators can watch a scan's findings as they happen instead of
+// polling pkg/store after the fact.
+type StreamWriter struct {
+	mu          sync.RWMutex
+	subscribers map[string]*streamSubscriber
+}
+
+type streamSubscriber struct {
+	filter StreamFilter
+	ch     chan *ResultEvent
+}
+
+// subscriberBufferSize is how many unconsumed events a subscriber's channel
+// holds before Write starts dropping events for it rather than blocking the
+// whole scan on a slow client.
+const subscriberBufferSize = 64
+
+var _ Writer = &StreamWriter{}
+
+// NewStreamWriter creates a new, empty StreamWriter.
+func NewStreamWriter() *StreamWriter {
+	return &StreamWriter{subscribers: make(map[string]*streamSubscriber)}
+}
+
+// Subscribe registers a new subscriber matching filter and returns its ID,
+// the channel it will receive matching ResultEvents on, and a cancel func
+// that unregisters it and closes the channel. Callers must keep draining
+// the channel (or call cancel) once they're done, or Write will start
+// dropping events for it once its buffer fills.
+func (s *StreamWriter) Subscribe(filter StreamFilter) (id string, events <-chan *ResultEvent, cancel func()) {
+	id = newSubscriberID()
+	sub := &streamSubscriber{filter: filter, ch: make(chan *ResultEvent, subscriberBufferSize)}
+
+	s.mu.Lock()
+	s.subscribers[id] = sub
+	s.mu.Unlock()
+
+	return id, sub.ch, func() { s.unsubscribe(id) }
+}
+
+func (s *StreamWriter) unsubscribe(id string) {
+	s.mu.Lock()
+	sub, ok := s.subscribers[id]
+	delete(s.subscribers, id)
+	s.mu.Unlock()
+	if ok {
+		close(sub.ch)
+	}
+}
+
+// Write fans event out to every subscriber whose filter matches it. A
+// subscriber whose channel is full has the event dropped for it rather
+// than blocking the scan.
+func (s *StreamWriter) Write(event *ResultEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sub := range s.subscribers {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close unregisters and closes every subscriber's channel.
+func (s *StreamWriter) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sub := range s.subscribers {
+		close(sub.ch)
+		delete(s.subscribers, id)
+	}
+}
+
+// Colorizer returns a no-op colorizer; StreamWriter has no screen output.
+func (s *StreamWriter) Colorizer() aurora.Aurora {
+	return aurora.NewAurora(false)
+}
+
+// Request is a no-op; StreamWriter only streams matched results, not the
+// request trace log.
+func (s *StreamWriter) Request(templateID, url, requestType string, err error, trace *RequestTrace) {}
+
+func newSubscriberID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "sub-0"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -83,8 +83,26 @@ func validateMandatoryInfoFields(info *model.Info) error {
 	return nil
 }
 
+// parsedTemplatesCache is a bounded, content-addressed cache of parsed
+// templates. Entries are automatically invalidated the moment the
+// underlying file's (mtime, sha256) fingerprint changes, so a long-running
+// process (e.g. server/API mode) sees template edits without a restart;
+// InvalidateTemplateCache additionally allows a file-watcher or admin
+// endpoint to force a hot-reload.
 var parsedTemplatesCache = cache.New()
 
+// InvalidateTemplateCache drops templatePath from the parsed template cache,
+// forcing the next ParseTemplate call to re-read and re-parse it from disk.
+func InvalidateTemplateCache(templatePath string) {
+	parsedTemplatesCache.Invalidate(templatePath)
+}
+
+// TemplateCacheStats returns hit/miss/eviction counters for the parsed
+// template cache.
+func TemplateCacheStats() cache.Stats {
+	return parsedTemplatesCache.Stats()
+}
+
 // ParseTemplate parses a template and returns a *templates.Template structure
 func ParseTemplate(templatePath string) (*templates.Template, error) {
 	if value, err := parsedTemplatesCache.Has(templatePath); value != nil {
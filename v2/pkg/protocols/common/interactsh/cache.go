@@ -0,0 +1,213 @@
+package interactsh
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/karlseguin/ccache"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/atomic"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+var interactionsBucket = []byte("interactions")
+
+// requestCache correlates interactsh interaction IDs back to the request
+// that produced them. The full event (including its MakeResultEventFunc
+// closure) only ever lives in the in-memory ccache, which is all a single
+// process's polling loop needs; an optional bbolt-backed DBPath additionally
+// persists a JSON-serializable projection of each entry so a distributed
+// scan that's restarted mid-flight can still recognize and log interactions
+// for templates it already fired, even though it can no longer replay their
+// exact matcher/extractor logic (MakeResultEventFunc can't survive a
+// restart, so persisted entries are reported via a fallback, closure-free
+// path - see Client.pollCallback and writePersistedInteraction).
+type requestCache struct {
+	memory *ccache.Cache
+	db     *bolt.DB
+
+	// evicted is incremented whenever ccache discards an entry we didn't
+	// explicitly Delete ourselves (i.e. a genuine eviction rather than a
+	// match being consumed), for Client.Stats.
+	evicted *atomic.Int64
+	// expectingDelete tracks ids currently being removed via Delete, so the
+	// ccache OnDelete callback (which fires for every removal) can tell
+	// those apart from evictions.
+	mu              sync.Mutex
+	expectingDelete map[string]struct{}
+}
+
+// persistedRequestEvent is the durable projection of an internalRequestEvent:
+// everything about it that's plain data, with the makeResultFunc closure
+// necessarily dropped.
+type persistedRequestEvent struct {
+	TemplateID   string                 `json:"template_id"`
+	TemplatePath string                 `json:"template_path"`
+	Host         string                 `json:"host"`
+	Internal     map[string]interface{} `json:"internal"`
+	StoredAt     time.Time              `json:"stored_at"`
+}
+
+// cacheEntry is the value actually stored in the ccache, pairing the
+// request event with its own id since ccache.Item doesn't expose the key a
+// deleted item was stored under - onDelete needs it to tell an eviction
+// apart from an expected Delete.
+type cacheEntry struct {
+	id    string
+	event *internalRequestEvent
+}
+
+// newRequestCache creates the in-memory cache, opening dbPath as a bbolt
+// database for the persistent projection if dbPath is non-empty. evicted,
+// if non-nil, is incremented for every entry ccache discards that wasn't
+// already removed via Delete (see requestCache.evicted).
+func newRequestCache(cacheSize int64, dbPath string, evicted *atomic.Int64) (*requestCache, error) {
+	rc := &requestCache{evicted: evicted, expectingDelete: make(map[string]struct{})}
+
+	configure := ccache.Configure()
+	configure = configure.MaxSize(cacheSize)
+	configure = configure.OnDelete(func(item *ccache.Item) {
+		entry, ok := item.Value().(*cacheEntry)
+		if !ok {
+			return
+		}
+		rc.onDelete(entry.id)
+	})
+	rc.memory = ccache.New(configure)
+
+	if dbPath == "" {
+		return rc, nil
+	}
+	db, err := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open interactsh cache database")
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(interactionsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "could not initialize interactsh cache bucket")
+	}
+	rc.db = db
+	return rc, nil
+}
+
+// Set records event against id in the in-memory cache (for exact-match
+// correlation in this process) and, if a persistent database is configured,
+// also persists a serializable projection of it.
+func (rc *requestCache) Set(id string, event *internalRequestEvent, eviction time.Duration) {
+	rc.memory.Set(id, &cacheEntry{id: id, event: event}, eviction)
+	if rc.db == nil {
+		return
+	}
+	persisted := &persistedRequestEvent{
+		TemplateID:   interfaceToString(event.event.InternalEvent["template-id"]),
+		TemplatePath: interfaceToString(event.event.InternalEvent["template-path"]),
+		Host:         interfaceToString(event.event.InternalEvent["host"]),
+		Internal:     event.event.InternalEvent,
+		StoredAt:     time.Now(),
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return
+	}
+	_ = rc.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(interactionsBucket).Put([]byte(id), data)
+	})
+}
+
+// Get returns the in-memory entry for id, if present.
+func (rc *requestCache) Get(id string) *internalRequestEvent {
+	item := rc.memory.Get(id)
+	if item == nil {
+		return nil
+	}
+	entry, ok := item.Value().(*cacheEntry)
+	if !ok {
+		return nil
+	}
+	return entry.event
+}
+
+// GetPersisted returns the durable projection for id, if a persistent
+// database is configured and holds an entry for it - used as a fallback
+// once the in-memory cache (and so the original MakeResultEventFunc) is
+// gone, e.g. after a process restart.
+func (rc *requestCache) GetPersisted(id string) (*persistedRequestEvent, bool) {
+	if rc.db == nil {
+		return nil, false
+	}
+	var persisted *persistedRequestEvent
+	_ = rc.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(interactionsBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		persisted = &persistedRequestEvent{}
+		return json.Unmarshal(data, persisted)
+	})
+	return persisted, persisted != nil
+}
+
+// Delete removes id from both the in-memory cache and, if configured, the
+// persistent database.
+func (rc *requestCache) Delete(id string) {
+	rc.mu.Lock()
+	rc.expectingDelete[id] = struct{}{}
+	rc.mu.Unlock()
+
+	rc.memory.Delete(id)
+	if rc.db == nil {
+		return
+	}
+	_ = rc.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(interactionsBucket).Delete([]byte(id))
+	})
+}
+
+// onDelete is ccache's OnDelete callback: it fires for every entry removed
+// from the cache, whether via our own Delete (a match being consumed) or an
+// eviction (MaxSize exceeded or the entry's ttl elapsing first). Only the
+// latter counts against requestCache.evicted.
+func (rc *requestCache) onDelete(id string) {
+	rc.mu.Lock()
+	_, expected := rc.expectingDelete[id]
+	delete(rc.expectingDelete, id)
+	rc.mu.Unlock()
+
+	if !expected && rc.evicted != nil {
+		rc.evicted.Inc()
+	}
+}
+
+// Close releases the persistent database, if one is open.
+func (rc *requestCache) Close() error {
+	if rc.db == nil {
+		return nil
+	}
+	return rc.db.Close()
+}
+
+func interfaceToString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// writePersistedInteraction logs a best-effort match for a persisted entry
+// that survived a restart: it reports the persisted template ID, template
+// path and host, plus the interaction's protocol, through output.Writer
+// without the original template's matcher/extractor logic, since
+// MakeResultEventFunc cannot be reconstructed from disk.
+func writePersistedInteraction(out output.Writer, persisted *persistedRequestEvent, protocol string) {
+	_ = out.Write(&output.ResultEvent{
+		TemplateID:   persisted.TemplateID,
+		TemplatePath: persisted.TemplatePath,
+		Host:         persisted.Host,
+		Matched:      persisted.Host,
+		Type:         protocol,
+	})
+}
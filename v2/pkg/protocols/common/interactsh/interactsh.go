@@ -1,12 +1,14 @@
 package interactsh
 
 import (
+	"context"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/karlseguin/ccache"
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/interactsh/pkg/client"
 	"github.com/projectdiscovery/interactsh/pkg/server"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators"
@@ -17,15 +19,30 @@ import (
 
 // Client is a wrapped client for interactsh server.
 type Client struct {
-	// interactsh is a client for interactsh server.
+	// interactsh is a client for interactsh server. It's swapped out in
+	// place by swapToken when Options.Renewable is set, so access to it is
+	// guarded by busy.
 	interactsh *client.Client
 	// requests is a stored cache for interactsh-url->request-event data.
-	requests *ccache.Cache
+	requests *requestCache
 
 	dotHostname      string
 	eviction         time.Duration
 	pollDuration     time.Duration
 	cooldownDuration time.Duration
+
+	busy sync.Mutex
+	// stopRenewer cancels the background token-renewal goroutine started by
+	// New, if Options.Renewable was set. It's a no-op func if not.
+	stopRenewer context.CancelFunc
+	// stopPoller cancels the poll supervisor goroutine started by New (see
+	// startPollSupervisor).
+	stopPoller context.CancelFunc
+	// diagnostics is the optional HTTP listener exposing Stats, started when
+	// Options.DiagnosticAddr is set.
+	diagnostics *diagnosticsServer
+
+	stats stats
 }
 
 var interactshURLMarker = "{{interactsh-url}}"
@@ -49,6 +66,37 @@ type Options struct {
 	Output output.Writer
 	// Progress is the nuclei progress bar implementation.
 	Progress progress.Progress
+
+	// AuthToken is the bearer token used to authenticate against a
+	// self-hosted interactsh server that requires one, instead of the
+	// public instance's unauthenticated access.
+	AuthToken string
+	// TokenTTL is how long AuthToken is valid for. When set together with
+	// Renewable, a background goroutine renews it before expiry instead of
+	// letting the server start rejecting polls partway through a long scan.
+	TokenTTL time.Duration
+	// Renewable enables the background token-renewal goroutine. It has no
+	// effect unless both AuthToken and RenewToken are also set.
+	Renewable bool
+	// RenewToken is called to obtain a fresh AuthToken when the current one
+	// is nearing TokenTTL. Required when Renewable is set.
+	RenewToken func() (string, error)
+	// RenewBehavior controls how renewal failures are handled. Defaults to
+	// RenewBehaviorFatal.
+	RenewBehavior RenewBehavior
+
+	// PersistentCacheDBPath, if non-empty, additionally persists pending
+	// interaction requests to a bbolt database at this path, so a
+	// long-running distributed scan that outlives the originating nuclei
+	// process can still correlate OOB callbacks that arrive after a
+	// restart. See requestCache for the durability caveats this implies.
+	PersistentCacheDBPath string
+
+	// DiagnosticAddr, if non-empty, starts an HTTP listener on this address
+	// serving Client.Stats() as JSON at /stats and as Prometheus text at
+	// /metrics, so nuclei run as a long-lived service can be alerted on for
+	// OOB delivery loss.
+	DiagnosticAddr string
 }
 
 // New returns a new interactsh server client
@@ -60,33 +108,68 @@ func New(options *Options) (*Client, error) {
 
 	interactsh, err := client.New(&client.Options{
 		ServerURL:         options.ServerURL,
+		Token:             options.AuthToken,
 		PersistentSession: false,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create client")
 	}
-	configure := ccache.Configure()
-	configure = configure.MaxSize(options.CacheSize)
-	cache := ccache.New(configure)
 
 	interactClient := &Client{
 		interactsh:       interactsh,
 		eviction:         options.Eviction,
 		dotHostname:      "." + parsed.Host,
-		requests:         cache,
 		pollDuration:     options.PollDuration,
 		cooldownDuration: options.ColldownPeriod,
 	}
-	interactClient.interactsh.StartPolling(interactClient.pollDuration, func(interaction *server.Interaction) {
-		item := interactClient.requests.Get(interaction.UniqueID)
-		if item == nil {
-			return
+	cache, err := newRequestCache(options.CacheSize, options.PersistentCacheDBPath, &interactClient.stats.evictions)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create interactsh cache")
+	}
+	interactClient.requests = cache
+
+	pollCtx, stopPoller := context.WithCancel(context.Background())
+	interactClient.stopPoller = stopPoller
+	go interactClient.startPollSupervisor(pollCtx, interactClient.pollDuration, interactClient.pollCallback(options))
+
+	interactClient.stopRenewer = interactClient.startTokenRenewer(options)
+
+	if options.DiagnosticAddr != "" {
+		diagnostics, err := startDiagnosticsServer(options.DiagnosticAddr, interactClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not start interactsh diagnostics listener")
 		}
-		data, ok := item.Value().(*internalRequestEvent)
-		if !ok {
+		interactClient.diagnostics = diagnostics
+	}
+	return interactClient, nil
+}
+
+// pollCallback builds the callback StartPolling invokes for every
+// interaction reported by the interactsh server, correlating it against a
+// pending request in c.requests and writing the resulting match(es) through
+// options.Output.
+func (c *Client) pollCallback(options *Options) func(interaction *server.Interaction) {
+	return func(interaction *server.Interaction) {
+		data := c.requests.Get(interaction.UniqueID)
+		if data == nil {
+			c.stats.cacheMisses.Inc()
+			if persisted, ok := c.requests.GetPersisted(interaction.UniqueID); ok {
+				c.requests.Delete(interaction.UniqueID)
+				writePersistedInteraction(options.Output, persisted, interaction.Protocol)
+			}
 			return
 		}
-		interactClient.requests.Delete(interaction.UniqueID)
+		if data.matchers != nil {
+			matched, extracted := data.matchers.Evaluate(interaction)
+			if !matched {
+				return
+			}
+			for name, value := range extracted {
+				data.event.InternalEvent[name] = value
+			}
+		}
+		c.requests.Delete(interaction.UniqueID)
+		c.stats.interactionsReceived.Inc()
 
 		data.event.OperatorsResult = &operators.Result{
 			Matches: map[string]struct{}{strings.ToLower(interaction.Protocol): {}},
@@ -97,22 +180,37 @@ func New(options *Options) (*Client, error) {
 			_ = options.Output.Write(result)
 			options.Progress.IncrementMatched()
 		}
-	})
-	return interactClient, nil
+	}
 }
 
 // URL returns a new URL that can be interacted with
 func (c *Client) URL() string {
+	c.busy.Lock()
+	defer c.busy.Unlock()
 	return c.interactsh.URL()
 }
 
 // Close closes the interactsh clients after waiting for cooldown period.
 func (c *Client) Close() {
+	if c.stopRenewer != nil {
+		c.stopRenewer()
+	}
+	if c.stopPoller != nil {
+		c.stopPoller()
+	}
+	if c.diagnostics != nil {
+		_ = c.diagnostics.Close()
+	}
 	if c.cooldownDuration > 0 {
 		time.Sleep(c.cooldownDuration)
 	}
-	c.interactsh.StopPolling()
+	c.busy.Lock()
+	_ = c.interactsh.StopPolling()
 	c.interactsh.Close()
+	c.busy.Unlock()
+	if err := c.requests.Close(); err != nil {
+		gologger.Warning().Msgf("interactsh: could not close persistent cache: %s\n", err)
+	}
 }
 
 // ReplaceMarkers replaces the {{interactsh-url}} placeholders to actual
@@ -136,10 +234,24 @@ type MakeResultEventFunc func(wrapped *output.InternalWrappedEvent) []*output.Re
 type internalRequestEvent struct {
 	makeResultFunc MakeResultEventFunc
 	event          *output.InternalWrappedEvent
+	// matchers, if non-nil, is evaluated against every interaction reported
+	// for this request; the request only produces a result event for
+	// interactions that pass it. A nil matchers accepts every interaction,
+	// so any callback is a hit.
+	matchers *InteractionMatchers
 }
 
 // RequestEvent is the event for a network request sent by nuclei.
 func (c *Client) RequestEvent(interactshURL string, event *output.InternalWrappedEvent, makeResult MakeResultEventFunc) {
+	c.RequestEventWithMatchers(interactshURL, event, makeResult, nil)
+}
+
+// RequestEventWithMatchers is RequestEvent plus an interactsh_matchers
+// block (matchers pre-compiled via InteractionMatchers.Compile): the
+// interaction is only reported as a result if matchers.Evaluate passes, and
+// any values its extractors capture are merged into event's InternalEvent
+// so later requests in the template can reference them as {{name}}.
+func (c *Client) RequestEventWithMatchers(interactshURL string, event *output.InternalWrappedEvent, makeResult MakeResultEventFunc, interactionMatchers *InteractionMatchers) {
 	id := strings.TrimSuffix(interactshURL, c.dotHostname)
-	c.requests.Set(id, &internalRequestEvent{makeResultFunc: makeResult, event: event}, c.eviction)
+	c.requests.Set(id, &internalRequestEvent{makeResultFunc: makeResult, event: event, matchers: interactionMatchers}, c.eviction)
 }
@@ -0,0 +1,323 @@
+package interactsh
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+)
+
+// Supported InteractionMatcher/InteractionExtractor Part values. Unlike
+// protocols/http's matcher parts (which select a field of a single HTTP
+// response), these select a field of a server.Interaction - the OOB
+// callback itself, not the request nuclei made.
+const (
+	// PartDNSQType is the DNS question type of the interaction (e.g. A, TXT).
+	PartDNSQType = "dns.qtype"
+	// PartHTTPRequest is the interaction's raw HTTP request, headers included.
+	PartHTTPRequest = "http.request"
+	// PartHTTPBody is the interaction's raw HTTP request body, with its
+	// header block stripped.
+	PartHTTPBody = "http.body"
+	// PartSMTPFrom is the MAIL FROM address of an SMTP interaction.
+	PartSMTPFrom = "smtp.from"
+	// PartRaw is every raw field the interaction carries (request and
+	// response) concatenated, for matchers that don't care which protocol
+	// produced the callback.
+	PartRaw = "raw"
+)
+
+// InteractionMatcher matches a part of an interactsh server.Interaction,
+// mirroring the word/regex/dsl/condition vocabulary of
+// pkg/operators/matchers.Matcher so templates authors don't have to learn a
+// second DSL for OOB interactions, while matching against interaction
+// fields (dns.qtype, http.request, http.body, smtp.from, raw) that a
+// protocol response matcher has no access to.
+type InteractionMatcher struct {
+	// Part is the interaction field this matcher is evaluated against. See
+	// the Part* constants. Defaults to PartRaw.
+	Part string `yaml:"part,omitempty"`
+	// Words are substrings that must appear in Part's value.
+	Words []string `yaml:"words,omitempty"`
+	// Regex are regular expressions matched against Part's value.
+	Regex []string `yaml:"regex,omitempty"`
+	// DSL are govaluate expressions evaluated with the interaction's fields
+	// bound as variables (protocol, unique_id, full_id, q_type, raw_request,
+	// raw_response, smtp_from, remote_address).
+	DSL []string `yaml:"dsl,omitempty"`
+	// Condition is and/or between Words/Regex/DSL. Defaults to or.
+	Condition string `yaml:"condition,omitempty"`
+	// Negative inverts the matcher's result, for asserting an interaction
+	// does NOT contain something.
+	Negative bool `yaml:"negative,omitempty"`
+
+	condition     matchers.ConditionType
+	regexCompiled []*regexp.Regexp
+	dslCompiled   []*govaluate.EvaluableExpression
+}
+
+// InteractionExtractor captures data out of an interaction part and makes
+// it available to subsequent template requests, the same way a protocol's
+// extractors feed dynamicValues - here, via the named capture being written
+// into the InternalWrappedEvent's InternalEvent map (e.g. {{extracted_name}}).
+type InteractionExtractor struct {
+	// Part is the interaction field to extract from. See the Part* constants.
+	Part string `yaml:"part,omitempty"`
+	// Regex are the patterns to extract matches with. If a pattern has a
+	// capture group, its first group is extracted; otherwise the whole match is.
+	Regex []string `yaml:"regex,omitempty"`
+	// Name is the dynamic value name the extracted data is stored under.
+	Name string `yaml:"name"`
+
+	regexCompiled []*regexp.Regexp
+}
+
+// InteractionMatchers is the template-facing `interactsh_matchers` block: a
+// set of matchers (combined per MatchersCondition, mirroring
+// protocols.Request.GetMatchersCondition) plus extractors, evaluated
+// against every interaction reported for a request before it's allowed to
+// produce a result event.
+type InteractionMatchers struct {
+	// Matchers are the conditions an interaction must satisfy.
+	Matchers []*InteractionMatcher `yaml:"matchers,omitempty"`
+	// Extractors capture data out of a matched interaction.
+	Extractors []*InteractionExtractor `yaml:"extractors,omitempty"`
+	// MatchersCondition is and/or between Matchers. Defaults to or.
+	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+}
+
+// Compile precompiles every regex and DSL expression referenced by m, and
+// must be called once (e.g. during template parsing) before Evaluate.
+func (m *InteractionMatchers) Compile() error {
+	for _, matcher := range m.Matchers {
+		if err := matcher.compile(); err != nil {
+			return errors.Wrap(err, "could not compile interaction matcher")
+		}
+	}
+	for _, extractor := range m.Extractors {
+		for _, pattern := range extractor.Regex {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return errors.Wrap(err, "could not compile interaction extractor regex")
+			}
+			extractor.regexCompiled = append(extractor.regexCompiled, compiled)
+		}
+	}
+	return nil
+}
+
+func (matcher *InteractionMatcher) compile() error {
+	matcher.condition = matchers.ORCondition
+	if matcher.Condition == "and" {
+		matcher.condition = matchers.ANDCondition
+	}
+	for _, pattern := range matcher.Regex {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		matcher.regexCompiled = append(matcher.regexCompiled, compiled)
+	}
+	for _, expression := range matcher.DSL {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, nil)
+		if err != nil {
+			return err
+		}
+		matcher.dslCompiled = append(matcher.dslCompiled, compiled)
+	}
+	return nil
+}
+
+// Evaluate runs every matcher (combined per MatchersCondition) against
+// interaction and, if they pass, every extractor, returning whether the
+// interaction should produce a result and the values its extractors captured.
+func (m *InteractionMatchers) Evaluate(interaction *server.Interaction) (matched bool, extracted map[string]interface{}) {
+	if len(m.Matchers) == 0 {
+		return true, m.extract(interaction)
+	}
+
+	condition := matchers.ORCondition
+	if m.MatchersCondition == "and" {
+		condition = matchers.ANDCondition
+	}
+
+	for _, matcher := range m.Matchers {
+		ok := matcher.match(interaction)
+		switch condition {
+		case matchers.ANDCondition:
+			if !ok {
+				return false, nil
+			}
+		default: // ORCondition
+			if ok {
+				return true, m.extract(interaction)
+			}
+		}
+	}
+	// Every matcher passed (AND), or none did (OR with nothing left to try).
+	return condition == matchers.ANDCondition, m.extract(interaction)
+}
+
+func (m *InteractionMatchers) extract(interaction *server.Interaction) map[string]interface{} {
+	if len(m.Extractors) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{})
+	for _, extractor := range m.Extractors {
+		corpus := interactionPart(interaction, extractor.Part)
+		var results []string
+		for _, regex := range extractor.regexCompiled {
+			for _, match := range regex.FindAllStringSubmatch(corpus, -1) {
+				if len(match) > 1 {
+					results = append(results, match[1])
+				} else {
+					results = append(results, match[0])
+				}
+			}
+		}
+		if len(results) == 1 {
+			values[extractor.Name] = results[0]
+		} else if len(results) > 1 {
+			values[extractor.Name] = results
+		}
+	}
+	return values
+}
+
+func (matcher *InteractionMatcher) match(interaction *server.Interaction) bool {
+	corpus := interactionPart(interaction, matcher.Part)
+
+	var results []bool
+	if len(matcher.Words) > 0 {
+		results = append(results, matcher.matchWords(corpus))
+	}
+	if len(matcher.regexCompiled) > 0 {
+		results = append(results, matcher.matchRegex(corpus))
+	}
+	if len(matcher.dslCompiled) > 0 {
+		results = append(results, matcher.matchDSL(interaction))
+	}
+
+	ok := false
+	if len(results) > 0 {
+		ok = results[0]
+		for _, result := range results[1:] {
+			if matcher.condition == matchers.ANDCondition {
+				ok = ok && result
+			} else {
+				ok = ok || result
+			}
+		}
+	}
+	if matcher.Negative {
+		return !ok
+	}
+	return ok
+}
+
+func (matcher *InteractionMatcher) matchWords(corpus string) bool {
+	if len(matcher.Words) == 0 {
+		return false
+	}
+	matchedAll := true
+	for _, word := range matcher.Words {
+		found := strings.Contains(corpus, word)
+		if found && matcher.condition != matchers.ANDCondition {
+			return true
+		}
+		if !found {
+			matchedAll = false
+		}
+	}
+	return matcher.condition == matchers.ANDCondition && matchedAll
+}
+
+func (matcher *InteractionMatcher) matchRegex(corpus string) bool {
+	if len(matcher.regexCompiled) == 0 {
+		return false
+	}
+	matchedAll := true
+	for _, regex := range matcher.regexCompiled {
+		found := regex.MatchString(corpus)
+		if found && matcher.condition != matchers.ANDCondition {
+			return true
+		}
+		if !found {
+			matchedAll = false
+		}
+	}
+	return matcher.condition == matchers.ANDCondition && matchedAll
+}
+
+func (matcher *InteractionMatcher) matchDSL(interaction *server.Interaction) bool {
+	if len(matcher.dslCompiled) == 0 {
+		return false
+	}
+	parameters := interactionDSLParameters(interaction)
+	matchedAll := true
+	for _, expression := range matcher.dslCompiled {
+		result, err := expression.Evaluate(parameters)
+		found := err == nil && asBool(result)
+		if found && matcher.condition != matchers.ANDCondition {
+			return true
+		}
+		if !found {
+			matchedAll = false
+		}
+	}
+	return matcher.condition == matchers.ANDCondition && matchedAll
+}
+
+func asBool(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// interactionPart returns the corpus a matcher/extractor configured for
+// part should be evaluated against.
+func interactionPart(interaction *server.Interaction, part string) string {
+	switch part {
+	case PartDNSQType:
+		return interaction.QType
+	case PartHTTPRequest:
+		return interaction.RawRequest
+	case PartHTTPBody:
+		return httpBody(interaction.RawRequest)
+	case PartSMTPFrom:
+		return interaction.SMTPFrom
+	default: // PartRaw and anything unrecognized
+		return interaction.RawRequest + interaction.RawResponse
+	}
+}
+
+// httpBody strips rawRequest's header block, returning whatever follows the
+// first blank line, best-effort: interactsh's RawRequest is a captured
+// dump, not a structured http.Request, so this is a simple split rather
+// than a full re-parse.
+func httpBody(rawRequest string) string {
+	if idx := strings.Index(rawRequest, "\r\n\r\n"); idx != -1 {
+		return rawRequest[idx+4:]
+	}
+	if idx := strings.Index(rawRequest, "\n\n"); idx != -1 {
+		return rawRequest[idx+2:]
+	}
+	return ""
+}
+
+// interactionDSLParameters exposes an interaction's fields to DSL
+// expressions under the same names server.Interaction JSON-marshals to.
+func interactionDSLParameters(interaction *server.Interaction) map[string]interface{} {
+	return map[string]interface{}{
+		"protocol":       interaction.Protocol,
+		"unique_id":      interaction.UniqueID,
+		"full_id":        interaction.FullId,
+		"q_type":         interaction.QType,
+		"raw_request":    interaction.RawRequest,
+		"raw_response":   interaction.RawResponse,
+		"smtp_from":      interaction.SMTPFrom,
+		"remote_address": interaction.RemoteAddress,
+	}
+}
@@ -0,0 +1,70 @@
+package interactsh
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInteractionMatcherMatchCondition(t *testing.T) {
+	interaction := &server.Interaction{Protocol: "dns", RawRequest: "foo present"}
+
+	t.Run("and requires every category to match", func(t *testing.T) {
+		matcher := &InteractionMatcher{Condition: "and", Words: []string{"foo"}, Regex: []string{"not-present"}}
+		require.NoError(t, matcher.compile())
+
+		require.False(t, matcher.match(interaction), "AND condition matched even though the regex category failed")
+	})
+
+	t.Run("and matches once every category matches", func(t *testing.T) {
+		matcher := &InteractionMatcher{Condition: "and", Words: []string{"foo"}, Regex: []string{"present"}}
+		require.NoError(t, matcher.compile())
+
+		require.True(t, matcher.match(interaction))
+	})
+
+	t.Run("or matches if any category matches", func(t *testing.T) {
+		matcher := &InteractionMatcher{Condition: "or", Words: []string{"not-present"}, Regex: []string{"present"}}
+		require.NoError(t, matcher.compile())
+
+		require.True(t, matcher.match(interaction))
+	})
+
+	t.Run("or fails if no category matches", func(t *testing.T) {
+		matcher := &InteractionMatcher{Condition: "or", Words: []string{"not-present"}, Regex: []string{"also-not-present"}}
+		require.NoError(t, matcher.compile())
+
+		require.False(t, matcher.match(interaction))
+	})
+}
+
+func TestInteractionMatchersEvaluateCondition(t *testing.T) {
+	interaction := &server.Interaction{Protocol: "dns", RawRequest: "foo"}
+
+	matchingMatcher := &InteractionMatcher{Words: []string{"foo"}}
+	failingMatcher := &InteractionMatcher{Words: []string{"bar"}}
+	require.NoError(t, matchingMatcher.compile())
+	require.NoError(t, failingMatcher.compile())
+
+	t.Run("and requires every matcher to pass", func(t *testing.T) {
+		m := &InteractionMatchers{MatchersCondition: "and", Matchers: []*InteractionMatcher{matchingMatcher, failingMatcher}}
+
+		matched, _ := m.Evaluate(interaction)
+		require.False(t, matched)
+	})
+
+	t.Run("or passes if any matcher passes", func(t *testing.T) {
+		m := &InteractionMatchers{MatchersCondition: "or", Matchers: []*InteractionMatcher{matchingMatcher, failingMatcher}}
+
+		matched, _ := m.Evaluate(interaction)
+		require.True(t, matched)
+	})
+
+	t.Run("or fails if no matcher passes", func(t *testing.T) {
+		m := &InteractionMatchers{MatchersCondition: "or", Matchers: []*InteractionMatcher{failingMatcher}}
+
+		matched, _ := m.Evaluate(interaction)
+		require.False(t, matched)
+	})
+}
@@ -0,0 +1,89 @@
+package interactsh
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/interactsh/pkg/client"
+)
+
+const (
+	pollBackoffInitial = 1 * time.Second
+	pollBackoffMax     = 30 * time.Second
+	// pollHealthCheckInterval is how often the supervisor checks whether the
+	// underlying client's poller goroutine is still running.
+	pollHealthCheckInterval = 5 * time.Second
+)
+
+// startPollSupervisor starts StartPolling against c.interactsh and watches
+// it for as long as ctx is alive, restarting it with capped exponential
+// backoff and jitter whenever the underlying poller goroutine stops on its
+// own (e.g. the interactsh server became unreachable and client.Client gave
+// up, moving its State to client.Idle/client.Closed outside of our own
+// Close()). The vendored client doesn't report poll failures to its caller
+// - getInteractions only logs them - so liveness is inferred from
+// client.Client.State rather than from a failure return value.
+func (c *Client) startPollSupervisor(ctx context.Context, duration time.Duration, callback client.InteractionCallback) {
+	backoff := pollBackoffInitial
+	for {
+		c.busy.Lock()
+		err := c.interactsh.StartPolling(duration, callback)
+		c.busy.Unlock()
+		c.stats.polls.Inc()
+		if err != nil {
+			c.stats.pollErrors.Inc()
+			gologger.Warning().Msgf("interactsh: could not start polling, retrying in %s: %s\n", backoff, err)
+		} else {
+			backoff = pollBackoffInitial
+		}
+
+		if !c.waitForPollerDeath(ctx) {
+			return
+		}
+		c.stats.pollErrors.Inc()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jitter(backoff)):
+		}
+		backoff *= 2
+		if backoff > pollBackoffMax {
+			backoff = pollBackoffMax
+		}
+	}
+}
+
+// waitForPollerDeath blocks until either ctx is cancelled (returning false,
+// meaning the caller should stop supervising) or the client's poller is no
+// longer in client.Polling state (returning true, meaning it should be
+// restarted).
+func (c *Client) waitForPollerDeath(ctx context.Context) bool {
+	ticker := time.NewTicker(pollHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			c.busy.Lock()
+			state := c.interactsh.State.Load()
+			c.busy.Unlock()
+			if state != client.Polling {
+				return true
+			}
+		}
+	}
+}
+
+// jitter returns d plus or minus up to 20%, so a supervisor restarting many
+// clients at once doesn't hammer the server in lockstep.
+func jitter(d time.Duration) time.Duration {
+	delta := time.Duration(rand.Int63n(int64(d) / 5))
+	if rand.Intn(2) == 0 {
+		return d + delta
+	}
+	return d - delta
+}
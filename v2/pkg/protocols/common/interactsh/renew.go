@@ -0,0 +1,159 @@
+package interactsh
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/interactsh/pkg/client"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// RenewBehavior controls how the token renewer reacts to a RenewToken
+// failure, modeled on Vault API's LifetimeWatcher RenewBehavior.
+type RenewBehavior uint8
+
+const (
+	// RenewBehaviorFatal stops the renewer and surfaces the error through
+	// Options.Output once RenewToken has failed renewRetries consecutive
+	// times, so a self-hosted server that's gone away doesn't retry forever
+	// against a dead endpoint.
+	RenewBehaviorFatal RenewBehavior = iota
+	// RenewBehaviorIgnoreErrors keeps retrying RenewToken with exponential
+	// backoff indefinitely, for servers with flaky connectivity where a
+	// failed renewal shouldn't fail the whole scan.
+	RenewBehaviorIgnoreErrors
+)
+
+const (
+	// renewAtFraction is how far into TokenTTL the renewer wakes up to
+	// renew, mirroring Vault's grace-period-before-expiry behavior instead
+	// of renewing right at the deadline.
+	renewAtFraction = 0.8
+	// renewRetries is how many consecutive RenewToken failures
+	// RenewBehaviorFatal tolerates before giving up.
+	renewRetries = 5
+
+	renewBackoffInitial = 2 * time.Second
+	renewBackoffMax     = 2 * time.Minute
+)
+
+// startTokenRenewer launches the background goroutine that keeps
+// c.interactsh authenticated with a fresh token, returning the CancelFunc
+// that stops it. It returns a no-op CancelFunc if renewal isn't configured.
+//
+// Modeled on the Vault API's LifetimeWatcher: it runs in its own goroutine,
+// wakes up before the current token is due to expire, calls RenewToken, and
+// either keeps going (RenewBehaviorIgnoreErrors, with exponential backoff
+// between attempts) or gives up and reports a fatal error through
+// options.Output after renewRetries consecutive failures (the default,
+// RenewBehaviorFatal).
+func (c *Client) startTokenRenewer(options *Options) context.CancelFunc {
+	if !options.Renewable || options.TokenTTL <= 0 || options.AuthToken == "" || options.RenewToken == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go c.renewLoop(ctx, options)
+	return cancel
+}
+
+func (c *Client) renewLoop(ctx context.Context, options *Options) {
+	for {
+		wait := time.Duration(float64(options.TokenTTL) * renewAtFraction)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		token, err := renewWithBackoff(ctx, options)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if options.RenewBehavior == RenewBehaviorIgnoreErrors {
+				gologger.Warning().Msgf("interactsh: could not renew auth token, keeping the existing one: %s\n", err)
+				continue
+			}
+			// renewWithBackoff has already made renewRetries consecutive attempts.
+			c.reportFatalRenewError(options, errors.Wrap(err, "interactsh: giving up renewing auth token"))
+			return
+		}
+
+		if err := c.swapToken(options, token); err != nil {
+			c.reportFatalRenewError(options, errors.Wrap(err, "interactsh: could not apply renewed auth token"))
+			return
+		}
+	}
+}
+
+// renewWithBackoff calls options.RenewToken, retrying with exponential
+// backoff (capped at renewBackoffMax) until it succeeds, ctx is cancelled, or
+// renewRetries attempts have failed.
+func renewWithBackoff(ctx context.Context, options *Options) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt < renewRetries; attempt++ {
+		token, err := options.RenewToken()
+		if err == nil {
+			return token, nil
+		}
+		lastErr = err
+
+		delay := time.Duration(float64(renewBackoffInitial) * math.Pow(2, float64(attempt)))
+		if delay > renewBackoffMax {
+			delay = renewBackoffMax
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return "", lastErr
+}
+
+// swapToken rebuilds the underlying interactsh client with the renewed
+// token, since the vendored client.Client has no way to rotate its token in
+// place. It does not itself resume polling: leaving the new client Idle
+// makes startPollSupervisor's own liveness check notice within one
+// pollHealthCheckInterval and restart polling on it with the usual backoff,
+// rather than racing a second StartPolling call against the supervisor's.
+// Requests already using the previous client's URL() keep correlating fine,
+// as the correlation ID isn't derived from the auth token.
+func (c *Client) swapToken(options *Options, token string) error {
+	c.busy.Lock()
+	defer c.busy.Unlock()
+
+	newClient, err := client.New(&client.Options{
+		ServerURL: options.ServerURL,
+		Token:     token,
+	})
+	if err != nil {
+		return err
+	}
+
+	old := c.interactsh
+	c.interactsh = newClient
+	_ = old.StopPolling()
+	old.Close()
+	return nil
+}
+
+// reportFatalRenewError surfaces a renewal failure that the renewer isn't
+// going to recover from, via the same output.Writer the rest of the scan's
+// findings go to, so it's visible without a separate out-of-band log.
+func (c *Client) reportFatalRenewError(options *Options, err error) {
+	gologger.Error().Msgf("%s\n", err)
+	if options.Output == nil {
+		return
+	}
+	_ = options.Output.Write(&output.ResultEvent{
+		Type:      "interactsh",
+		Matched:   "interactsh-token-renewal",
+		Metadata:  map[string]interface{}{"error": err.Error()},
+		Timestamp: time.Now(),
+	})
+}
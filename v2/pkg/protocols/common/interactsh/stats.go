@@ -0,0 +1,112 @@
+package interactsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/projectdiscovery/gologger"
+	"go.uber.org/atomic"
+)
+
+// Stats is a snapshot of a Client's polling counters, returned by
+// Client.Stats() and served by the optional diagnostics listener
+// (Options.DiagnosticAddr).
+type Stats struct {
+	// Polls is how many times the poll supervisor has started (or
+	// restarted) the client's poller, successfully or not. Individual
+	// poll requests are not counted.
+	Polls int64 `json:"polls"`
+	// PollErrors is how many of those starts returned an error, plus how
+	// many times the supervisor found the poller no longer running and
+	// restarted it.
+	PollErrors int64 `json:"poll_errors"`
+	// InteractionsReceived is how many interactions the server has reported
+	// that matched a pending request in the correlation cache.
+	InteractionsReceived int64 `json:"interactions_received"`
+	// CacheMisses is how many reported interactions had no corresponding
+	// entry in the correlation cache (already evicted, already consumed, or
+	// a callback for a request this process never issued).
+	CacheMisses int64 `json:"cache_misses"`
+	// Evictions is how many correlation cache entries were discarded before
+	// ever being matched, e.g. because CacheSize was exceeded or Eviction
+	// elapsed first.
+	Evictions int64 `json:"evictions"`
+	// CacheSize is the number of requests currently pending correlation.
+	CacheSize int64 `json:"cache_size"`
+}
+
+// stats holds the live, atomically-updated counters backing Stats.
+type stats struct {
+	polls                atomic.Int64
+	pollErrors           atomic.Int64
+	interactionsReceived atomic.Int64
+	cacheMisses          atomic.Int64
+	evictions            atomic.Int64
+}
+
+func (s *stats) snapshot(cacheSize int64) Stats {
+	return Stats{
+		Polls:                s.polls.Load(),
+		PollErrors:           s.pollErrors.Load(),
+		InteractionsReceived: s.interactionsReceived.Load(),
+		CacheMisses:          s.cacheMisses.Load(),
+		Evictions:            s.evictions.Load(),
+		CacheSize:            cacheSize,
+	}
+}
+
+// Stats returns a snapshot of the client's current polling counters.
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot(int64(c.requests.memory.ItemCount()))
+}
+
+// diagnosticsServer is the optional HTTP listener exposing Client.Stats()
+// for operators running nuclei as a long-lived service, so OOB delivery
+// loss (rising cache_misses/evictions/poll_errors) can be alerted on
+// without instrumenting nuclei itself.
+type diagnosticsServer struct {
+	httpServer *http.Server
+}
+
+func startDiagnosticsServer(addr string, client *Client) (*diagnosticsServer, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(client.Stats())
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePrometheusStats(w, client.Stats())
+	})
+
+	d := &diagnosticsServer{httpServer: &http.Server{Addr: addr, Handler: mux}}
+	go func() {
+		if err := d.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			gologger.Warning().Msgf("interactsh: diagnostics listener stopped: %s\n", err)
+		}
+	}()
+	return d, nil
+}
+
+func (d *diagnosticsServer) Close() error {
+	return d.httpServer.Close()
+}
+
+func writePrometheusStats(w http.ResponseWriter, s Stats) {
+	metrics := []struct {
+		name  string
+		help  string
+		value int64
+	}{
+		{"nuclei_interactsh_polls_total", "Total number of times the interactsh poller was started or restarted.", s.Polls},
+		{"nuclei_interactsh_poll_errors_total", "Total number of failed poller starts and poller stops that needed a restart.", s.PollErrors},
+		{"nuclei_interactsh_interactions_received_total", "Total number of interactions matched to a pending request.", s.InteractionsReceived},
+		{"nuclei_interactsh_cache_misses_total", "Total number of interactions with no matching pending request.", s.CacheMisses},
+		{"nuclei_interactsh_evictions_total", "Total number of pending requests discarded before being matched.", s.Evictions},
+		{"nuclei_interactsh_cache_size", "Number of requests currently pending correlation.", s.CacheSize},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", m.name, m.help, m.name, m.name, m.value)
+	}
+}
@@ -0,0 +1,33 @@
+// Package netns provides optional Linux network-namespace isolation for
+// template execution, letting each scan (or each host) run inside its
+// own network namespace instead of sharing the host's network stack.
+package netns
+
+// Isolator creates and tears down an isolated network namespace for the
+// lifetime of a scan. On non-Linux platforms New returns an error instead
+// of an Isolator, and callers run without isolation.
+type Isolator interface {
+	// Enter switches the calling OS thread into the isolated namespace.
+	Enter() error
+	// Exit restores the calling OS thread's original namespace.
+	Exit() error
+	// Run executes fn on a dedicated, locked OS thread inside the isolated
+	// namespace and restores that thread's original namespace afterwards.
+	// If restoring the original namespace fails, the thread is left locked
+	// so it terminates along with the goroutine instead of being recycled
+	// by the Go runtime while still sitting in the wrong namespace.
+	Run(fn func() error) error
+	// Close releases the namespace and any interfaces created for it.
+	Close() error
+}
+
+// Config controls how an isolated namespace is set up.
+type Config struct {
+	// Name is used to name the namespace so it can be inspected with
+	// `ip netns list` while a scan is running.
+	Name string
+	// VethCIDR is an address range for a veth pair, e.g. "169.254.40.0/24".
+	// The Linux isolator does not read it and creates no veth pair: the
+	// namespace gets only its loopback interface.
+	VethCIDR string
+}
@@ -0,0 +1,160 @@
+//go:build linux
+// +build linux
+
+package netns
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// linuxIsolator implements Isolator using CLONE_NEWNET via unshare(2),
+// bind-mounting the resulting namespace under /var/run/netns so it is
+// visible to the `ip netns` tooling for debugging.
+type linuxIsolator struct {
+	config Config
+	nsPath string
+	origNS *os.File
+	isolNS *os.File
+}
+
+// New creates a new Linux network-namespace isolator. The namespace is
+// not entered until Enter is called.
+func New(config Config) (Isolator, error) {
+	if config.Name == "" {
+		config.Name = fmt.Sprintf("nuclei-%d", os.Getpid())
+	}
+	if err := os.MkdirAll("/var/run/netns", 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create /var/run/netns")
+	}
+	return &linuxIsolator{config: config, nsPath: "/var/run/netns/" + config.Name}, nil
+}
+
+// Enter locks the calling goroutine to its OS thread, records the
+// current namespace and switches into a freshly created one.
+func (l *linuxIsolator) Enter() error {
+	runtime.LockOSThread()
+
+	origFd, err := unix.Open("/proc/self/ns/net", unix.O_RDONLY, 0)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrap(err, "could not open current namespace")
+	}
+	l.origNS = os.NewFile(uintptr(origFd), "current-netns")
+
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		// unshare failed as a whole, so the thread never left its
+		// original namespace and is safe to return to the pool.
+		_ = l.origNS.Close()
+		l.origNS = nil
+		runtime.UnlockOSThread()
+		return errors.Wrap(err, "could not unshare network namespace")
+	}
+
+	// From here on the thread has already switched into the new namespace,
+	// so any further failure leaves the thread locked rather than risking
+	// it being recycled while still isolated.
+	nsFile, err := os.Create(l.nsPath)
+	if err != nil {
+		return errors.Wrap(err, "could not create namespace bind target")
+	}
+	nsFile.Close()
+
+	if err := unix.Mount("/proc/self/ns/net", l.nsPath, "none", unix.MS_BIND, ""); err != nil {
+		return errors.Wrap(err, "could not bind mount namespace")
+	}
+
+	isolFd, err := unix.Open(l.nsPath, unix.O_RDONLY, 0)
+	if err != nil {
+		return errors.Wrap(err, "could not open new namespace")
+	}
+	l.isolNS = os.NewFile(uintptr(isolFd), l.nsPath)
+
+	// bring loopback up so localhost-bound tooling in the namespace works
+	return bringUpLoopback()
+}
+
+// Run executes fn inside the isolated namespace on a fresh OS thread,
+// independent of whatever namespace the calling goroutine is currently in.
+// This lets callers isolate a single request (HTTP, DNS, headless, ...)
+// without having to Enter/Exit around every call site.
+func (l *linuxIsolator) Run(fn func() error) error {
+	result := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		origFd, err := unix.Open("/proc/self/ns/net", unix.O_RDONLY, 0)
+		if err != nil {
+			runtime.UnlockOSThread()
+			result <- errors.Wrap(err, "could not open current namespace")
+			return
+		}
+		defer unix.Close(origFd)
+
+		isolFd, err := unix.Open(l.nsPath, unix.O_RDONLY, 0)
+		if err != nil {
+			runtime.UnlockOSThread()
+			result <- errors.Wrap(err, "could not open isolated namespace")
+			return
+		}
+		defer unix.Close(isolFd)
+
+		if err := unix.Setns(isolFd, unix.CLONE_NEWNET); err != nil {
+			runtime.UnlockOSThread()
+			result <- errors.Wrap(err, "could not enter isolated namespace")
+			return
+		}
+
+		fnErr := fn()
+
+		if err := unix.Setns(origFd, unix.CLONE_NEWNET); err != nil {
+			// Leave the thread locked: it terminates with this goroutine
+			// instead of being recycled into the scheduler's pool while
+			// still sitting in the isolated namespace.
+			result <- errors.Wrap(err, "could not restore original namespace after run")
+			return
+		}
+		runtime.UnlockOSThread()
+		result <- fnErr
+	}()
+	return <-result
+}
+
+// Exit switches the calling thread back to the namespace it started in.
+func (l *linuxIsolator) Exit() error {
+	defer runtime.UnlockOSThread()
+	if l.origNS == nil {
+		return nil
+	}
+	if err := unix.Setns(int(l.origNS.Fd()), unix.CLONE_NEWNET); err != nil {
+		return errors.Wrap(err, "could not restore original namespace")
+	}
+	return l.origNS.Close()
+}
+
+// Close unmounts and removes the namespace created for this isolator.
+func (l *linuxIsolator) Close() error {
+	if l.isolNS != nil {
+		_ = l.isolNS.Close()
+	}
+	_ = unix.Unmount(l.nsPath, unix.MNT_DETACH)
+	return os.Remove(l.nsPath)
+}
+
+// bringUpLoopback brings the namespace's loopback interface up so that
+// localhost-bound tooling inside the namespace keeps working. Shelling
+// out to `ip` keeps this in line with how the rest of the namespace is
+// provisioned (bind mount via /var/run/netns) without pulling in a full
+// netlink client for a single interface flag flip.
+func bringUpLoopback() error {
+	cmd := exec.Command("ip", "link", "set", "lo", "up")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "could not bring up loopback: %s", string(out))
+	}
+	return nil
+}
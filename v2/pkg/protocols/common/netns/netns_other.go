@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package netns
+
+import "errors"
+
+// New returns an error on non-Linux platforms, since network-namespace
+// isolation is a Linux-only kernel feature. Callers should treat this as
+// an optional capability and fall back to running without isolation.
+func New(config Config) (Isolator, error) {
+	return nil, errors.New("network namespace isolation is only supported on linux")
+}
@@ -0,0 +1,186 @@
+package dns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+)
+
+// Request contains a DNS protocol request to be made from a template
+type Request struct {
+	// ID is the optional id of the request
+	ID string `yaml:"id,omitempty"`
+	// Name is the name of the request
+	Name string `yaml:"name,omitempty"`
+	// Type is the type of DNS request to make
+	Type DNSRequestTypeHolder `yaml:"type"`
+	// Class is the class of the DNS request. Make does not read it: every
+	// query is sent with class IN.
+	Class string `yaml:"class,omitempty"`
+	// Retries is the number of retries for the DNS request
+	Retries int `yaml:"retries,omitempty"`
+	// Resolvers is the list of resolvers to use for the request. Its format
+	// depends on Transport: host:port for udp/tcp/dot, or a full DoH
+	// endpoint URL (e.g. https://cloudflare-dns.com/dns-query) for doh.
+	// Defaults to 8.8.8.8:53 when empty.
+	Resolvers []string `yaml:"resolvers,omitempty"`
+	// Recursion specifies whether to recurse all the answers
+	Recursion *bool `yaml:"recursion,omitempty"`
+	// Transport is the protocol used to reach the resolver. Supported
+	// values are udp (default), tcp, dot (DNS over TLS) and doh (DNS over
+	// HTTPS).
+	Transport string `yaml:"transport,omitempty"`
+
+	options   *protocols.ExecuterOptions
+	dnsClient dnsClient
+}
+
+// dnsClient is implemented by every supported DNS transport (plain udp/tcp,
+// DNS over TLS, and DNS over HTTPS), so Request can stay agnostic of how the
+// request is actually carried over the wire. ctx lets a caller abandon a
+// resolver round-trip (and its retries) as soon as the scan is cancelled or
+// the target's deadline expires, instead of blocking until the transport's
+// own fixed timeout fires.
+type dnsClient interface {
+	Do(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+}
+
+// Compile compiles the request generators preparing any requests possible.
+func (request *Request) Compile(options *protocols.ExecuterOptions) error {
+	request.options = options
+
+	resolvers := request.Resolvers
+	if len(resolvers) == 0 {
+		resolvers = []string{"8.8.8.8:53"}
+	}
+
+	client, err := newDNSClient(request.Transport, resolvers, request.Retries)
+	if err != nil {
+		return errors.Wrap(err, "could not create dns client")
+	}
+	request.dnsClient = client
+	return nil
+}
+
+// Requests returns the total number of requests the rule will perform
+func (request *Request) Requests() int {
+	return 1
+}
+
+// Make creates a DNS request for the provided domain based on the template.
+func (request *Request) Make(domain string) (*dns.Msg, error) {
+	domain = dns.Fqdn(domain)
+
+	qtype, ok := dnsRequestTypeToQType[request.Type.DNSRequestType]
+	if !ok {
+		return nil, errors.Errorf("unsupported dns request type: %s", request.Type.String())
+	}
+
+	msg := &dns.Msg{}
+	msg.Id = dns.Id()
+	msg.RecursionDesired = request.Recursion == nil || *request.Recursion
+	msg.Question = append(msg.Question, dns.Question{Name: domain, Qtype: qtype, Qclass: dns.ClassINET})
+	return msg, nil
+}
+
+// dnsRequestTypeToQType maps a template DNS request type to its miekg/dns
+// question type constant.
+var dnsRequestTypeToQType = map[DNSRequestType]uint16{
+	A:     dns.TypeA,
+	NS:    dns.TypeNS,
+	DS:    dns.TypeDS,
+	CNAME: dns.TypeCNAME,
+	SOA:   dns.TypeSOA,
+	PTR:   dns.TypePTR,
+	MX:    dns.TypeMX,
+	TXT:   dns.TypeTXT,
+	AAAA:  dns.TypeAAAA,
+	CAA:   dns.TypeCAA,
+	SRV:   dns.TypeSRV,
+	HTTPS: dns.TypeHTTPS,
+	SVCB:  dns.TypeSVCB,
+	NAPTR: dns.TypeNAPTR,
+	ANY:   dns.TypeANY,
+}
+
+// responseToDSLMap converts a DNS response to a map for use in DSL matching
+// and output. In addition to the raw request/response strings, it flattens
+// record-specific fields parsed from the answer section (e.g. caa_issuer,
+// srv_target, svcb_alpn) so templates can match on them directly.
+func (request *Request) responseToDSLMap(msg, resp *dns.Msg, host, matched string) output.InternalEvent {
+	data := output.InternalEvent{
+		"host":          host,
+		"matched":       matched,
+		"request":       msg.String(),
+		"response":      resp.String(),
+		"template-id":   request.options.TemplateID,
+		"template-path": request.options.TemplatePath,
+	}
+	for key, value := range extractAnswerFields(resp) {
+		data[key] = value
+	}
+	return data
+}
+
+// extractAnswerFields parses the answer section of a DNS response and
+// returns record-type-specific fields for use in the DSL matching map: the
+// issuer tag/value for CAA, priority/weight/port/target for SRV,
+// priority/target/ALPN for HTTPS and SVCB (RFC 9460), and the order/fields
+// for NAPTR.
+func extractAnswerFields(resp *dns.Msg) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if resp == nil {
+		return fields
+	}
+
+	var alpn []string
+	for _, rr := range resp.Answer {
+		switch record := rr.(type) {
+		case *dns.CAA:
+			fields["caa_tag"] = record.Tag
+			fields["caa_issuer"] = record.Value
+			fields["caa_value"] = record.Value
+		case *dns.SRV:
+			fields["srv_priority"] = int(record.Priority)
+			fields["srv_weight"] = int(record.Weight)
+			fields["srv_port"] = int(record.Port)
+			fields["srv_target"] = record.Target
+		case *dns.NAPTR:
+			fields["naptr_order"] = int(record.Order)
+			fields["naptr_preference"] = int(record.Preference)
+			fields["naptr_flags"] = record.Flags
+			fields["naptr_service"] = record.Service
+			fields["naptr_regexp"] = record.Regexp
+			fields["naptr_replacement"] = record.Replacement
+		case *dns.HTTPS:
+			fields["svcb_priority"] = int(record.Priority)
+			fields["svcb_target"] = record.Target
+			alpn = append(alpn, svcbALPNValues(record.Value)...)
+		case *dns.SVCB:
+			fields["svcb_priority"] = int(record.Priority)
+			fields["svcb_target"] = record.Target
+			alpn = append(alpn, svcbALPNValues(record.Value)...)
+		}
+	}
+	if len(alpn) > 0 {
+		fields["svcb_alpn"] = alpn
+	}
+	return fields
+}
+
+// svcbALPNValues extracts the ALPN protocol IDs from a SVCB/HTTPS record's
+// SvcParams, e.g. ["h2", "h3"].
+func svcbALPNValues(params []dns.SVCBKeyValue) []string {
+	var alpn []string
+	for _, kv := range params {
+		if kv.Key() == dns.SVCB_ALPN {
+			alpn = append(alpn, strings.Split(kv.String(), ",")...)
+		}
+	}
+	return alpn
+}
@@ -21,6 +21,12 @@ const (
 	MX
 	TXT
 	AAAA
+	CAA
+	SRV
+	HTTPS
+	SVCB
+	NAPTR
+	ANY
 	limit
 )
 
@@ -35,6 +41,12 @@ var DNSRequestTypeMapping = map[DNSRequestType]string{
 	MX:    "MX",
 	TXT:   "TXT",
 	AAAA:  "AAAA",
+	CAA:   "CAA",
+	SRV:   "SRV",
+	HTTPS: "HTTPS",
+	SVCB:  "SVCB",
+	NAPTR: "NAPTR",
+	ANY:   "ANY",
 }
 
 // GetSupportedDNSRequestTypes returns list of supported types
@@ -77,7 +89,7 @@ func (holder DNSRequestTypeHolder) JSONSchemaType() *jsonschema.Type {
 	gotType := &jsonschema.Type{
 		Type:        "string",
 		Title:       "type of DNS request to make",
-		Description: "Type is the type of DNS request to make,enum=A,enum=NS,enum=DS,enum=CNAME,enum=SOA,enum=PTR,enum=MX,enum=TXT,enum=AAAA",
+		Description: "Type is the type of DNS request to make,enum=A,enum=NS,enum=DS,enum=CNAME,enum=SOA,enum=PTR,enum=MX,enum=TXT,enum=AAAA,enum=CAA,enum=SRV,enum=HTTPS,enum=SVCB,enum=NAPTR,enum=ANY",
 	}
 	for _, types := range GetSupportedDNSRequestTypes() {
 		gotType.Enum = append(gotType.Enum, types.String())
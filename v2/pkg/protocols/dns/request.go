@@ -1,6 +1,7 @@
 package dns
 
 import (
+	"context"
 	"encoding/hex"
 	"net/url"
 
@@ -23,7 +24,9 @@ func (request *Request) Type() templateTypes.ProtocolType {
 }
 
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
-func (request *Request) ExecuteWithResults(input string, metadata /*TODO review unused parameter*/, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+// ctx carries the per-target deadline (and the runner's shutdown signal);
+// it is honored for the resolver round-trip via request.dnsClient.Do.
+func (request *Request) ExecuteWithResults(ctx context.Context, input string, metadata /*TODO review unused parameter*/, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	// Parse the URL and return domain if URL.
 	var domain string
 	if isURL(input) {
@@ -35,7 +38,7 @@ func (request *Request) ExecuteWithResults(input string, metadata /*TODO review
 	// Compile each request for the template based on the URL
 	compiledRequest, err := request.Make(domain)
 	if err != nil {
-		request.options.Output.Request(request.options.TemplatePath, domain, "dns", err)
+		request.options.Output.Request(request.options.TemplatePath, domain, "dns", err, nil)
 		request.options.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, "could not build request")
 	}
@@ -51,9 +54,9 @@ func (request *Request) ExecuteWithResults(input string, metadata /*TODO review
 	}
 
 	// Send the request to the target servers
-	response, err := request.dnsClient.Do(compiledRequest)
+	response, err := request.dnsClient.Do(ctx, compiledRequest)
 	if err != nil {
-		request.options.Output.Request(request.options.TemplatePath, domain, "dns", err)
+		request.options.Output.Request(request.options.TemplatePath, domain, "dns", err, nil)
 		request.options.Progress.IncrementFailedRequestsBy(1)
 	}
 	if response == nil {
@@ -61,7 +64,7 @@ func (request *Request) ExecuteWithResults(input string, metadata /*TODO review
 	}
 	request.options.Progress.IncrementRequests()
 
-	request.options.Output.Request(request.options.TemplatePath, domain, "dns", err)
+	request.options.Output.Request(request.options.TemplatePath, domain, "dns", err, nil)
 	gologger.Verbose().Msgf("[%s] Sent DNS request to %s\n", request.options.TemplateID, domain)
 
 	outputEvent := request.responseToDSLMap(compiledRequest, response, input, input)
@@ -0,0 +1,125 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// defaultDNSTimeout bounds a single resolver round-trip across all
+// transports.
+const defaultDNSTimeout = 5 * time.Second
+
+// newDNSClient constructs the dnsClient implementation for the requested
+// transport. Supported values are udp (default), tcp, dot (DNS over TLS)
+// and doh (DNS over HTTPS). An unknown transport is rejected at compile
+// time rather than silently falling back to udp.
+func newDNSClient(transport string, resolvers []string, retries int) (dnsClient, error) {
+	if retries <= 0 {
+		retries = 1
+	}
+
+	switch strings.ToLower(transport) {
+	case "", "udp":
+		return &classicDNSClient{resolvers: resolvers, retries: retries, client: &dns.Client{Net: "udp", Timeout: defaultDNSTimeout}}, nil
+	case "tcp":
+		return &classicDNSClient{resolvers: resolvers, retries: retries, client: &dns.Client{Net: "tcp", Timeout: defaultDNSTimeout}}, nil
+	case "dot":
+		return &classicDNSClient{resolvers: resolvers, retries: retries, client: &dns.Client{Net: "tcp-tls", Timeout: defaultDNSTimeout}}, nil
+	case "doh":
+		return &dohDNSClient{resolvers: resolvers, retries: retries, httpClient: &http.Client{Timeout: defaultDNSTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported dns transport: %s", transport)
+	}
+}
+
+// classicDNSClient implements the dnsClient interface for plain udp/tcp and
+// DNS over TLS (dot) lookups, all of which miekg/dns' client already
+// understands via its Net field.
+type classicDNSClient struct {
+	resolvers []string
+	retries   int
+	client    *dns.Client
+}
+
+func (c *classicDNSClient) Do(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	var lastErr error
+	for attempt := 0; attempt < c.retries; attempt++ {
+		for _, resolver := range c.resolvers {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			resp, _, err := c.client.ExchangeContext(ctx, msg, resolver)
+			if err == nil {
+				return resp, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+// dohDNSClient implements DNS over HTTPS (RFC 8484) using the wire-format
+// "application/dns-message" media type over HTTP POST.
+type dohDNSClient struct {
+	resolvers  []string
+	retries    int
+	httpClient *http.Client
+}
+
+func (c *dohDNSClient) Do(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < c.retries; attempt++ {
+		for _, resolver := range c.resolvers {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			resp, err := c.doRequest(ctx, resolver, packed)
+			if err == nil {
+				return resp, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *dohDNSClient) doRequest(ctx context.Context, endpoint string, packed []byte) (*dns.Msg, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("doh resolver returned status %d", httpResp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dns.Msg{}
+	if err := resp.Unpack(body); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,229 @@
+// Package grpc implements a protocol.Request that invokes a single unary
+// gRPC method dynamically from a .proto file, without requiring the target
+// server to support reflection.
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/dynamic/grpcdynamic"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+)
+
+func init() {
+	protocols.Register("grpc", func() protocols.Request { return &Request{} })
+}
+
+// Request is a request for the grpc protocol. It invokes a single unary
+// method on a .proto-described service via reflection-free dynamic
+// invocation (github.com/jhump/protoreflect), since most targets don't
+// expose the standard gRPC server-reflection service.
+type Request struct {
+	// ProtoFile is the path to the .proto file describing the service.
+	ProtoFile string `yaml:"proto-file"`
+	// ImportPaths are additional directories searched for imports used by
+	// ProtoFile.
+	ImportPaths []string `yaml:"import-paths,omitempty"`
+	// Service is the fully-qualified name of the service to invoke.
+	Service string `yaml:"service"`
+	// Method is the name of the unary method to invoke on Service.
+	Method string `yaml:"method"`
+	// Body is the JSON-encoded request message.
+	Body string `yaml:"body"`
+	// TLS enables a TLS connection to the target. Certificate verification
+	// is skipped, matching nuclei's other protocols' handling of
+	// self-signed scan targets.
+	TLS bool `yaml:"tls,omitempty"`
+
+	// Matchers contains the detection mechanism for the request to identify
+	// whether the request was successful
+	Matchers []*matchers.Matcher `yaml:"matchers,omitempty"`
+	// MatchersCondition is the condition between the matchers. Default is OR.
+	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+	matchersCondition matchers.ConditionType
+	// Extractors contains the extraction mechanism for the request to
+	// identify and extract data from the response.
+	Extractors []*extractors.Extractor `yaml:"extractors,omitempty"`
+
+	methodDescriptor *desc.MethodDescriptor
+	options          *protocols.ExecuterOptions
+}
+
+var _ protocols.Request = &Request{}
+
+// Compile compiles the request generators preparing any requests possible.
+func (r *Request) Compile(options *protocols.ExecuterOptions) error {
+	if r.ProtoFile == "" || r.Service == "" || r.Method == "" {
+		return errors.New("proto-file, service and method are required for grpc requests")
+	}
+
+	parser := protoparse.Parser{ImportPaths: append(r.ImportPaths, filepath.Dir(r.ProtoFile))}
+	descriptors, err := parser.ParseFiles(filepath.Base(r.ProtoFile))
+	if err != nil {
+		return errors.Wrap(err, "could not parse proto file")
+	}
+
+	var serviceDescriptor *desc.ServiceDescriptor
+	for _, fileDescriptor := range descriptors {
+		if serviceDescriptor = fileDescriptor.FindService(r.Service); serviceDescriptor != nil {
+			break
+		}
+	}
+	if serviceDescriptor == nil {
+		return errors.Errorf("service %q not found in %s", r.Service, r.ProtoFile)
+	}
+
+	methodDescriptor := serviceDescriptor.FindMethodByName(r.Method)
+	if methodDescriptor == nil {
+		return errors.Errorf("method %q not found on service %q", r.Method, r.Service)
+	}
+	r.methodDescriptor = methodDescriptor
+
+	r.matchersCondition = matchers.ORCondition
+	if r.MatchersCondition == "and" {
+		r.matchersCondition = matchers.ANDCondition
+	}
+
+	r.options = options
+	return nil
+}
+
+// Requests returns the total number of requests the rule will perform.
+func (r *Request) Requests() int {
+	return 1
+}
+
+// Match performs matching operation for a matcher on model and returns true or false.
+func (r *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) bool {
+	part, ok := data["response"]
+	if !ok {
+		return false
+	}
+	response, ok := part.(string)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case len(matcher.Words) > 0:
+		matched, _ := matcher.MatchWords(response, nil)
+		return matched
+	case len(matcher.Regex) > 0:
+		matched, _ := matcher.MatchRegex(response)
+		return matched
+	case len(matcher.Binary) > 0:
+		matched, _ := matcher.MatchBinary(response)
+		return matched
+	case len(matcher.DSL) > 0:
+		return matcher.MatchDSL(data)
+	default:
+		return false
+	}
+}
+
+// Extract performs extracting operation for an extractor on model and returns true or false.
+func (r *Request) Extract(data map[string]interface{}, extractor *extractors.Extractor) map[string]struct{} {
+	part, ok := data["response"]
+	if !ok {
+		return nil
+	}
+	response, ok := part.(string)
+	if !ok {
+		return nil
+	}
+	return extractor.Extract(response)
+}
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (r *Request) ExecuteWithResults(input string, metadata output.InternalEvent) ([]*output.InternalWrappedEvent, error) {
+	dialOpts := []grpc.DialOption{grpc.WithBlock()}
+	if r.TLS {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDialTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, input, dialOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not dial grpc target")
+	}
+	defer conn.Close()
+
+	requestMessage := dynamic.NewMessage(r.methodDescriptor.GetInputType())
+	if err := requestMessage.UnmarshalJSON([]byte(r.Body)); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal grpc request body")
+	}
+
+	stub := grpcdynamic.NewStub(conn)
+	responseMessage, err := stub.InvokeRpc(ctx, r.methodDescriptor, requestMessage)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not invoke grpc method")
+	}
+
+	responseJSON, err := marshalResponse(responseMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(output.InternalEvent)
+	for k, v := range metadata {
+		data[k] = v
+	}
+	data["host"] = input
+	data["response"] = responseJSON
+	data["type"] = "grpc"
+
+	event := &output.InternalWrappedEvent{InternalEvent: data}
+	if len(r.Matchers) == 0 {
+		return []*output.InternalWrappedEvent{event}, nil
+	}
+
+	matchedAll := true
+	for _, matcher := range r.Matchers {
+		if r.Match(data, matcher) {
+			if r.matchersCondition == matchers.ORCondition {
+				return []*output.InternalWrappedEvent{event}, nil
+			}
+		} else {
+			matchedAll = false
+		}
+	}
+	if matchedAll {
+		return []*output.InternalWrappedEvent{event}, nil
+	}
+	return nil, nil
+}
+
+const defaultDialTimeout = 10 * time.Second
+
+// marshalResponse converts a dynamic response message into its JSON form,
+// going through dynamic.AsDynamicMessage so responses returned by grpc's
+// own generated client machinery would work here too.
+func marshalResponse(msg proto.Message) (string, error) {
+	dynamicMessage, err := dynamic.AsDynamicMessage(msg)
+	if err != nil {
+		return "", errors.Wrap(err, "could not convert grpc response to dynamic message")
+	}
+	responseJSON, err := dynamicMessage.MarshalJSON()
+	if err != nil {
+		return "", errors.Wrap(err, "could not marshal grpc response")
+	}
+	return string(responseJSON), nil
+}
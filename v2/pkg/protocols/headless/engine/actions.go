@@ -0,0 +1,289 @@
+package engine
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// ActionType is the type of an action taken by the headless engine
+// while running a chain of actions for a template.
+type ActionType string
+
+// Action types that can be executed by the headless engine.
+const (
+	ActionNavigate     ActionType = "navigate"
+	ActionScript       ActionType = "script"
+	ActionClick        ActionType = "click"
+	ActionRightClick   ActionType = "rightclick"
+	ActionTextInput    ActionType = "text"
+	ActionScreenshot   ActionType = "screenshot"
+	ActionTimeInput    ActionType = "time"
+	ActionSelectInput  ActionType = "select"
+	ActionFilesInput   ActionType = "files"
+	ActionWaitLoad     ActionType = "waitload"
+	ActionGetResource  ActionType = "getresource"
+	ActionExtract      ActionType = "extract"
+	ActionSetMethod    ActionType = "setmethod"
+	ActionAddHeader    ActionType = "addheader"
+	ActionDeleteHeader ActionType = "deleteheader"
+	ActionSetHeader    ActionType = "setheader"
+	ActionSetBody      ActionType = "setbody"
+	ActionKeyboard     ActionType = "keyboard"
+	ActionDebug        ActionType = "debug"
+	ActionSleep        ActionType = "sleep"
+	ActionWaitVisible  ActionType = "waitvisible"
+	ActionWaitEvent    ActionType = "waitevent"
+	// ActionEmulate switches the page into a named device profile
+	// (or a custom viewport) before subsequent actions run.
+	ActionEmulate ActionType = "emulate"
+	// ActionPDF renders the current page to a PDF file.
+	ActionPDF ActionType = "pdf"
+	// ActionHARCapture captures all network traffic generated while
+	// running the action chain into a HAR 1.2 document.
+	ActionHARCapture ActionType = "harcapture"
+	// ActionIntercept registers a URL-pattern based request/response
+	// interception rule (block/redirect/respond/modify). ActionMock is
+	// an alias kept for readability in templates that stub out a response.
+	ActionIntercept ActionType = "intercept"
+	ActionMock      ActionType = "mock"
+	// ActionSetCookie, ActionGetCookie and ActionDeleteCookie manage
+	// cookies on the page via the Network domain.
+	ActionSetCookie    ActionType = "setcookie"
+	ActionGetCookie    ActionType = "getcookie"
+	ActionDeleteCookie ActionType = "deletecookie"
+	// ActionSetStorage, ActionGetStorage and ActionDeleteStorage manage
+	// localStorage/sessionStorage key/value pairs on the page.
+	ActionSetStorage    ActionType = "setstorage"
+	ActionGetStorage    ActionType = "getstorage"
+	ActionDeleteStorage ActionType = "deletestorage"
+	// ActionWaitNetworkIdle blocks until no in-flight network requests
+	// have been observed for a configurable idle window.
+	ActionWaitNetworkIdle ActionType = "waitfornetworkidle"
+)
+
+// Action is a single action that can be executed as part of a headless
+// template's action chain.
+type Action struct {
+	ActionType ActionType
+	Name       string
+	Data       map[string]string
+}
+
+// RunAction runs a single action against the page, writing any named
+// output into the out map.
+func (p *Page) RunAction(out map[string]string, input *url.URL, act *Action) error {
+	switch act.ActionType {
+	case ActionNavigate:
+		return p.actionNavigate(input, act)
+	case ActionWaitLoad:
+		return p.page.WaitLoad()
+	case ActionScript:
+		return p.actionScript(out, act)
+	case ActionClick:
+		return p.actionClick(act, false)
+	case ActionRightClick:
+		return p.actionClick(act, true)
+	case ActionTextInput:
+		return p.actionTextInput(act)
+	case ActionScreenshot:
+		return p.actionScreenshot(act)
+	case ActionTimeInput:
+		return p.actionTimeInput(act)
+	case ActionSelectInput:
+		return p.actionSelectInput(act)
+	case ActionFilesInput:
+		return p.actionFilesInput(act)
+	case ActionGetResource:
+		return p.actionGetResource(out, act)
+	case ActionExtract:
+		return p.actionExtract(out, act)
+	case ActionSetMethod, ActionAddHeader, ActionDeleteHeader, ActionSetHeader, ActionSetBody:
+		return p.actionAddRule(act)
+	case ActionKeyboard:
+		return p.actionKeyboard(act)
+	case ActionDebug:
+		return nil
+	case ActionSleep:
+		return p.actionSleep(act)
+	case ActionWaitVisible:
+		return p.actionWaitVisible(act)
+	case ActionWaitEvent:
+		return p.actionWaitEvent(out, act)
+	case ActionEmulate:
+		return p.actionEmulate(act)
+	case ActionPDF:
+		return p.actionPDF(act)
+	case ActionHARCapture:
+		return p.actionHARCapture(out, act)
+	case ActionIntercept, ActionMock:
+		return p.actionIntercept(act)
+	case ActionSetCookie:
+		return p.actionSetCookie(act)
+	case ActionGetCookie:
+		return p.actionGetCookie(out, act)
+	case ActionDeleteCookie:
+		return p.actionDeleteCookie(act)
+	case ActionSetStorage:
+		return p.actionSetStorage(act)
+	case ActionGetStorage:
+		return p.actionGetStorage(out, act)
+	case ActionDeleteStorage:
+		return p.actionDeleteStorage(act)
+	case ActionWaitNetworkIdle:
+		return p.actionWaitNetworkIdle(act)
+	default:
+		return errors.Errorf("unknown action type: %s", act.ActionType)
+	}
+}
+
+func (p *Page) actionNavigate(input *url.URL, act *Action) error {
+	navigateURL := strings.ReplaceAll(act.Data["url"], "{{BaseURL}}", input.String())
+	return p.page.Navigate(navigateURL)
+}
+
+func (p *Page) actionScript(out map[string]string, act *Action) error {
+	if act.Data["hook"] == "true" {
+		return p.page.EvalOnNewDocument(act.Data["code"])
+	}
+	result, err := p.page.Eval(act.Data["code"])
+	if err != nil {
+		return errors.Wrap(err, "could not run script")
+	}
+	if act.Name != "" {
+		out[act.Name] = result.Value.String()
+	}
+	return nil
+}
+
+func (p *Page) selector(act *Action) string {
+	if act.Data["by"] == "x" {
+		return "xpath://" + strings.TrimPrefix(act.Data["xpath"], "//")
+	}
+	return act.Data["selector"]
+}
+
+func (p *Page) element(act *Action) (*rod.Element, error) {
+	if act.Data["by"] == "x" {
+		return p.page.ElementX(act.Data["xpath"])
+	}
+	return p.page.Element(act.Data["selector"])
+}
+
+func (p *Page) actionClick(act *Action, right bool) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	button := proto.InputMouseButtonLeft
+	if right {
+		button = proto.InputMouseButtonRight
+	}
+	return element.Click(button, 1)
+}
+
+func (p *Page) actionTextInput(act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	return element.Input(act.Data["value"])
+}
+
+func (p *Page) actionTimeInput(act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	parsed, err := time.Parse(time.RFC3339, act.Data["value"])
+	if err != nil {
+		return errors.Wrap(err, "could not parse time value")
+	}
+	return element.Input(parsed.Format("2006-01-02"))
+}
+
+func (p *Page) actionSelectInput(act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	return element.Select([]string{act.Data["value"]}, act.Data["selected"] == "true", rod.SelectorTypeText)
+}
+
+func (p *Page) actionFilesInput(act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	return element.SetFiles(strings.Split(act.Data["value"], ","))
+}
+
+func (p *Page) actionGetResource(out map[string]string, act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	attribute, err := element.Attribute("src")
+	if err != nil || attribute == nil {
+		return errors.Wrap(err, "could not get resource attribute")
+	}
+	if act.Name != "" {
+		out[act.Name] = *attribute
+	}
+	return nil
+}
+
+func (p *Page) actionExtract(out map[string]string, act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not find element")
+	}
+	text, err := element.Text()
+	if err != nil {
+		return errors.Wrap(err, "could not extract text")
+	}
+	if act.Name != "" {
+		out[act.Name] = text
+	}
+	return nil
+}
+
+// actionAddRule queues a request/response mutation rule to be applied
+// by the hijack router for every subsequent navigation on this page.
+func (p *Page) actionAddRule(act *Action) error {
+	r := &rule{Action: act.ActionType, Part: act.Data["part"], Args: act.Data}
+	p.rules = append(p.rules, r)
+	return nil
+}
+
+func (p *Page) actionKeyboard(act *Action) error {
+	return p.page.InsertText(act.Data["keys"])
+}
+
+func (p *Page) actionSleep(act *Action) error {
+	seconds, err := time.ParseDuration(act.Data["duration"] + "s")
+	if err != nil {
+		return errors.Wrap(err, "could not parse sleep duration")
+	}
+	time.Sleep(seconds)
+	return nil
+}
+
+func (p *Page) actionWaitVisible(act *Action) error {
+	element, err := p.element(act)
+	if err != nil {
+		return errors.Wrap(err, "could not wait element")
+	}
+	if err := element.WaitVisible(); err != nil {
+		return errors.Wrap(err, "could not wait element")
+	}
+	return nil
+}
+
+// actionWaitEvent is implemented in waitevent.go.
+
+// actionScreenshot is implemented in capture.go.
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"io/ioutil"
+	"strconv"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// actionScreenshot takes a screenshot of the page, optionally scoped to
+// an element/xpath selector or stitched across the full scrollable page,
+// and writes it to disk in the requested format when a "to" path is set.
+func (p *Page) actionScreenshot(act *Action) error {
+	format := proto.PageCaptureScreenshotFormatPng
+	switch act.Data["format"] {
+	case "jpeg":
+		format = proto.PageCaptureScreenshotFormatJpeg
+	case "webp":
+		format = proto.PageCaptureScreenshotFormatWebp
+	}
+
+	var quality *int
+	if v := act.Data["quality"]; v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.Wrap(err, "could not parse screenshot quality")
+		}
+		quality = &parsed
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case act.Data["selector"] != "" || act.Data["xpath"] != "":
+		element, elemErr := p.element(act)
+		if elemErr != nil {
+			return errors.Wrap(elemErr, "could not find element")
+		}
+		data, err = element.Screenshot(format, quality)
+	case act.Data["fullpage"] == "true":
+		data, err = p.page.Screenshot(true, &proto.PageCaptureScreenshot{
+			Format:  format,
+			Quality: quality,
+		})
+	default:
+		data, err = p.page.Screenshot(false, &proto.PageCaptureScreenshot{
+			Format:  format,
+			Quality: quality,
+		})
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not take screenshot")
+	}
+
+	if to := act.Data["to"]; to != "" {
+		if writeErr := ioutil.WriteFile(to+"."+screenshotExtension(act.Data["format"]), data, 0644); writeErr != nil {
+			return errors.Wrap(writeErr, "could not write screenshot")
+		}
+	}
+	return nil
+}
+
+func screenshotExtension(format string) string {
+	switch format {
+	case "jpeg":
+		return "jpg"
+	case "webp":
+		return "webp"
+	default:
+		return "png"
+	}
+}
+
+// actionPDF renders the current page to a PDF document using the
+// options supported by the Chrome DevTools Page.printToPDF command.
+func (p *Page) actionPDF(act *Action) error {
+	req := &proto.PagePrintToPDF{
+		Landscape:       act.Data["landscape"] == "true",
+		PrintBackground: act.Data["printBackground"] == "true",
+	}
+	if v := act.Data["paperWidth"]; v != "" {
+		width, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.Wrap(err, "could not parse paperWidth")
+		}
+		req.PaperWidth = &width
+	}
+	if v := act.Data["paperHeight"]; v != "" {
+		height, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.Wrap(err, "could not parse paperHeight")
+		}
+		req.PaperHeight = &height
+	}
+
+	reader, err := p.page.PDF(req)
+	if err != nil {
+		return errors.Wrap(err, "could not render pdf")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(err, "could not read pdf output")
+	}
+	if to := act.Data["to"]; to != "" {
+		if writeErr := ioutil.WriteFile(to+".pdf", data, 0644); writeErr != nil {
+			return errors.Wrap(writeErr, "could not write pdf")
+		}
+	}
+	return nil
+}
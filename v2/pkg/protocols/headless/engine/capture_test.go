@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionScreenshotElementAndFullpage(t *testing.T) {
+	browser, instance, err := setUp(t)
+	defer browser.Close()
+	defer instance.Close()
+	require.Nil(t, err, "could not create browser instance")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `
+		<html>
+		<head><title>Nuclei Test Page</title></head>
+		<body><div id="crop" style="width:50px;height:50px;background:red;"></div></body>
+		</html>`)
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse URL")
+
+	t.Run("element", func(t *testing.T) {
+		actions := []*Action{
+			{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: "waitload"},
+			{ActionType: "screenshot", Data: map[string]string{"to": "test-element", "selector": "#crop"}},
+		}
+		_, page, err := instance.Run(parsed, actions, 20*time.Second)
+		require.Nil(t, err, "could not run page actions")
+		defer page.Close()
+
+		require.FileExists(t, "test-element.png")
+		os.Remove("test-element.png")
+	})
+
+	t.Run("fullpage", func(t *testing.T) {
+		actions := []*Action{
+			{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: "waitload"},
+			{ActionType: "screenshot", Data: map[string]string{"to": "test-fullpage", "fullpage": "true", "format": "jpeg"}},
+		}
+		_, page, err := instance.Run(parsed, actions, 20*time.Second)
+		require.Nil(t, err, "could not run page actions")
+		defer page.Close()
+
+		require.FileExists(t, "test-fullpage.jpg")
+		os.Remove("test-fullpage.jpg")
+	})
+}
+
+func TestActionPDF(t *testing.T) {
+	browser, instance, err := setUp(t)
+	defer browser.Close()
+	defer instance.Close()
+	require.Nil(t, err, "could not create browser instance")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><head><title>Nuclei Test Page</title></head><body></body></html>`)
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse URL")
+
+	actions := []*Action{
+		{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: "waitload"},
+		{ActionType: "pdf", Data: map[string]string{"to": "test-export", "landscape": "true"}},
+	}
+	_, page, err := instance.Run(parsed, actions, 20*time.Second)
+	require.Nil(t, err, "could not run page actions")
+	defer page.Close()
+
+	require.FileExists(t, "test-export.pdf")
+	data, err := ioutil.ReadFile("test-export.pdf")
+	require.Nil(t, err, "could not read pdf")
+	require.True(t, len(data) > 4 && string(data[:4]) == "%PDF", "invalid pdf signature")
+	os.Remove("test-export.pdf")
+}
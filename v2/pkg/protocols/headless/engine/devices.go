@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"strconv"
+
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// devicePresets maps the preset names accepted by the `emulate` action
+// to the matching rod device definition. Only the commonly requested
+// mobile form-factors are kept here, additional presets from rod's
+// devices package can be wired in the same way as the need arises.
+var devicePresets = map[string]devices.Device{
+	"iPhone12": devices.IPhone12,
+	"iPhoneX":  devices.IPhoneX,
+	"PixelXL":  devices.PixelXL,
+	"iPad":     devices.IPad,
+}
+
+// actionEmulate switches the page into a named device profile (preset)
+// or a custom viewport before subsequent actions run.
+func (p *Page) actionEmulate(act *Action) error {
+	if preset := act.Data["preset"]; preset != "" {
+		device, ok := devicePresets[preset]
+		if !ok {
+			return errors.Errorf("unknown device preset: %s", preset)
+		}
+		if act.Data["orientation"] == "landscape" {
+			device = device.Landscape()
+		}
+		p.page.MustEmulate(device)
+		return nil
+	}
+	return p.emulateCustom(act)
+}
+
+func (p *Page) emulateCustom(act *Action) error {
+	width, err := strconv.Atoi(act.Data["width"])
+	if err != nil {
+		return errors.Wrap(err, "could not parse emulate width")
+	}
+	height, err := strconv.Atoi(act.Data["height"])
+	if err != nil {
+		return errors.Wrap(err, "could not parse emulate height")
+	}
+	scaleFactor := 1.0
+	if v := act.Data["deviceScaleFactor"]; v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.Wrap(err, "could not parse emulate deviceScaleFactor")
+		}
+		scaleFactor = parsed
+	}
+	mobile := act.Data["mobile"] == "true"
+	touch := act.Data["touch"] == "true"
+
+	if err := p.page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             width,
+		Height:            height,
+		DeviceScaleFactor: scaleFactor,
+		Mobile:            mobile,
+	}); err != nil {
+		return errors.Wrap(err, "could not set viewport")
+	}
+	if touch {
+		req := proto.EmulationSetTouchEmulationEnabled{Enabled: true}
+		if err := req.Call(p.page); err != nil {
+			return errors.Wrap(err, "could not enable touch emulation")
+		}
+	}
+	if ua := act.Data["userAgent"]; ua != "" {
+		req := proto.EmulationSetUserAgentOverride{UserAgent: ua}
+		if err := req.Call(p.page); err != nil {
+			return errors.Wrap(err, "could not set user agent")
+		}
+	}
+	return nil
+}
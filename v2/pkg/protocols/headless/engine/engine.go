@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// Browser is a single browser instance used for headless navigation. It
+// wraps the underlying rod browser and keeps track of the options used
+// to launch it so new instances/pages can reuse the same configuration.
+type Browser struct {
+	tempDir    string
+	engine     *rod.Browser
+	httpclient *http.Client
+	options    *types.Options
+}
+
+// New creates a new nuclei headless browser module.
+func New(options *types.Options) (*Browser, error) {
+	dataStore, err := ioutil.TempDir("", "nuclei-headless-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temporary directory")
+	}
+
+	launcherURL := launcher.New().
+		Headless(!options.ShowBrowser).
+		Set("disable-gpu", "true").
+		Set("ignore-certificate-errors", "true").
+		Set("disable-setuid-sandbox", "true").
+		Set("no-sandbox", "true").
+		UserDataDir(dataStore).
+		MustLaunch()
+
+	browser := rod.New().ControlURL(launcherURL)
+	if err := browser.Connect(); err != nil {
+		return nil, errors.Wrap(err, "could not connect to browser")
+	}
+
+	engine := &Browser{
+		tempDir:    dataStore,
+		engine:     browser,
+		httpclient: newhttpClient(options),
+		options:    options,
+	}
+	return engine, nil
+}
+
+// NewInstance creates a new instance from the browser, which shares
+// the lifetime of the parent browser but can be used concurrently with
+// other instances.
+func (b *Browser) NewInstance() (*Instance, error) {
+	return &Instance{browser: b}, nil
+}
+
+// Close closes the browser and releases all held resources.
+func (b *Browser) Close() {
+	b.engine.MustClose()
+	_ = os.RemoveAll(b.tempDir)
+}
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// harDocument is a minimal HAR 1.2 document, as defined by the HTTP
+// Archive specification (http://www.softwareishard.com/blog/har-12-spec/).
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string     `json:"startedDateTime"`
+	Time            float64    `json:"time"`
+	Request         harMessage `json:"request"`
+	Response        harMessage `json:"response"`
+	Cache           struct{}   `json:"cache"`
+	Timings         harTimings `json:"timings"`
+	requestID       proto.NetworkRequestID
+	finished        bool
+}
+
+type harMessage struct {
+	Method      string      `json:"method,omitempty"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	Cookies     []struct{}  `json:"cookies"`
+	QueryString []struct{}  `json:"queryString"`
+	PostData    *harPost    `json:"postData,omitempty"`
+	Content     *harContent `json:"content,omitempty"`
+	Status      int         `json:"status,omitempty"`
+	StatusText  string      `json:"statusText,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPost struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harRecorder attaches to the CDP Network domain of a page and keeps
+// track of in-flight requests, writing the resulting HAR document to
+// disk and/or the out map when the page is closed.
+type harRecorder struct {
+	page        *Page
+	to          string
+	name        string
+	outMap      map[string]string
+	includeBody bool
+	entries     map[proto.NetworkRequestID]*harEntry
+}
+
+func (p *Page) actionHARCapture(out map[string]string, act *Action) error {
+	recorder := &harRecorder{
+		page:        p,
+		to:          act.Data["to"],
+		name:        act.Name,
+		outMap:      out,
+		includeBody: act.Data["body"] == "true",
+		entries:     make(map[proto.NetworkRequestID]*harEntry),
+	}
+	if err := recorder.attach(); err != nil {
+		return errors.Wrap(err, "could not attach har recorder")
+	}
+	p.har = recorder
+	return nil
+}
+
+func (h *harRecorder) attach() error {
+	page := h.page.page
+
+	go page.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+		entry := &harEntry{
+			StartedDateTime: time.Now().Format(time.RFC3339),
+			requestID:       e.RequestID,
+			Request: harMessage{
+				Method:      e.Request.Method,
+				URL:         e.Request.URL,
+				HTTPVersion: "HTTP/1.1",
+			},
+		}
+		for k, v := range e.Request.Headers {
+			entry.Request.Headers = append(entry.Request.Headers, harHeader{Name: k, Value: v.String()})
+		}
+		if e.Request.PostData != "" {
+			entry.Request.PostData = &harPost{MimeType: "text/plain", Text: e.Request.PostData}
+		}
+		h.entries[e.RequestID] = entry
+	}, func(e *proto.NetworkResponseReceived) {
+		entry, ok := h.entries[e.RequestID]
+		if !ok {
+			return
+		}
+		entry.Response.Status = int(e.Response.Status)
+		entry.Response.StatusText = e.Response.StatusText
+		entry.Response.URL = e.Response.URL
+		for k, v := range e.Response.Headers {
+			entry.Response.Headers = append(entry.Response.Headers, harHeader{Name: k, Value: v.String()})
+		}
+	}, func(e *proto.NetworkLoadingFinished) {
+		entry, ok := h.entries[e.RequestID]
+		if !ok {
+			return
+		}
+		entry.finished = true
+		if h.includeBody {
+			req := proto.NetworkGetResponseBody{RequestID: e.RequestID}
+			if body, err := req.Call(page); err == nil {
+				content := &harContent{Size: len(body.Body), MimeType: "application/octet-stream"}
+				if body.Base64Encoded {
+					content.Encoding = "base64"
+					content.Text = body.Body
+				} else {
+					content.Text = body.Body
+				}
+				entry.Response.Content = content
+			}
+		}
+	}, func(e *proto.NetworkLoadingFailed) {
+		if entry, ok := h.entries[e.RequestID]; ok {
+			entry.finished = true
+		}
+	})()
+
+	return nil
+}
+
+// flush serializes the recorded entries into a HAR document and writes
+// it to disk (when `to` was set) and into the out map.
+func (h *harRecorder) flush() error {
+	entries := make([]*harEntry, 0, len(h.entries))
+	for _, entry := range h.entries {
+		entries = append(entries, entry)
+	}
+
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "nuclei", Version: "1.0"},
+		Entries: entries,
+	}}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal har document")
+	}
+
+	if h.name != "" {
+		h.outMap[h.name] = string(data)
+	}
+	if h.to != "" {
+		if err := ioutil.WriteFile(h.to, data, 0644); err != nil {
+			return errors.Wrap(err, "could not write har document")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionHARCapture(t *testing.T) {
+	browser, instance, err := setUp(t)
+	defer browser.Close()
+	defer instance.Close()
+	require.Nil(t, err, "could not create browser instance")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><head><title>Nuclei Test Page</title></head><body></body></html>`)
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse URL")
+
+	actions := []*Action{
+		{ActionType: "harcapture", Data: map[string]string{"to": "test.har"}, Name: "har"},
+		{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: "waitload"},
+	}
+	out, page, err := instance.Run(parsed, actions, 20*time.Second)
+	require.Nil(t, err, "could not run page actions")
+	page.Close()
+
+	require.FileExists(t, "test.har")
+	data, err := ioutil.ReadFile("test.har")
+	require.Nil(t, err, "could not read har file")
+
+	var doc harDocument
+	require.Nil(t, json.Unmarshal(data, &doc), "could not parse har document")
+	require.Equal(t, "1.2", doc.Log.Version)
+	require.NotEmpty(t, out["har"], "har document not stored in out map")
+
+	os.Remove("test.har")
+}
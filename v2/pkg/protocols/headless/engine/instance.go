@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// Instance is an instance of the browser driver, which can be used to
+// run a chain of actions against a target and collect their results.
+type Instance struct {
+	browser *Browser
+}
+
+// Run runs a list of actions against a target url and returns the
+// output data map collected by named actions along with the page
+// the actions were executed on, so callers can perform additional
+// inspection or clean up after themselves.
+func (i *Instance) Run(input *url.URL, actions []*Action, timeout time.Duration) (map[string]string, *Page, error) {
+	rodPage, err := i.browser.engine.Page(rod.PageTarget())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not create page")
+	}
+	rodPage = rodPage.Timeout(timeout)
+
+	page := &Page{
+		page:     rodPage,
+		instance: i,
+		rules:    []*rule{},
+	}
+
+	out := make(map[string]string)
+	for _, act := range actions {
+		if err := page.RunAction(out, input, act); err != nil {
+			return out, page, errors.Wrapf(err, "could not run action %s", act.ActionType)
+		}
+	}
+	return out, page, nil
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// interceptRule is a single URL-pattern match rule registered by the
+// `intercept`/`mock` action, applied to every request issued by the page
+// for the remainder of its lifetime.
+type interceptRule struct {
+	pattern   string
+	isRegex   bool
+	directive string // block, redirect, respond, modify
+	status    int
+	headers   map[string]string
+	body      string
+	target    string // redirect target / modify target field
+}
+
+func (r *interceptRule) matches(requestURL string) bool {
+	if r.isRegex {
+		matched, err := regexp.MatchString(r.pattern, requestURL)
+		return err == nil && matched
+	}
+	matched, err := filepath.Match(r.pattern, requestURL)
+	return err == nil && matched || strings.Contains(requestURL, r.pattern)
+}
+
+// actionIntercept registers an interception rule for this page and, on
+// the first call, enables the Fetch domain router that applies it.
+func (p *Page) actionIntercept(act *Action) error {
+	status, _ := strconv.Atoi(act.Data["status"])
+
+	rule := &interceptRule{
+		pattern:   act.Data["pattern"],
+		isRegex:   act.Data["regex"] == "true",
+		directive: act.Data["directive"],
+		status:    status,
+		body:      act.Data["body"],
+		target:    act.Data["target"],
+	}
+	if rule.directive == "" {
+		rule.directive = "block"
+	}
+
+	p.interceptRules = append(p.interceptRules, rule)
+	if p.interceptRouter == nil {
+		return p.startInterceptRouter()
+	}
+	return nil
+}
+
+func (p *Page) startInterceptRouter() error {
+	router := p.page.HijackRequests()
+	p.interceptRouter = router
+
+	router.MustAdd("*", func(h *rod.Hijack) {
+		requestURL := h.Request.URL().String()
+		for _, rule := range p.interceptRules {
+			if !rule.matches(requestURL) {
+				continue
+			}
+			switch rule.directive {
+			case "block":
+				h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+				return
+			case "redirect":
+				h.Request.Req().URL, _ = h.Request.Req().URL.Parse(rule.target)
+				_ = h.LoadResponse(nil, true)
+				return
+			case "respond":
+				h.Response.SetHeader("Content-Type", "text/plain")
+				if rule.status != 0 {
+					h.Response.Payload().ResponseCode = rule.status
+				}
+				h.Response.SetBody(rule.body)
+				return
+			case "modify":
+				if rule.body != "" {
+					h.Response.SetBody(rule.body)
+				}
+				return
+			}
+		}
+		_ = h.LoadResponse(nil, true)
+	})
+
+	go router.Run()
+	return nil
+}
+
+// stopInterceptRouter releases the hijack router, called as part of
+// page cleanup.
+func (p *Page) stopInterceptRouter() {
+	if p.interceptRouter != nil {
+		_ = p.interceptRouter.Stop()
+	}
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// actionWaitNetworkIdle blocks until there have been no in-flight
+// network requests for a configurable idle window, or the action's
+// own timeout elapses. `idle` sets the idle window (default 500ms)
+// and `timeout` bounds the overall wait (default 5s).
+func (p *Page) actionWaitNetworkIdle(act *Action) error {
+	idle := 500 * time.Millisecond
+	if v := act.Data["idle"]; v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.Wrap(err, "could not parse idle window")
+		}
+		idle = time.Duration(ms) * time.Millisecond
+	}
+	timeout := 5 * time.Second
+	if v := act.Data["timeout"]; v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.Wrap(err, "could not parse timeout")
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	var mu sync.Mutex
+	inflight := 0
+	idleTimer := time.NewTimer(idle)
+
+	go p.page.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+		mu.Lock()
+		inflight++
+		mu.Unlock()
+		idleTimer.Stop()
+	}, func(e *proto.NetworkLoadingFinished) {
+		mu.Lock()
+		inflight--
+		remaining := inflight
+		mu.Unlock()
+		if remaining <= 0 {
+			idleTimer.Reset(idle)
+		}
+	}, func(e *proto.NetworkLoadingFailed) {
+		mu.Lock()
+		inflight--
+		remaining := inflight
+		mu.Unlock()
+		if remaining <= 0 {
+			idleTimer.Reset(idle)
+		}
+	})()
+
+	select {
+	case <-idleTimer.C:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("timeout waiting for network idle")
+	}
+}
@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"github.com/go-rod/rod"
+)
+
+// rule is a pending request/response modification rule registered by
+// one of the header/body/method mutation actions. Rules are queued on
+// the page and applied to the hijacked request chain that follows.
+type rule struct {
+	Action ActionType
+	Part   string
+	Args   map[string]string
+}
+
+// Page is a single page in a browser instance, and holds the rod page
+// along with any pending rules and state collected while running actions.
+type Page struct {
+	page            *rod.Page
+	instance        *Instance
+	rules           []*rule
+	har             *harRecorder
+	interceptRules  []*interceptRule
+	interceptRouter *rod.HijackRouter
+}
+
+// Page returns the underlying rod page for direct access in tests or
+// advanced use-cases not covered by the action set.
+func (p *Page) Page() *rod.Page {
+	return p.page
+}
+
+// Close closes the page releasing the underlying rod resources, flushing
+// any pending HAR capture to disk first.
+func (p *Page) Close() {
+	if p.har != nil {
+		_ = p.har.flush()
+	}
+	p.stopInterceptRouter()
+	if p.page != nil {
+		_ = p.page.Close()
+	}
+}
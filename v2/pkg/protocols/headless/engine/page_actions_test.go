@@ -643,6 +643,58 @@ func TestActionSetBody(t *testing.T) {
 }
 
 func TestActionWaitEvent(t *testing.T) {
+	t.Run("console message", func(t *testing.T) {
+		browser, instance, err := setUp(t)
+		defer browser.Close()
+		defer instance.Close()
+		require.Nil(t, err, "could not create browser instance")
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, `
+			<html>
+				<head><title>Nuclei Test Page</title></head>
+				<body>
+					<script>setTimeout(() => console.log('payload-triggered'), 100);</script>
+				</body>
+			</html>`)
+		}))
+		defer ts.Close()
+
+		parsed, err := url.Parse(ts.URL)
+		require.Nil(t, err, "could not parse URL")
+
+		actions := []*Action{
+			{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: "waitevent", Data: map[string]string{"event": "console.messageAdded", "pattern": "payload-triggered"}, Name: "console"},
+		}
+		out, page, err := instance.Run(parsed, actions, 20*time.Second)
+		require.Nil(t, err, "could not run page actions")
+		defer page.Close()
+
+		require.Contains(t, out["console"], "payload-triggered")
+	})
+
+	t.Run("timeout when event never fires", func(t *testing.T) {
+		browser, instance, err := setUp(t)
+		defer browser.Close()
+		defer instance.Close()
+		require.Nil(t, err, "could not create browser instance")
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, `<html><head><title>Nuclei Test Page</title></head><body></body></html>`)
+		}))
+		defer ts.Close()
+
+		parsed, err := url.Parse(ts.URL)
+		require.Nil(t, err, "could not parse URL")
+
+		actions := []*Action{
+			{ActionType: "navigate", Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: "waitevent", Data: map[string]string{"event": "console.messageAdded", "pattern": "never-happens"}},
+		}
+		_, _, err = instance.Run(parsed, actions, 2*time.Second)
+		require.Error(t, err)
+	})
 }
 
 func TestActionKeyboard(t *testing.T) {
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// actionSetCookie sets a single cookie on the page via the Network
+// domain, scoped to the page's current URL unless a domain is given.
+func (p *Page) actionSetCookie(act *Action) error {
+	cookie := &proto.NetworkCookieParam{
+		Name:   act.Data["name"],
+		Value:  act.Data["value"],
+		Domain: act.Data["domain"],
+		Path:   act.Data["path"],
+		Secure: act.Data["secure"] == "true",
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	req := proto.NetworkSetCookie{
+		Name:   cookie.Name,
+		Value:  cookie.Value,
+		Domain: cookie.Domain,
+		Path:   cookie.Path,
+		Secure: cookie.Secure,
+	}
+	if cookie.Domain == "" {
+		info, err := p.page.Info()
+		if err != nil {
+			return errors.Wrap(err, "could not get page url")
+		}
+		req.URL = info.URL
+	}
+	if err := req.Call(p.page); err != nil {
+		return errors.Wrap(err, "could not set cookie")
+	}
+	return nil
+}
+
+// actionGetCookie reads a named cookie from the page and stores its
+// value into the out map.
+func (p *Page) actionGetCookie(out map[string]string, act *Action) error {
+	cookies, err := p.page.Cookies([]string{})
+	if err != nil {
+		return errors.Wrap(err, "could not get cookies")
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == act.Data["name"] {
+			if act.Name != "" {
+				out[act.Name] = cookie.Value
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("cookie %s not found", act.Data["name"])
+}
+
+// actionDeleteCookie removes a named cookie from the page.
+func (p *Page) actionDeleteCookie(act *Action) error {
+	req := proto.NetworkDeleteCookies{
+		Name:   act.Data["name"],
+		Domain: act.Data["domain"],
+		Path:   act.Data["path"],
+	}
+	if err := req.Call(p.page); err != nil {
+		return errors.Wrap(err, "could not delete cookie")
+	}
+	return nil
+}
+
+// actionSetStorage writes a key/value pair to the page's localStorage
+// or sessionStorage (selected via the `type` field, default local).
+func (p *Page) actionSetStorage(act *Action) error {
+	store := storageObject(act.Data["type"])
+	script := fmt.Sprintf("() => %s.setItem(%q, %q)", store, act.Data["key"], act.Data["value"])
+	_, err := p.page.Eval(script)
+	if err != nil {
+		return errors.Wrap(err, "could not set storage item")
+	}
+	return nil
+}
+
+// actionGetStorage reads a key from storage into the out map.
+func (p *Page) actionGetStorage(out map[string]string, act *Action) error {
+	store := storageObject(act.Data["type"])
+	script := fmt.Sprintf("() => %s.getItem(%q)", store, act.Data["key"])
+	result, err := p.page.Eval(script)
+	if err != nil {
+		return errors.Wrap(err, "could not get storage item")
+	}
+	if act.Name != "" {
+		out[act.Name] = result.Value.String()
+	}
+	return nil
+}
+
+// actionDeleteStorage removes a key from storage.
+func (p *Page) actionDeleteStorage(act *Action) error {
+	store := storageObject(act.Data["type"])
+	script := fmt.Sprintf("() => %s.removeItem(%q)", store, act.Data["key"])
+	if _, err := p.page.Eval(script); err != nil {
+		return errors.Wrap(err, "could not delete storage item")
+	}
+	return nil
+}
+
+func storageObject(kind string) string {
+	if kind == "session" {
+		return "window.sessionStorage"
+	}
+	return "window.localStorage"
+}
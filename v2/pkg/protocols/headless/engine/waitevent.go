@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// actionWaitEvent blocks until a named CDP event fires, optionally
+// filtered by a pattern, and records any captured console/JS error
+// output into the out map for later matching. For
+// Page.javascriptDialogOpening it returns at once and accepts or
+// dismisses the dialog in the background when it opens.
+func (p *Page) actionWaitEvent(out map[string]string, act *Action) error {
+	switch act.Data["event"] {
+	case "console.messageAdded":
+		return p.waitConsoleMessage(out, act)
+	case "Runtime.exceptionThrown":
+		return p.waitJSException(out, act)
+	case "Page.javascriptDialogOpening":
+		return p.waitDialog(act)
+	case "Network.responseReceived":
+		return p.waitNetworkResponse(out, act)
+	case "Page.frameNavigated":
+		return p.waitFrameNavigated(act)
+	default:
+		return errors.Errorf("unknown waitevent event: %s", act.Data["event"])
+	}
+}
+
+func (p *Page) waitConsoleMessage(out map[string]string, act *Action) error {
+	pattern := act.Data["pattern"]
+	level := act.Data["level"]
+
+	message, err := waitWithFilter(p, func(e *proto.RuntimeConsoleAPICalled) bool {
+		if level != "" && string(e.Type) != level {
+			return false
+		}
+		return matchesConsoleArgs(e, pattern)
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not wait for console event")
+	}
+	if act.Name != "" {
+		out[act.Name] = message
+	}
+	return nil
+}
+
+func matchesConsoleArgs(e *proto.RuntimeConsoleAPICalled, pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+	for _, arg := range e.Args {
+		if arg.Value.Str() != "" {
+			if matched, _ := regexp.MatchString(pattern, arg.Value.Str()); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Page) waitJSException(out map[string]string, act *Action) error {
+	e := &proto.RuntimeExceptionThrown{}
+	p.page.WaitEvent(e)()
+	if act.Name != "" {
+		out[act.Name] = e.ExceptionDetails.Text
+	}
+	return nil
+}
+
+func (p *Page) waitDialog(act *Action) error {
+	wait, handle := p.page.HandleDialog()
+	go func() {
+		wait()
+		accept := act.Data["action"] != "dismiss"
+		_ = handle(&proto.PageHandleJavaScriptDialog{Accept: accept})
+	}()
+	return nil
+}
+
+func (p *Page) waitNetworkResponse(out map[string]string, act *Action) error {
+	urlPattern := act.Data["url"]
+	statusMin, statusMax := parseStatusRange(act.Data["status"])
+
+	for {
+		e := &proto.NetworkResponseReceived{}
+		p.page.WaitEvent(e)()
+		if urlPattern != "" && !strings.Contains(e.Response.URL, urlPattern) {
+			continue
+		}
+		status := int(e.Response.Status)
+		if status < statusMin || status > statusMax {
+			continue
+		}
+		if act.Name != "" {
+			out[act.Name] = e.Response.URL
+		}
+		return nil
+	}
+}
+
+func parseStatusRange(value string) (int, int) {
+	if value == "" {
+		return 0, 999
+	}
+	parts := strings.SplitN(value, "-", 2)
+	min, _ := strconv.Atoi(parts[0])
+	max := min
+	if len(parts) == 2 {
+		max, _ = strconv.Atoi(parts[1])
+	}
+	return min, max
+}
+
+func (p *Page) waitFrameNavigated(act *Action) error {
+	target := act.Data["url"]
+	for {
+		e := &proto.PageFrameNavigated{}
+		p.page.WaitEvent(e)()
+		if target == "" || strings.Contains(e.Frame.URL, target) {
+			return nil
+		}
+	}
+}
+
+// waitWithFilter blocks until an event matching the predicate is
+// observed, returning a short textual summary built from its arguments,
+// or returns the context's error once the page's context is done.
+func waitWithFilter(page *Page, match func(*proto.RuntimeConsoleAPICalled) bool) (string, error) {
+	for {
+		e := &proto.RuntimeConsoleAPICalled{}
+		page.page.WaitEvent(e)()
+		if err := page.page.GetContext().Err(); err != nil {
+			return "", err
+		}
+		if match(e) {
+			var parts []string
+			for _, arg := range e.Args {
+				parts = append(parts, arg.Value.Str())
+			}
+			return strings.Join(parts, " "), nil
+		}
+	}
+}
@@ -100,16 +100,19 @@ type generatedRequest struct {
 	meta            map[string]interface{}
 	pipelinedClient *rawhttp.PipelineClient
 	request         *retryablehttp.Request
+	// ctx is the per-request context, carrying the deadline/cancellation
+	// derived from the template timeout. It is consulted by the rawhttp
+	// code paths in executeRequest, which have no native context support.
+	ctx context.Context
 }
 
 // Make creates a http request for the provided input.
 // It returns io.EOF as error when all the requests have been exhausted.
-func (r *requestGenerator) Make(baseURL string, dynamicValues map[string]interface{}) (*generatedRequest, error) {
+func (r *requestGenerator) Make(ctx context.Context, baseURL string, dynamicValues map[string]interface{}) (*generatedRequest, error) {
 	data, payloads, ok := r.nextValue()
 	if !ok {
 		return nil, io.EOF
 	}
-	ctx := context.Background()
 
 	parsed, err := url.Parse(baseURL)
 	if err != nil {
@@ -168,7 +171,7 @@ func (r *requestGenerator) makeHTTPRequestFromModel(ctx context.Context, data st
 	if err != nil {
 		return nil, err
 	}
-	return &generatedRequest{request: request}, nil
+	return &generatedRequest{request: request, ctx: ctx}, nil
 }
 
 // makeHTTPRequestFromRaw creates a *http.Request from a raw request
@@ -225,6 +228,7 @@ func (r *requestGenerator) handleRawWithPaylods(ctx context.Context, rawRequest,
 			rawRequest: rawRequestData,
 			meta:       genValues,
 			original:   r.request,
+			ctx:        ctx,
 		}
 		return unsafeReq, nil
 	}
@@ -235,7 +239,13 @@ func (r *requestGenerator) handleRawWithPaylods(ctx context.Context, rawRequest,
 	if r.request.Race {
 		// More or less this ensures that all requests hit the endpoint at the same approximated time
 		// Todo: sync internally upon writing latest request byte
-		body = race.NewOpenGateWithTimeout(body, time.Duration(2)*time.Second)
+		raceTimeout := time.Duration(2) * time.Second
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); remaining < raceTimeout {
+				raceTimeout = remaining
+			}
+		}
+		body = race.NewOpenGateWithTimeout(body, raceTimeout)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, rawRequestData.Method, rawRequestData.FullURL, body)
@@ -252,7 +262,7 @@ func (r *requestGenerator) handleRawWithPaylods(ctx context.Context, rawRequest,
 	if err != nil {
 		return nil, err
 	}
-	return &generatedRequest{request: request, meta: genValues}, nil
+	return &generatedRequest{request: request, meta: genValues, ctx: ctx}, nil
 }
 
 // fillRequest fills various headers in the request with values
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -27,7 +28,7 @@ import (
 const defaultMaxWorkers = 150
 
 // executeRaceRequest executes race condition request for a URL
-func (r *Request) executeRaceRequest(reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (r *Request) executeRaceRequest(ctx context.Context, reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	generator := r.newGenerator()
 
 	maxWorkers := r.RaceNumberRequests
@@ -36,14 +37,17 @@ func (r *Request) executeRaceRequest(reqURL string, dynamicValues, previous outp
 	var requestErr error
 	mutex := &sync.Mutex{}
 
-	request, err := generator.Make(reqURL, nil)
+	request, err := generator.Make(ctx, reqURL, nil)
 	if err != nil {
 		return err
 	}
 	for i := 0; i < r.RaceNumberRequests; i++ {
+		if ctx.Err() != nil {
+			break
+		}
 		swg.Add()
 		go func(httpRequest *generatedRequest) {
-			err := r.executeRequest(reqURL, httpRequest, dynamicValues, previous, callback)
+			err := r.executeRequest(ctx, reqURL, httpRequest, dynamicValues, previous, callback)
 			mutex.Lock()
 			if err != nil {
 				requestErr = multierr.Append(requestErr, err)
@@ -53,11 +57,14 @@ func (r *Request) executeRaceRequest(reqURL string, dynamicValues, previous outp
 		}(request)
 	}
 	swg.Wait()
+	if requestErr == nil {
+		requestErr = ctx.Err()
+	}
 	return requestErr
 }
 
 // executeRaceRequest executes parallel requests for a template
-func (r *Request) executeParallelHTTP(reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (r *Request) executeParallelHTTP(ctx context.Context, reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	generator := r.newGenerator()
 
 	// Workers that keeps enqueuing new requests
@@ -67,7 +74,11 @@ func (r *Request) executeParallelHTTP(reqURL string, dynamicValues, previous out
 	var requestErr error
 	mutex := &sync.Mutex{}
 	for {
-		request, err := generator.Make(reqURL, dynamicValues)
+		if ctx.Err() != nil {
+			requestErr = ctx.Err()
+			break
+		}
+		request, err := generator.Make(ctx, reqURL, dynamicValues)
 		if err == io.EOF {
 			break
 		}
@@ -79,8 +90,11 @@ func (r *Request) executeParallelHTTP(reqURL string, dynamicValues, previous out
 		go func(httpRequest *generatedRequest) {
 			defer swg.Done()
 
+			if ctx.Err() != nil {
+				return
+			}
 			r.options.RateLimiter.Take()
-			err := r.executeRequest(reqURL, httpRequest, dynamicValues, previous, callback)
+			err := r.executeRequest(ctx, reqURL, httpRequest, dynamicValues, previous, callback)
 			mutex.Lock()
 			if err != nil {
 				requestErr = multierr.Append(requestErr, err)
@@ -94,7 +108,7 @@ func (r *Request) executeParallelHTTP(reqURL string, dynamicValues, previous out
 }
 
 // executeRaceRequest executes turbo http request for a URL
-func (r *Request) executeTurboHTTP(reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (r *Request) executeTurboHTTP(ctx context.Context, reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	generator := r.newGenerator()
 
 	// need to extract the target from the url
@@ -125,7 +139,11 @@ func (r *Request) executeTurboHTTP(reqURL string, dynamicValues, previous output
 	var requestErr error
 	mutex := &sync.Mutex{}
 	for {
-		request, err := generator.Make(reqURL, dynamicValues)
+		if ctx.Err() != nil {
+			requestErr = ctx.Err()
+			break
+		}
+		request, err := generator.Make(ctx, reqURL, dynamicValues)
 		if err == io.EOF {
 			break
 		}
@@ -139,7 +157,10 @@ func (r *Request) executeTurboHTTP(reqURL string, dynamicValues, previous output
 		go func(httpRequest *generatedRequest) {
 			defer swg.Done()
 
-			err := r.executeRequest(reqURL, httpRequest, dynamicValues, previous, callback)
+			if ctx.Err() != nil {
+				return
+			}
+			err := r.executeRequest(ctx, reqURL, httpRequest, dynamicValues, previous, callback)
 			mutex.Lock()
 			if err != nil {
 				requestErr = multierr.Append(requestErr, err)
@@ -154,26 +175,57 @@ func (r *Request) executeTurboHTTP(reqURL string, dynamicValues, previous output
 
 // ExecuteWithResults executes the final request on a URL
 func (r *Request) ExecuteWithResults(reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	ctx := context.Background()
+	if r.options.Options.ScanContext != nil {
+		ctx = r.options.Options.ScanContext
+	}
+	return r.ExecuteWithResultsContext(ctx, reqURL, dynamicValues, previous, callback)
+}
+
+// ExecuteWithResultsContext is the context-aware variant of ExecuteWithResults.
+//
+// The request's context is cancelled, and all in-flight goroutines bail out
+// with ctx.Err(), whichever happens first among: the parent ctx being done
+// (e.g. Options.ScanContext was cancelled via output.WithCancel, or its
+// GlobalDeadline elapsed), r.options.Options.TemplateTimeout elapsing, or
+// r.options.Options.PerTargetTimeout elapsing. Zero values for the two
+// timeouts leave cancellation entirely up to the parent ctx.
+func (r *Request) ExecuteWithResultsContext(ctx context.Context, reqURL string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	if timeout := r.options.Options.TemplateTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if timeout := r.options.Options.PerTargetTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	// verify if pipeline was requested
 	if r.Pipeline {
-		return r.executeTurboHTTP(reqURL, dynamicValues, previous, callback)
+		return r.executeTurboHTTP(ctx, reqURL, dynamicValues, previous, callback)
 	}
 
 	// verify if a basic race condition was requested
 	if r.Race && r.RaceNumberRequests > 0 {
-		return r.executeRaceRequest(reqURL, dynamicValues, previous, callback)
+		return r.executeRaceRequest(ctx, reqURL, dynamicValues, previous, callback)
 	}
 
 	// verify if parallel elaboration was requested
 	if r.Threads > 0 {
-		return r.executeParallelHTTP(reqURL, dynamicValues, previous, callback)
+		return r.executeParallelHTTP(ctx, reqURL, dynamicValues, previous, callback)
 	}
 
 	generator := r.newGenerator()
 
 	var requestErr error
 	for {
-		request, err := generator.Make(reqURL, dynamicValues)
+		if ctx.Err() != nil {
+			requestErr = ctx.Err()
+			break
+		}
+		request, err := generator.Make(ctx, reqURL, dynamicValues)
 		if err == io.EOF {
 			break
 		}
@@ -184,7 +236,7 @@ func (r *Request) ExecuteWithResults(reqURL string, dynamicValues, previous outp
 
 		var gotOutput bool
 		r.options.RateLimiter.Take()
-		err = r.executeRequest(reqURL, request, dynamicValues, previous, func(event *output.InternalWrappedEvent) {
+		err = r.executeRequest(ctx, reqURL, request, dynamicValues, previous, func(event *output.InternalWrappedEvent) {
 			// Add the extracts to the dynamic values if any.
 			if event.OperatorsResult != nil {
 				gotOutput = true
@@ -207,8 +259,115 @@ func (r *Request) ExecuteWithResults(reqURL string, dynamicValues, previous outp
 
 const drainReqSize = int64(8 * 1024)
 
+// doRawWithContext runs a rawhttp call, which has no native context support,
+// on its own goroutine and races it against ctx - mirroring the netstack
+// deadline-timer pattern of a separate cancellation signal that wakes up the
+// caller without waiting on the in-flight socket. If ctx wins, the call's
+// eventual response body is drained and closed in the background, the same
+// way a failed request is drained below.
+func doRawWithContext(ctx context.Context, do func() (*http.Response, error)) (*http.Response, error) {
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	resultChan := make(chan result, 1)
+	go func() {
+		resp, err := do()
+		resultChan <- result{resp, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		go func() {
+			res := <-resultChan
+			if res.resp != nil && res.resp.Body != nil {
+				_, _ = io.CopyN(ioutil.Discard, res.resp.Body, drainReqSize)
+				res.resp.Body.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	case res := <-resultChan:
+		return res.resp, res.err
+	}
+}
+
+// doRequestWithRetries performs the transport call for request, re-issuing it
+// according to the HTTP request's retry policy (r.MaxRetries, r.RetryBackoff,
+// r.RetryStatus, r.RetryOnErr) until a response is accepted, the policy is
+// exhausted, or ctx is done. Project-file cache hits are never retried. It
+// returns the final response/error along with whether it was served from
+// cache and how many attempts were made (1 when no retry happened).
+func (r *Request) doRequestWithRetries(ctx context.Context, reqURL string, request *generatedRequest, dumpedRequest []byte, timings *output.RequestTimings) (resp *http.Response, fromcache bool, attempts int, err error) {
+	maxAttempts := r.MaxRetries + 1
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempts = 1; attempts <= maxAttempts; attempts++ {
+		resp, fromcache, err = r.doRequest(ctx, reqURL, request, dumpedRequest, timings)
+		if fromcache || attempts == maxAttempts || !shouldRetryResponse(resp, err, r.RetryStatus, r.RetryOnErr) {
+			return resp, fromcache, attempts, err
+		}
+
+		// rawhttp doesn't support draining response bodies.
+		if resp != nil && resp.Body != nil && request.rawRequest == nil {
+			_, _ = io.CopyN(ioutil.Discard, resp.Body, drainReqSize)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, fromcache, attempts, ctx.Err()
+		case <-time.After(retryDelay(attempts, r.RetryBackoff, r.RetryInitialDelay, r.RetryMaxDelay)):
+		}
+		r.options.RateLimiter.Take()
+	}
+	return resp, fromcache, attempts, err
+}
+
+// doRequest performs a single attempt of the transport call, dispatching to
+// the pipelined, raw (unsafe) or standard retryablehttp client depending on
+// the request mode.
+func (r *Request) doRequest(ctx context.Context, reqURL string, request *generatedRequest, dumpedRequest []byte, timings *output.RequestTimings) (resp *http.Response, fromcache bool, err error) {
+	if request.original.Pipeline {
+		resp, err = doRawWithContext(ctx, func() (*http.Response, error) {
+			return request.pipelinedClient.DoRaw(request.rawRequest.Method, reqURL, request.rawRequest.Path, generators.ExpandMapValues(request.rawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.rawRequest.Data)))
+		})
+		return resp, false, err
+	}
+	if request.original.Unsafe && request.rawRequest != nil {
+		options := request.original.rawhttpClient.Options
+		options.AutomaticContentLength = !r.DisableAutoContentLength
+		options.AutomaticHostHeader = !r.DisableAutoHostname
+		options.FollowRedirects = r.Redirects
+		options.CustomHeaders = request.rawRequest.UnsafeHeaders
+		resp, err = doRawWithContext(ctx, func() (*http.Response, error) {
+			return request.original.rawhttpClient.DoRawWithOptions(request.rawRequest.Method, reqURL, request.rawRequest.Path, generators.ExpandMapValues(request.rawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.rawRequest.Data)), options)
+		})
+		return resp, false, err
+	}
+
+	// if nuclei-project is available check if the request was already sent previously
+	if r.options.ProjectFile != nil {
+		// nolint:bodyclose // false positive the response is generated at runtime
+		if cached, cacheErr := r.options.ProjectFile.Get(dumpedRequest); cacheErr == nil {
+			return cached, true, nil
+		}
+	}
+	req := request.request
+	if timings != nil {
+		req = req.WithContext(withTimingTrace(req.Context(), timings))
+	}
+	resp, err = r.httpClient.Do(req)
+	return resp, false, err
+}
+
 // executeRequest executes the actual generated request and returns error if occured
-func (r *Request) executeRequest(reqURL string, request *generatedRequest, dynamicvalues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (r *Request) executeRequest(ctx context.Context, reqURL string, request *generatedRequest, dynamicvalues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
 	// Add User-Agent value randomly to the customHeaders slice if `random-agent` flag is given
 	if r.options.Options.RandomAgent {
 		r.customHeaders["User-Agent"] = uarand.GetRandom()
@@ -231,40 +390,23 @@ func (r *Request) executeRequest(reqURL string, request *generatedRequest, dynam
 
 	var formedURL string
 	var hostname string
-	timeStart := time.Now()
-	if request.original.Pipeline {
+	if request.original.Pipeline || (request.original.Unsafe && request.rawRequest != nil) {
 		formedURL = request.rawRequest.FullURL
-		if parsed, err := url.Parse(formedURL); err == nil {
-			hostname = parsed.Hostname()
-		}
-		resp, err = request.pipelinedClient.DoRaw(request.rawRequest.Method, reqURL, request.rawRequest.Path, generators.ExpandMapValues(request.rawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.rawRequest.Data)))
-	} else if request.original.Unsafe && request.rawRequest != nil {
-		formedURL = request.rawRequest.FullURL
-		if parsed, err := url.Parse(formedURL); err == nil {
-			hostname = parsed.Hostname()
-		}
-		options := request.original.rawhttpClient.Options
-		options.AutomaticContentLength = !r.DisableAutoContentLength
-		options.AutomaticHostHeader = !r.DisableAutoHostname
-		options.FollowRedirects = r.Redirects
-		options.CustomHeaders = request.rawRequest.UnsafeHeaders
-		resp, err = request.original.rawhttpClient.DoRawWithOptions(request.rawRequest.Method, reqURL, request.rawRequest.Path, generators.ExpandMapValues(request.rawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.rawRequest.Data)), options)
 	} else {
-		hostname = request.request.URL.Hostname()
 		formedURL = request.request.URL.String()
-		// if nuclei-project is available check if the request was already sent previously
-		if r.options.ProjectFile != nil {
-			// if unavailable fail silently
-			fromcache = true
-			// nolint:bodyclose // false positive the response is generated at runtime
-			resp, err = r.options.ProjectFile.Get(dumpedRequest)
-			if err != nil {
-				fromcache = false
-			}
-		}
-		if resp == nil {
-			resp, err = r.httpClient.Do(request.request)
-		}
+	}
+	if parsed, err := url.Parse(formedURL); err == nil {
+		hostname = parsed.Hostname()
+	}
+
+	timeStart := time.Now()
+	timings := &output.RequestTimings{}
+	var attempts int
+	resp, fromcache, attempts, err = r.doRequestWithRetries(ctx, reqURL, request, dumpedRequest, timings)
+	timings.Total = time.Since(timeStart)
+	trace := &output.RequestTrace{RawRequest: string(dumpedRequest), Timings: *timings}
+	if resp != nil && resp.Request != nil && resp.Request.URL.String() != reqURL {
+		trace.Redirects = []string{resp.Request.URL.String()}
 	}
 	if resp == nil {
 		err = errors.New("no response got for request")
@@ -275,13 +417,15 @@ func (r *Request) executeRequest(reqURL string, request *generatedRequest, dynam
 			_, _ = io.CopyN(ioutil.Discard, resp.Body, drainReqSize)
 			resp.Body.Close()
 		}
-		r.options.Output.Request(r.options.TemplateID, reqURL, "http", err)
+		if ctxErr := ctx.Err(); ctxErr != nil && output.WasCancelled(ctx) {
+			err = &output.CancelledError{Err: ctxErr}
+		}
+		r.options.Output.Request(r.options.TemplateID, reqURL, "http", err, trace)
 		r.options.Progress.DecrementRequests(1)
 		return err
 	}
 
 	gologger.Verbose().Msgf("[%s] Sent HTTP request to %s", r.options.TemplateID, formedURL)
-	r.options.Output.Request(r.options.TemplateID, reqURL, "http", err)
 
 	duration := time.Since(timeStart)
 
@@ -324,6 +468,9 @@ func (r *Request) executeRequest(reqURL string, request *generatedRequest, dynam
 	dumpedResponse := dumpedResponseBuilder.Bytes()
 	redirectedResponse = bytes.ReplaceAll(redirectedResponse, dataOrig, data)
 
+	trace.RawResponse = string(dumpedResponse)
+	r.options.Output.Request(r.options.TemplateID, reqURL, "http", nil, trace)
+
 	// Dump response - step 2 - replace gzip body with deflated one or with itself (NOP operation)
 	if r.options.Options.Debug || r.options.Options.DebugResponse {
 		gologger.Info().Msgf("[%s] Dumped HTTP response for %s\n\n", r.options.TemplateID, formedURL)
@@ -348,6 +495,7 @@ func (r *Request) executeRequest(reqURL string, request *generatedRequest, dynam
 	outputEvent := r.responseToDSLMap(resp, reqURL, matchedURL, tostring.UnsafeToString(dumpedRequest), tostring.UnsafeToString(dumpedResponse), tostring.UnsafeToString(data), headersToString(resp.Header), duration, request.meta)
 	outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
 	outputEvent["redirect-chain"] = tostring.UnsafeToString(redirectedResponse)
+	outputEvent["retry_count"] = attempts
 	for k, v := range previous {
 		outputEvent[k] = v
 	}
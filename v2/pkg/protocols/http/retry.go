@@ -0,0 +1,75 @@
+package http
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryBackoffType is the delay strategy used between retry attempts of a
+// single HTTP request.
+type RetryBackoffType string
+
+const (
+	// RetryBackoffExponential doubles the delay on every attempt.
+	RetryBackoffExponential RetryBackoffType = "exponential"
+	// RetryBackoffLinear grows the delay by a fixed initial-delay step on every attempt.
+	RetryBackoffLinear RetryBackoffType = "linear"
+	// RetryBackoffJitter behaves like exponential backoff but randomizes the
+	// delay to avoid multiple workers retrying the same target in lockstep.
+	RetryBackoffJitter RetryBackoffType = "jitter"
+)
+
+const (
+	defaultRetryInitialDelay = 1 * time.Second
+	defaultRetryMaxDelay     = 30 * time.Second
+)
+
+// retryDelay returns how long to wait before the given retry attempt
+// (1-indexed) given the configured backoff strategy, falling back to sane
+// defaults when initialDelay/maxDelay are unset.
+func retryDelay(attempt int, backoff RetryBackoffType, initialDelay, maxDelay time.Duration) time.Duration {
+	if initialDelay <= 0 {
+		initialDelay = defaultRetryInitialDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	exponent := math.Pow(2, float64(attempt-1))
+
+	var delay time.Duration
+	switch backoff {
+	case RetryBackoffLinear:
+		delay = initialDelay * time.Duration(attempt)
+	case RetryBackoffJitter:
+		base := time.Duration(float64(initialDelay) * exponent)
+		delay = base/2 + time.Duration(rand.Int63n(int64(base/2)+1))
+	default: // RetryBackoffExponential
+		delay = time.Duration(float64(initialDelay) * exponent)
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// shouldRetryResponse reports whether a completed attempt (resp, err)
+// warrants another try under the configured retry policy: a transport error
+// only counts when retryOnErr is set, and a successful response only counts
+// when its status code is in retryStatus.
+func shouldRetryResponse(resp *http.Response, err error, retryStatus []int, retryOnErr bool) bool {
+	if err != nil {
+		return retryOnErr
+	}
+	if resp == nil {
+		return false
+	}
+	for _, status := range retryStatus {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
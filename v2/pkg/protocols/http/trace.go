@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// withTimingTrace attaches an httptrace.ClientTrace to ctx that fills in
+// timings' DNS/Connect/TLSHandshake/FirstByte phases as the standard
+// library's transport reaches each stage, so the timing breakdown plumbed
+// into output.Writer.Request reflects the actual transport, not an
+// estimate. Timings.Total is left to the caller, who has the overall
+// start/end around the whole retry loop.
+func withTimingTrace(ctx context.Context, timings *output.RequestTimings) context.Context {
+	var dnsStart, connectStart, tlsStart, reqStart time.Time
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(_ string) {
+			reqStart = time.Now()
+		},
+		DNSStart: func(_ httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(_ httptrace.DNSDoneInfo) {
+			if !dnsStart.IsZero() {
+				timings.DNS = time.Since(dnsStart)
+			}
+		},
+		ConnectStart: func(_, _ string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil && !connectStart.IsZero() {
+				timings.Connect = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil && !tlsStart.IsZero() {
+				timings.TLSHandshake = time.Since(tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			if !reqStart.IsZero() {
+				timings.FirstByte = time.Since(reqStart)
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace)
+}
@@ -1,6 +1,7 @@
 package multi
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/model"
@@ -14,6 +15,16 @@ import (
 	errorutil "github.com/projectdiscovery/utils/errors"
 )
 
+// EmitLast propagates only the last protocol's event to the outer callback,
+// matching the executor's historical behavior.
+const EmitLast = "last"
+
+// EmitAll propagates every protocol's event to the outer callback, as it fires.
+const EmitAll = "all"
+
+// EmitMatched propagates only the events of protocols whose operators matched.
+const EmitMatched = "matched"
+
 var _ protocols.Request = &Request{}
 
 // refer doc.go for package description , limitations etc
@@ -39,6 +50,19 @@ type Request struct {
 	//   - value: exampleInfoStructure
 	Info model.Info `yaml:"info" json:"info" jsonschema:"title=info for the template,description=Info contains metadata for the template"`
 
+	// description: |
+	//   Emit controls which protocols' events in Queue propagate to the
+	//   outer callback (and therefore to matches/output):
+	//     - "last" (default): only the final protocol's event, preserving
+	//       the historical single-result-per-template behavior.
+	//     - "all": every protocol's event, as it fires.
+	//     - "matched": only the events of protocols whose own operators matched.
+	// values:
+	//   - "last"
+	//   - "all"
+	//   - "matched"
+	Emit string `yaml:"emit,omitempty" json:"emit,omitempty" jsonschema:"title=which protocol events to emit,description=Controls which protocols' events propagate to the template's matches/output,enum=last,enum=all,enum=matched"`
+
 	// Queue is queue of all protocols present in the template
 	Queue []protocols.Request `yaml:"-" json:"-"`
 	// request executor options
@@ -53,6 +77,31 @@ func (r *Request) getLastRequest() protocols.Request {
 	return r.Queue[len(r.Queue)-1]
 }
 
+// protocolTypeKey is the InternalEvent key protoCallback stamps with the
+// type of the protocol that produced the event, so MakeResultEventItem/
+// MakeResultEvent can attribute a per-protocol event (emitted via
+// Emit "all"/"matched") to the queue entry that actually produced it,
+// instead of always assuming the last protocol in the queue.
+const protocolTypeKey = "internal_multi_protocol_type"
+
+// getRequestForEvent returns the Queue entry that produced wrapped, as
+// recorded by protocolTypeKey, falling back to the last request in the
+// queue if the event carries no such marker (or no match is found).
+func (r *Request) getRequestForEvent(wrapped *output.InternalWrappedEvent) protocols.Request {
+	if wrapped != nil {
+		if rawType, ok := wrapped.InternalEvent[protocolTypeKey]; ok {
+			if protoType, ok := rawType.(string); ok {
+				for _, req := range r.Queue {
+					if fmt.Sprintf("%s", req.Type()) == protoType {
+						return req
+					}
+				}
+			}
+		}
+	}
+	return r.getLastRequest()
+}
+
 // Requests returns the total number of requests template will send
 func (r *Request) Requests() int {
 	var count int
@@ -90,36 +139,61 @@ func (r *Request) Extract(data map[string]interface{}, matcher *extractors.Extra
 	return protocols.MakeDefaultExtractFunc(data, matcher)
 }
 
-// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
-func (r *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
-	var finalProtoEvent *output.InternalWrappedEvent
-	// callback to process results from all protocols
-	multiProtoCallback := func(event *output.InternalWrappedEvent) {
-		finalProtoEvent = event
-		// export dynamic values from operators (i.e internal:true)
-		if event.OperatorsResult != nil && len(event.OperatorsResult.DynamicValues) > 0 {
-			for k, v := range event.OperatorsResult.DynamicValues {
-				// TBD: iterate-all is only supported in `http` protocol
-				// we either need to add support for iterate-all in other protocols or implement a different logic (specific to template context)
-				// currently if dynamic value array only contains one value we replace it with the value
-				if len(v) == 1 {
-					r.options.TemplateCtx.Set(k, v[0])
+// emitMode returns the configured Emit mode, defaulting to EmitLast when unset.
+func (r *Request) emitMode() string {
+	if r.Emit == "" {
+		return EmitLast
+	}
+	return r.Emit
+}
+
+// namespaceEventVars returns a copy of vars with every key prefixed by
+// label (e.g. "status_code" -> "http_1_status_code"), so a protocol's
+// variables can be told apart from other protocols' in the same template
+// once multiple protocols' events reach the output.
+func namespaceEventVars(vars output.InternalEvent, label string) output.InternalEvent {
+	namespaced := make(output.InternalEvent, len(vars))
+	for k, v := range vars {
+		namespaced[label+"_"+k] = v
+	}
+	return namespaced
+}
+
+// mergeDynamicValues folds operators.Result.DynamicValues (values captured
+// by `internal: true` extractors) into ctx, unprefixed, so later protocols
+// in the queue can keep referencing them by their original name.
+func mergeDynamicValues(ctx *contextargs.Context, result *operators.Result) {
+	if result == nil || len(result.DynamicValues) == 0 {
+		return
+	}
+	for k, v := range result.DynamicValues {
+		// TBD: iterate-all is only supported in `http` protocol
+		// we either need to add support for iterate-all in other protocols or implement a different logic (specific to template context)
+		// currently if dynamic value array only contains one value we replace it with the value
+		if len(v) == 1 {
+			ctx.Set(k, v[0])
+		} else {
+			// Note: if extracted value contains multiple values then they can be accessed by indexing
+			// ex: if values are dynamic = []string{"a","b","c"} then they are available as
+			// dynamic = "a" , dynamic1 = "b" , dynamic2 = "c"
+			// we intentionally omit first index for unknown situations (where no of extracted values are not known)
+			for i, val := range v {
+				if i == 0 {
+					ctx.Set(k, val)
 				} else {
-					// Note: if extracted value contains multiple values then they can be accessed by indexing
-					// ex: if values are dynamic = []string{"a","b","c"} then they are available as
-					// dynamic = "a" , dynamic1 = "b" , dynamic2 = "c"
-					// we intentionally omit first index for unknown situations (where no of extracted values are not known)
-					for i, val := range v {
-						if i == 0 {
-							r.options.TemplateCtx.Set(k, val)
-						} else {
-							r.options.TemplateCtx.Set(k+strconv.Itoa(i), val)
-						}
-					}
+					ctx.Set(k+strconv.Itoa(i), val)
 				}
 			}
 		}
 	}
+}
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (r *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	emit := r.emitMode()
+	protocolIndex := make(map[types.ProtocolType]int)
+
+	var lastEvent *output.InternalWrappedEvent
 
 	// template context: contains values extracted using `internal` extractor from previous protocols
 	// these values are extracted from each protocol in queue and are passed to next protocol in queue
@@ -127,34 +201,67 @@ func (r *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues,
 	// this makes it possible to use multi protocol templates in workflows
 	// Note: internal extractor values take precedence over dynamicValues from workflows (i.e other templates in workflow)
 
-	// execute all protocols in the queue
+	// execute all protocols in the queue, forwarding each one's event as it
+	// fires (subject to emit), instead of only the last protocol's.
 	for _, req := range r.Queue {
-		err := req.ExecuteWithResults(input, dynamicValues, previous, multiProtoCallback)
+		protocolIndex[req.Type()]++
+		label := fmt.Sprintf("%s_%d", req.Type(), protocolIndex[req.Type()])
+
+		protoCallback := func(event *output.InternalWrappedEvent) {
+			lastEvent = event
+
+			if event.InternalEvent != nil {
+				event.InternalEvent[protocolTypeKey] = fmt.Sprintf("%s", req.Type())
+			}
+
+			// export dynamic values from operators (i.e internal:true) for
+			// downstream protocols in the queue, keyed by their original name
+			mergeDynamicValues(r.options.TemplateCtx, event.OperatorsResult)
+
+			// namespace this protocol's variables so multiple protocols'
+			// events can be correlated once they reach the output, e.g.
+			// http_1_status_code, dns_2_answer.
+			for k, v := range namespaceEventVars(event.InternalEvent, label) {
+				event.InternalEvent[k] = v
+			}
+
+			switch emit {
+			case EmitAll:
+				callback(event)
+			case EmitMatched:
+				if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+					callback(event)
+				}
+			}
+		}
+
+		err := req.ExecuteWithResults(input, dynamicValues, previous, protoCallback)
 		// if error skip execution of next protocols
 		if err != nil {
 			return err
 		}
 	}
-	// Review: how to handle events of multiple protocols in a single template
-	// currently the outer callback is only executed once (for the last protocol in queue)
-	// due to workflow logic at https://github.com/projectdiscovery/nuclei/blob/main/v2/pkg/protocols/common/executer/executer.go#L150
-	// this causes addition of duplicated / unncessary variables with prefix template_id_all_variables
-	callback(finalProtoEvent)
+
+	// default/"last" behavior: only the final protocol's event propagates.
+	if emit == EmitLast && lastEvent != nil {
+		callback(lastEvent)
+	}
 
 	return nil
 }
 
 // MakeResultEventItem creates a result event from internal wrapped event. Intended to be used by MakeResultEventItem internally
 func (r *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
-	if r.getLastRequest() == nil {
+	req := r.getRequestForEvent(wrapped)
+	if req == nil {
 		return nil
 	}
-	return r.getLastRequest().MakeResultEventItem(wrapped)
+	return req.MakeResultEventItem(wrapped)
 }
 
 // MakeResultEvent creates a flat list of result events from an internal wrapped event, based on successful matchers and extracted data
 func (r *Request) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
-	return protocols.MakeDefaultResultEvent(r.getLastRequest(), wrapped)
+	return protocols.MakeDefaultResultEvent(r.getRequestForEvent(wrapped), wrapped)
 }
 
 // GetCompiledOperators returns a list of the compiled operators
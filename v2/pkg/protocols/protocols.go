@@ -1,7 +1,10 @@
 package protocols
 
 import (
+	"sync"
+
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/auth/challenge"
 	"github.com/projectdiscovery/nuclei/v2/pkg/catalogue"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
@@ -43,6 +46,10 @@ type ExecuterOptions struct {
 	Catalogue *catalogue.Catalogue
 	// ProjectFile is the project file for nuclei
 	ProjectFile *projectfile.ProjectFile
+	// Authenticator resolves WWW-Authenticate challenges into an
+	// Authorization header for requests that support challenge-driven
+	// auth. Nil disables it.
+	Authenticator *challenge.Authenticator
 }
 
 // Request is an interface implemented any protocol based request generator.
@@ -58,3 +65,27 @@ type Request interface {
 	// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 	ExecuteWithResults(input string, metadata output.InternalEvent) ([]*output.InternalWrappedEvent, error)
 }
+
+var (
+	factoriesMu sync.Mutex
+	factories   = make(map[string]func() Request)
+)
+
+// Register adds a Request factory under name, so the template loader can
+// dispatch a template's top-level protocol key (e.g. "websocket", "grpc")
+// to the right implementation instead of growing a hardcoded switch for
+// every protocol nuclei ships.
+func Register(name string, factory func() Request) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// GetRequestFactory returns the Request factory registered under name, if
+// any was registered.
+func GetRequestFactory(name string) (func() Request, bool) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factory, ok := factories[name]
+	return factory, ok
+}
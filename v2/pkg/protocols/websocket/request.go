@@ -0,0 +1,240 @@
+// Package websocket implements a protocol.Request that performs a WebSocket
+// handshake and exchanges a scripted sequence of text frames with a server.
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+)
+
+func init() {
+	protocols.Register("websocket", func() protocols.Request { return &Request{} })
+}
+
+// Request is a request for the websocket protocol. It upgrades via an
+// Unsafe-style raw HTTP handshake (reusing requests.ParseRawRequest) and
+// then exchanges a scripted sequence of text messages over the resulting
+// connection.
+type Request struct {
+	// Raw is the raw HTTP request used to perform the WebSocket upgrade
+	// handshake, in the same format as BulkHTTPRequest's Raw requests.
+	Raw string `yaml:"raw"`
+	// Messages is the sequence of text messages sent, in order, once the
+	// handshake completes.
+	Messages []string `yaml:"messages"`
+	// Expect holds, for each entry in Messages at the same index, a
+	// substring the corresponding reply must contain when no Matchers are
+	// configured.
+	Expect []string `yaml:"expect,omitempty"`
+	// ReadTimeout is the maximum time to wait for a reply to each message.
+	ReadTimeout time.Duration `yaml:"read-timeout,omitempty"`
+
+	// Matchers contains the detection mechanism for the request to identify
+	// whether the request was successful
+	Matchers []*matchers.Matcher `yaml:"matchers,omitempty"`
+	// MatchersCondition is the condition between the matchers. Default is OR.
+	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+	matchersCondition matchers.ConditionType
+	// Extractors contains the extraction mechanism for the request to
+	// identify and extract data from the response.
+	Extractors []*extractors.Extractor `yaml:"extractors,omitempty"`
+
+	options *protocols.ExecuterOptions
+}
+
+var _ protocols.Request = &Request{}
+
+const defaultReadTimeout = 5 * time.Second
+
+// Compile compiles the request generators preparing any requests possible.
+func (r *Request) Compile(options *protocols.ExecuterOptions) error {
+	if r.Raw == "" {
+		return errors.New("raw handshake request is required for websocket requests")
+	}
+	if len(r.Messages) == 0 {
+		return errors.New("at least one message is required for websocket requests")
+	}
+	if r.ReadTimeout == 0 {
+		r.ReadTimeout = defaultReadTimeout
+	}
+
+	r.matchersCondition = matchers.ORCondition
+	if r.MatchersCondition == "and" {
+		r.matchersCondition = matchers.ANDCondition
+	}
+
+	r.options = options
+	return nil
+}
+
+// Requests returns the total number of requests the rule will perform.
+func (r *Request) Requests() int {
+	return 1
+}
+
+// Match performs matching operation for a matcher on model and returns true or false.
+func (r *Request) Match(data map[string]interface{}, matcher *matchers.Matcher) bool {
+	part, ok := data["response"]
+	if !ok {
+		return false
+	}
+	response, ok := part.(string)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case len(matcher.Words) > 0:
+		matched, _ := matcher.MatchWords(response, nil)
+		return matched
+	case len(matcher.Regex) > 0:
+		matched, _ := matcher.MatchRegex(response)
+		return matched
+	case len(matcher.Binary) > 0:
+		matched, _ := matcher.MatchBinary(response)
+		return matched
+	case len(matcher.DSL) > 0:
+		return matcher.MatchDSL(data)
+	default:
+		return false
+	}
+}
+
+// Extract performs extracting operation for an extractor on model and returns true or false.
+func (r *Request) Extract(data map[string]interface{}, extractor *extractors.Extractor) map[string]struct{} {
+	part, ok := data["response"]
+	if !ok {
+		return nil
+	}
+	response, ok := part.(string)
+	if !ok {
+		return nil
+	}
+	return extractor.Extract(response)
+}
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (r *Request) ExecuteWithResults(input string, metadata output.InternalEvent) ([]*output.InternalWrappedEvent, error) {
+	handshake, err := requests.ParseRawRequest(r.Raw, input)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse websocket handshake request")
+	}
+
+	wsURL, header, err := buildDialTarget(handshake)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not dial websocket")
+	}
+	defer conn.Close()
+
+	var results []*output.InternalWrappedEvent
+	for i, message := range r.Messages {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			return nil, errors.Wrap(err, "could not write websocket message")
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(r.ReadTimeout))
+		_, reply, err := conn.ReadMessage()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read websocket reply")
+		}
+		response := string(reply)
+
+		data := make(output.InternalEvent)
+		for k, v := range metadata {
+			data[k] = v
+		}
+		data["host"] = input
+		data["message"] = message
+		data["response"] = response
+		data["type"] = "websocket"
+
+		event := r.responseToEvent(data)
+		if r.isMatched(data, i) {
+			results = append(results, event)
+		}
+	}
+	return results, nil
+}
+
+// isMatched reports whether the reply at index i satisfies either the
+// configured Matchers, or - when none are configured - the Expect substring
+// at the same index.
+func (r *Request) isMatched(data output.InternalEvent, index int) bool {
+	if len(r.Matchers) > 0 {
+		matchedAll := true
+		for _, matcher := range r.Matchers {
+			if r.Match(data, matcher) {
+				if r.matchersCondition == matchers.ORCondition {
+					return true
+				}
+			} else {
+				matchedAll = false
+			}
+		}
+		return matchedAll
+	}
+	if index >= len(r.Expect) {
+		return false
+	}
+	response, _ := data["response"].(string)
+	return strings.Contains(response, r.Expect[index])
+}
+
+func (r *Request) responseToEvent(data output.InternalEvent) *output.InternalWrappedEvent {
+	return &output.InternalWrappedEvent{InternalEvent: data}
+}
+
+// hopByHopHeaders are stripped from the parsed handshake before it's used to
+// dial, since the WebSocket client library sets its own upgrade-specific
+// headers and re-sending the raw request's copies would conflict.
+var hopByHopHeaders = map[string]struct{}{
+	"Connection":               {},
+	"Upgrade":                  {},
+	"Sec-Websocket-Key":        {},
+	"Sec-Websocket-Version":    {},
+	"Sec-Websocket-Extensions": {},
+	"Content-Length":           {},
+}
+
+// buildDialTarget derives a ws:// or wss:// URL and the header to dial with
+// from a raw HTTP Upgrade handshake request parsed by requests.ParseRawRequest.
+func buildDialTarget(handshake *requests.RawRequest) (string, http.Header, error) {
+	host := handshake.Headers["Host"]
+	if host == "" {
+		return "", nil, errors.New("websocket handshake request has no Host header")
+	}
+
+	scheme := "ws"
+	if strings.HasSuffix(handshake.FullURL, ":443") || strings.Contains(host, ":443") {
+		scheme = "wss"
+	}
+	wsURL := fmt.Sprintf("%s://%s%s", scheme, host, handshake.Path)
+
+	header := make(http.Header)
+	for name, value := range handshake.Headers {
+		if _, skip := hopByHopHeaders[http.CanonicalHeaderKey(name)]; skip {
+			continue
+		}
+		if strings.EqualFold(name, "Host") {
+			continue
+		}
+		header.Set(name, value)
+	}
+	return wsURL, header, nil
+}
@@ -0,0 +1,185 @@
+// Package ratelimit implements a shared, token-bucket rate limiter for
+// HTTPExecutor, capping both the total requests-per-second across a scan and
+// the requests-per-second sent to any single host. It wraps
+// golang.org/x/time/rate, adding per-host bookkeeping (with LRU eviction of
+// idle hosts, mirroring pkg/xfer's responseCache) and an adaptive mode that
+// backs a host's rate off on repeated 429/503 responses.
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultMaxHosts bounds how many idle per-host limiters are kept around at
+// once; the least-recently-used one is evicted once the limit is exceeded.
+const defaultMaxHosts = 10000
+
+// minAdaptiveRate is the floor an adaptive host rate is allowed to back off
+// to, so a consistently-429ing host still gets probed rather than stalling
+// forever.
+const minAdaptiveRate = 0.1
+
+// recoveryFactor is how much a host's adaptive rate grows back towards its
+// configured ceiling on every successful (non-429/503) response.
+const recoveryFactor = 1.05
+
+// Options configures a Limiter.
+type Options struct {
+	// GlobalRPS bounds the total requests per second across every host.
+	// Zero means unlimited.
+	GlobalRPS float64
+	// PerHostRPS bounds the requests per second sent to any single host.
+	// Zero means unlimited.
+	PerHostRPS float64
+	// MaxHosts bounds how many per-host limiters are kept in memory at
+	// once. Defaults to 10000.
+	MaxHosts int
+	// Adaptive halves a host's effective rate whenever it returns a 429
+	// or 503, slowly recovering back towards PerHostRPS on success. Has
+	// no effect if PerHostRPS is zero.
+	Adaptive bool
+}
+
+func (o *Options) setDefaults() {
+	if o.MaxHosts == 0 {
+		o.MaxHosts = defaultMaxHosts
+	}
+}
+
+// Limiter is a global-plus-per-host token-bucket rate limiter, safe for
+// concurrent use by every HTTPExecutor in the process.
+type Limiter struct {
+	options  Options
+	global   *rate.Limiter
+	adaptive bool
+
+	mu      sync.Mutex
+	order   *list.List
+	byHost  map[string]*list.Element
+	maxHost int
+}
+
+type hostEntry struct {
+	host    string
+	limiter *hostLimiter
+}
+
+// hostLimiter tracks a single host's token bucket plus the bookkeeping
+// needed to adapt its rate up or down.
+type hostLimiter struct {
+	mu          sync.Mutex
+	limiter     *rate.Limiter
+	ceiling     float64
+	currentRate float64
+}
+
+// New creates a Limiter from options. A nil options (or all-zero fields)
+// means both the global and per-host limits are unlimited.
+func New(options *Options) *Limiter {
+	if options == nil {
+		options = &Options{}
+	}
+	opts := *options
+	opts.setDefaults()
+
+	l := &Limiter{
+		options:  opts,
+		adaptive: opts.Adaptive && opts.PerHostRPS > 0,
+		order:    list.New(),
+		byHost:   make(map[string]*list.Element),
+		maxHost:  opts.MaxHosts,
+	}
+	if opts.GlobalRPS > 0 {
+		l.global = rate.NewLimiter(rate.Limit(opts.GlobalRPS), burstFor(opts.GlobalRPS))
+	}
+	return l
+}
+
+// Wait blocks until both the global and the host's limiter allow a request
+// to proceed, or ctx is done. host is typically the request's URL host.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	if l.global != nil {
+		if err := l.global.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.options.PerHostRPS <= 0 {
+		return nil
+	}
+	return l.hostFor(host).wait(ctx)
+}
+
+// Observe feeds a response's status code back into the adaptive limiter for
+// host, halving its effective rate on a 429/503 and slowly recovering it on
+// any other status. A no-op unless Options.Adaptive was set.
+func (l *Limiter) Observe(host string, statusCode int) {
+	if !l.adaptive {
+		return
+	}
+	l.hostFor(host).observe(statusCode)
+}
+
+// hostFor returns the hostLimiter for host, creating it on first use and
+// marking it most-recently-used, evicting the oldest entry if the LRU is
+// over capacity.
+func (l *Limiter) hostFor(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.byHost[host]; ok {
+		l.order.MoveToFront(elem)
+		return elem.Value.(*hostEntry).limiter
+	}
+
+	hl := &hostLimiter{
+		limiter:     rate.NewLimiter(rate.Limit(l.options.PerHostRPS), burstFor(l.options.PerHostRPS)),
+		ceiling:     l.options.PerHostRPS,
+		currentRate: l.options.PerHostRPS,
+	}
+	elem := l.order.PushFront(&hostEntry{host: host, limiter: hl})
+	l.byHost[host] = elem
+
+	for l.order.Len() > l.maxHost {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.byHost, oldest.Value.(*hostEntry).host)
+	}
+	return hl
+}
+
+func (h *hostLimiter) wait(ctx context.Context) error {
+	return h.limiter.Wait(ctx)
+}
+
+func (h *hostLimiter) observe(statusCode int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if statusCode == 429 || statusCode == 503 {
+		h.currentRate /= 2
+		if h.currentRate < minAdaptiveRate {
+			h.currentRate = minAdaptiveRate
+		}
+	} else {
+		h.currentRate *= recoveryFactor
+		if h.currentRate > h.ceiling {
+			h.currentRate = h.ceiling
+		}
+	}
+	h.limiter.SetLimit(rate.Limit(h.currentRate))
+}
+
+// burstFor picks a token bucket burst size proportional to rps, so a
+// configured rate of e.g. 1 req/s isn't forced to wait a full second before
+// its very first request. rate.Limiter requires a burst of at least 1.
+func burstFor(rps float64) int {
+	burst := int(rps)
+	if burst < 1 {
+		burst = 1
+	}
+	return burst
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterConvergesToConfiguredGlobalRate(t *testing.T) {
+	const globalRPS = 20
+	const duration = 2 * time.Second
+	const goroutines = 8
+
+	limiter := New(&Options{GlobalRPS: globalRPS})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var total int32
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if err := limiter.Wait(ctx, host); err != nil {
+					return
+				}
+				atomic.AddInt32(&total, 1)
+			}
+		}(string(rune('a' + i)))
+	}
+
+	time.Sleep(duration)
+	close(stop)
+	wg.Wait()
+
+	got := atomic.LoadInt32(&total)
+	// burst tokens (one second's worth) are available immediately, on top
+	// of the steady-state rate sustained for the rest of the run.
+	expected := float64(globalRPS) + float64(globalRPS)*duration.Seconds()
+
+	require.True(t, float64(got) <= expected*1.25, "sent %d requests, expected roughly %.1f at %d rps", got, expected, globalRPS)
+	require.True(t, float64(got) >= expected*0.75, "sent %d requests, expected roughly %.1f at %d rps", got, expected, globalRPS)
+}
+
+func TestLimiterPerHostIsIndependentOfOtherHosts(t *testing.T) {
+	limiter := New(&Options{PerHostRPS: 1000})
+	ctx := context.Background()
+
+	require.NoError(t, limiter.Wait(ctx, "a.example.com"))
+	require.NoError(t, limiter.Wait(ctx, "b.example.com"))
+}
+
+func TestLimiterAdaptiveBacksOffOn429AndRecovers(t *testing.T) {
+	limiter := New(&Options{PerHostRPS: 10, Adaptive: true})
+
+	host := limiter.hostFor("rate-limited.example.com")
+	initial := host.currentRate
+
+	limiter.Observe("rate-limited.example.com", 429)
+	require.True(t, host.currentRate < initial, "rate should drop after a 429")
+
+	afterBackoff := host.currentRate
+	limiter.Observe("rate-limited.example.com", 200)
+	require.True(t, host.currentRate > afterBackoff, "rate should recover after a non-429/503 response")
+}
+
+func TestLimiterAdaptiveIsNoopWithoutOption(t *testing.T) {
+	limiter := New(&Options{PerHostRPS: 10})
+	host := limiter.hostFor("example.com")
+	initial := host.currentRate
+
+	limiter.Observe("example.com", 429)
+	require.Equal(t, initial, host.currentRate)
+}
+
+func TestLimiterEvictsLeastRecentlyUsedHost(t *testing.T) {
+	limiter := New(&Options{PerHostRPS: 10, MaxHosts: 2})
+
+	limiter.hostFor("a.example.com")
+	limiter.hostFor("b.example.com")
+	limiter.hostFor("c.example.com")
+
+	limiter.mu.Lock()
+	_, hasA := limiter.byHost["a.example.com"]
+	_, hasC := limiter.byHost["c.example.com"]
+	count := limiter.order.Len()
+	limiter.mu.Unlock()
+
+	require.False(t, hasA, "least-recently-used host should have been evicted")
+	require.True(t, hasC)
+	require.Equal(t, 2, count)
+}
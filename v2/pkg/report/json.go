@@ -0,0 +1,21 @@
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// WriteJSON marshals r as indented JSON and writes it to path, creating or
+// truncating the file as needed.
+func WriteJSON(path string, r *Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal report")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write report")
+	}
+	return nil
+}
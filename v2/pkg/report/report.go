@@ -0,0 +1,110 @@
+// Package report aggregates the findings of a nuclei run into a single
+// structured document grouping results by target/host, as an alternative
+// to the line-delimited JSON produced by output.NewStandardWriter.
+package report
+
+import "time"
+
+// Finding is the subset of a matched result the aggregated report needs.
+// It is intentionally decoupled from output.ResultEvent so this package has
+// no dependency on the rest of nuclei's output pipeline.
+type Finding struct {
+	Host         string
+	TemplateID   string
+	TemplateInfo map[string]string
+	Severity     string
+	CVEID        string
+	Matched      string
+}
+
+// TemplateSummary describes a single matched template for a host, along
+// with the info block nuclei loaded it with.
+type TemplateSummary struct {
+	TemplateID string            `json:"template-id"`
+	Severity   string            `json:"severity,omitempty"`
+	Info       map[string]string `json:"info,omitempty"`
+	Matched    []string          `json:"matched,omitempty"`
+}
+
+// HostSummary is the per-host vulnerability summary block of the report.
+type HostSummary struct {
+	Host           string            `json:"host"`
+	SeverityCounts map[string]int    `json:"severity-counts,omitempty"`
+	CVEIDs         []string          `json:"cve-ids,omitempty"`
+	Templates      []TemplateSummary `json:"templates,omitempty"`
+
+	templateIndex map[string]int
+	cveSeen       map[string]struct{}
+}
+
+// Report is the aggregated, structured document for a single nuclei run.
+type Report struct {
+	StartedAt     time.Time               `json:"started-at"`
+	FinishedAt    time.Time               `json:"finished-at,omitempty"`
+	Templates     []string                `json:"templates"`
+	TemplateCount int                     `json:"template-count"`
+	TotalRequests int64                   `json:"total-requests"`
+	RateLimit     int                     `json:"rate-limit"`
+	Hosts         map[string]*HostSummary `json:"hosts"`
+}
+
+// New creates a Report for a run about to start scanning templates against
+// targets, recording the scan metadata known up front.
+func New(templates []string, templateCount int, rateLimit int) *Report {
+	return &Report{
+		StartedAt:     time.Now(),
+		Templates:     templates,
+		TemplateCount: templateCount,
+		RateLimit:     rateLimit,
+		Hosts:         make(map[string]*HostSummary),
+	}
+}
+
+// AddFinding folds a single matched finding into the report's per-host
+// summary, creating the host's entry on first sight.
+func (r *Report) AddFinding(f Finding) {
+	host, ok := r.Hosts[f.Host]
+	if !ok {
+		host = &HostSummary{
+			Host:           f.Host,
+			SeverityCounts: make(map[string]int),
+			templateIndex:  make(map[string]int),
+			cveSeen:        make(map[string]struct{}),
+		}
+		r.Hosts[f.Host] = host
+	}
+
+	if f.Severity != "" {
+		host.SeverityCounts[f.Severity]++
+	}
+	if f.CVEID != "" {
+		if _, seen := host.cveSeen[f.CVEID]; !seen {
+			host.cveSeen[f.CVEID] = struct{}{}
+			host.CVEIDs = append(host.CVEIDs, f.CVEID)
+		}
+	}
+
+	if idx, ok := host.templateIndex[f.TemplateID]; ok {
+		if f.Matched != "" {
+			host.Templates[idx].Matched = append(host.Templates[idx].Matched, f.Matched)
+		}
+		return
+	}
+	summary := TemplateSummary{TemplateID: f.TemplateID, Severity: f.Severity, Info: f.TemplateInfo}
+	if f.Matched != "" {
+		summary.Matched = []string{f.Matched}
+	}
+	host.templateIndex[f.TemplateID] = len(host.Templates)
+	host.Templates = append(host.Templates, summary)
+}
+
+// IncRequests adds delta to the report's total request count. It is safe to
+// call repeatedly as the scan progresses.
+func (r *Report) IncRequests(delta int64) {
+	r.TotalRequests += delta
+}
+
+// Finish marks the report as complete, recording the run's end time.
+func (r *Report) Finish() {
+	r.FinishedAt = time.Now()
+}
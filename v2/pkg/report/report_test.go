@@ -0,0 +1,41 @@
+package report
+
+import "testing"
+
+func TestReportAddFinding(t *testing.T) {
+	r := New([]string{"cves/"}, 1, 150)
+
+	r.AddFinding(Finding{Host: "https://example.com", TemplateID: "CVE-2021-1234", TemplateInfo: map[string]string{"name": "Example RCE"}, Severity: "critical", CVEID: "CVE-2021-1234", Matched: "https://example.com/vuln"})
+	r.AddFinding(Finding{Host: "https://example.com", TemplateID: "CVE-2021-1234", TemplateInfo: map[string]string{"name": "Example RCE"}, Severity: "critical", CVEID: "CVE-2021-1234", Matched: "https://example.com/vuln2"})
+	r.AddFinding(Finding{Host: "https://example.com", TemplateID: "exposed-panel", Severity: "low", Matched: "https://example.com/admin"})
+	r.AddFinding(Finding{Host: "https://other.com", TemplateID: "exposed-panel", Severity: "low", Matched: "https://other.com/admin"})
+
+	if len(r.Hosts) != 2 {
+		t.Fatalf("expected 2 hosts, got %d", len(r.Hosts))
+	}
+
+	example, ok := r.Hosts["https://example.com"]
+	if !ok {
+		t.Fatalf("expected example.com host summary")
+	}
+	if example.SeverityCounts["critical"] != 2 {
+		t.Fatalf("expected 2 critical findings, got %d", example.SeverityCounts["critical"])
+	}
+	if example.SeverityCounts["low"] != 1 {
+		t.Fatalf("expected 1 low finding, got %d", example.SeverityCounts["low"])
+	}
+	if len(example.CVEIDs) != 1 || example.CVEIDs[0] != "CVE-2021-1234" {
+		t.Fatalf("expected deduplicated cve ids, got %v", example.CVEIDs)
+	}
+	if len(example.Templates) != 2 {
+		t.Fatalf("expected 2 distinct templates, got %d", len(example.Templates))
+	}
+	if len(example.Templates[0].Matched) != 2 {
+		t.Fatalf("expected matched entries to accumulate on the same template, got %v", example.Templates[0].Matched)
+	}
+
+	r.Finish()
+	if r.FinishedAt.IsZero() {
+		t.Fatalf("expected FinishedAt to be set after Finish")
+	}
+}
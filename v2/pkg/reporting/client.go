@@ -0,0 +1,311 @@
+package reporting
+
+import (
+	stderrors "errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/es"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/kafka"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/nats"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/splunk"
+	exporterwebhook "github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/webhook"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/discord"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/slack"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/smtp"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/teams"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/webhook"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/bitbucket"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/forgejo"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/gitea"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/github"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/gitlab"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/jira"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultConfigFilename is the name of the reporting config file seeded by
+// CreateConfigIfNotExists in the user's nuclei config directory.
+const DefaultConfigFilename = ".nuclei-reporting-config.yaml"
+
+// Client is a reporting client for nuclei that can notify multiple
+// configured issue trackers and chat/webhook sinks of findings.
+type Client struct {
+	options   *Options
+	trackers  []trackers.Tracker
+	notifiers []notifiers.Notifier
+	exporters []Exporter
+}
+
+// New creates a new reporting client based on options, instantiating an
+// integration for every issue tracker that has been configured.
+func New(options *Options) (*Client, error) {
+	client := &Client{options: options}
+
+	if options.GitHub != nil {
+		tracker, err := github.New(options.GitHub)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create github tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+	if options.GitLab != nil {
+		tracker, err := gitlab.New(options.GitLab)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create gitlab tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+	if options.Jira != nil {
+		tracker, err := jira.New(options.Jira)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create jira tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+	if options.Gitea != nil {
+		tracker, err := gitea.New(options.Gitea)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create gitea tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+	if options.Forgejo != nil {
+		tracker, err := forgejo.New(options.Forgejo)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create forgejo tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+	if options.Bitbucket != nil {
+		tracker, err := bitbucket.New(options.Bitbucket)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create bitbucket tracker")
+		}
+		client.trackers = append(client.trackers, tracker)
+	}
+
+	if options.Slack != nil {
+		notifier, err := slack.New(options.Slack)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create slack notifier")
+		}
+		client.notifiers = append(client.notifiers, gateBySeverity(notifier, options.MinNotifySeverity))
+	}
+	if options.Discord != nil {
+		notifier, err := discord.New(options.Discord)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create discord notifier")
+		}
+		client.notifiers = append(client.notifiers, gateBySeverity(notifier, options.MinNotifySeverity))
+	}
+	if options.Teams != nil {
+		notifier, err := teams.New(options.Teams)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create teams notifier")
+		}
+		client.notifiers = append(client.notifiers, gateBySeverity(notifier, options.MinNotifySeverity))
+	}
+	if options.SMTP != nil {
+		notifier, err := smtp.New(options.SMTP)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create smtp notifier")
+		}
+		client.notifiers = append(client.notifiers, notifier)
+	}
+	if options.Webhook != nil {
+		notifier, err := webhook.New(options.Webhook)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create webhook notifier")
+		}
+		client.notifiers = append(client.notifiers, notifier)
+	}
+
+	if options.KafkaExporter != nil {
+		exporter, err := kafka.New(options.KafkaExporter)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create kafka exporter")
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.NatsExporter != nil {
+		exporter, err := nats.New(options.NatsExporter)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create nats exporter")
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.ElasticsearchExporter != nil {
+		exporter, err := es.New(options.ElasticsearchExporter)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create elasticsearch exporter")
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.SplunkExporter != nil {
+		exporter, err := splunk.New(options.SplunkExporter)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create splunk hec exporter")
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	if options.WebhookExporter != nil {
+		exporter, err := exporterwebhook.New(options.WebhookExporter)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create webhook exporter")
+		}
+		client.exporters = append(client.exporters, exporter)
+	}
+	return client, nil
+}
+
+// severityRank orders severities from lowest to highest, matching the
+// scale (info < low < medium < high < critical) used across nuclei.
+var severityRank = map[string]int{
+	"info":     0,
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// severityGatedNotifier wraps a notifiers.Notifier so it's only notified of
+// findings at or above minSeverity, letting high-volume chat sinks
+// (Slack/Discord/Teams) stay limited to the findings worth paging on
+// without needing a dedicated AllowList entry per severity.
+type severityGatedNotifier struct {
+	notifiers.Notifier
+	minSeverity string
+}
+
+// Notify implements notifiers.Notifier.
+func (n *severityGatedNotifier) Notify(event *output.ResultEvent) error {
+	if severityRank[event.Info.SeverityHolder.Severity.String()] < severityRank[n.minSeverity] {
+		return nil
+	}
+	return n.Notifier.Notify(event)
+}
+
+// gateBySeverity wraps notifier in a severityGatedNotifier when minSeverity
+// is set, otherwise it's returned unchanged.
+func gateBySeverity(notifier notifiers.Notifier, minSeverity string) notifiers.Notifier {
+	if minSeverity == "" {
+		return notifier
+	}
+	return &severityGatedNotifier{Notifier: notifier, minSeverity: minSeverity}
+}
+
+// CreateIssue reports event to every configured issue tracker and notifies
+// every configured chat/webhook sink, dispatching to every sink even if an
+// earlier one fails. The returned error is a multierr whose leaves are all
+// *ReportingError, so callers can errors.As(err, &reporting.ReportingError{})
+// to tell which sink failed, for which finding, and whether it's worth
+// retrying.
+func (c *Client) CreateIssue(event *output.ResultEvent) error {
+	if !c.options.AllowList.GetMatch(event) || (c.options.DenyList != nil && c.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	var reportErr error
+	for _, tracker := range c.trackers {
+		reportErr = multierr.Append(reportErr, c.dispatch(tracker.Name(), event, tracker.CreateIssue))
+	}
+	for _, notifier := range c.notifiers {
+		reportErr = multierr.Append(reportErr, c.dispatch(notifier.Name(), event, notifier.Notify))
+	}
+	for _, exporter := range c.exporters {
+		reportErr = multierr.Append(reportErr, c.dispatch(exporter.Name(), event, exporter.Export))
+	}
+	return reportErr
+}
+
+// dispatch runs report against event, retrying Retryable failures per the
+// configured RetryPolicy before giving up and wrapping the final error in a
+// *ReportingError.
+func (c *Client) dispatch(sinkName string, event *output.ResultEvent, report func(*output.ResultEvent) error) error {
+	for attempt := 1; ; attempt++ {
+		err := report(event)
+		if err == nil {
+			return nil
+		}
+
+		reportErr := &ReportingError{
+			TrackerName:     sinkName,
+			EventTemplateID: event.TemplateID,
+			Retryable:       isRetryable(err),
+			Err:             err,
+		}
+		if !reportErr.Retryable || c.options.RetryPolicy == nil {
+			return reportErr
+		}
+		retry, wait := c.options.RetryPolicy.ShouldRetry(reportErr, attempt)
+		if !retry {
+			return reportErr
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+}
+
+// isRetryable reports whether err is a known transient failure (e.g. a
+// tracker's rate-limit sentinel) that's worth retrying.
+func isRetryable(err error) bool {
+	return stderrors.Is(err, gitea.ErrRateLimited) ||
+		stderrors.Is(err, bitbucket.ErrRateLimited) ||
+		stderrors.Is(err, forgejo.ErrRateLimited)
+}
+
+// Close releases any resources held by the configured notifiers and flushes
+// every configured exporter.
+func (c *Client) Close() error {
+	var firstErr error
+	for _, notifier := range c.notifiers {
+		if err := notifier.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, exporter := range c.exporters {
+		if err := exporter.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// CreateConfigIfNotExists writes a default, commented-out reporting config
+// file to configDir if one doesn't already exist there, seeding every
+// supported issue tracker so users can uncomment and fill in the ones they
+// need.
+func CreateConfigIfNotExists(configDir string) error {
+	configFile := filepath.Join(configDir, DefaultConfigFilename)
+	if _, err := os.Stat(configFile); err == nil {
+		return nil
+	}
+
+	options := &Options{
+		GitHub:    &github.Options{},
+		GitLab:    &gitlab.Options{},
+		Jira:      &jira.Options{},
+		Gitea:     &gitea.Options{},
+		Forgejo:   &forgejo.Options{},
+		Bitbucket: &bitbucket.Options{},
+		Slack:     &slack.Options{},
+		Discord:   &discord.Options{},
+		Teams:     &teams.Options{},
+		SMTP:      &smtp.Options{},
+		Webhook:   &webhook.Options{},
+	}
+	data, err := yaml.Marshal(options)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal default reporting config")
+	}
+	return ioutil.WriteFile(configFile, data, 0644)
+}
@@ -0,0 +1,60 @@
+package reporting
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportingError wraps a failure from a single tracker or notifier so
+// callers can use errors.As to recover which sink failed, for which
+// finding, and whether the underlying cause is worth retrying - instead of
+// string-matching an opaque multierr blob.
+type ReportingError struct {
+	// TrackerName is the Name() of the tracker/notifier that failed.
+	TrackerName string
+	// EventTemplateID is the TemplateID of the finding that failed to report.
+	EventTemplateID string
+	// Retryable indicates whether retrying the same operation later may succeed.
+	Retryable bool
+
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ReportingError) Error() string {
+	return fmt.Sprintf("%s: could not report %s: %s", e.TrackerName, e.EventTemplateID, e.Err)
+}
+
+// Unwrap returns the underlying cause, so errors.Is/errors.As see through
+// ReportingError to the tracker-specific sentinel error it wraps.
+func (e *ReportingError) Unwrap() error {
+	return e.Err
+}
+
+// RetryPolicy is consulted by Client.CreateIssue for every ReportingError
+// with Retryable set, before the error is given up on and added to the
+// returned multierr.
+type RetryPolicy interface {
+	// ShouldRetry reports whether reportErr should be retried, and if so,
+	// how long to wait before the next try. attempt is the 1-indexed number
+	// of the try that just failed.
+	ShouldRetry(reportErr *ReportingError, attempt int) (retry bool, wait time.Duration)
+}
+
+// MaxAttemptsRetryPolicy is a RetryPolicy that retries a fixed number of
+// times with a constant delay between attempts.
+type MaxAttemptsRetryPolicy struct {
+	// MaxAttempts is the maximum number of retry attempts made for a
+	// single failure.
+	MaxAttempts int
+	// Delay is the wait time between retry attempts.
+	Delay time.Duration
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p *MaxAttemptsRetryPolicy) ShouldRetry(_ *ReportingError, attempt int) (bool, time.Duration) {
+	if attempt > p.MaxAttempts {
+		return false, 0
+	}
+	return true, p.Delay
+}
@@ -0,0 +1,17 @@
+package reporting
+
+import "github.com/projectdiscovery/nuclei/v2/pkg/output"
+
+// Exporter is implemented by a streaming/batch sink (Kafka, NATS,
+// Elasticsearch, Splunk HEC, a generic webhook, ...) that nuclei's
+// reporting module forwards every finding to, as opposed to
+// trackers.Tracker (one ticket per finding) or notifiers.Notifier
+// (near-real-time chat alerts).
+type Exporter interface {
+	// Export queues event for delivery to the sink.
+	Export(event *output.ResultEvent) error
+	// Close flushes any pending findings and releases the sink's resources.
+	Close() error
+	// Name returns the exporter's name, used to identify it in a ReportingError.
+	Name() string
+}
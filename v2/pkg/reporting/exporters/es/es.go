@@ -0,0 +1,277 @@
+// Package es implements a streaming exporter that bulk-indexes nuclei
+// findings into Elasticsearch, mirroring the kafka/nats exporters' bounded
+// ring buffer and batching model but pushing over Elasticsearch's _bulk
+// NDJSON API instead of a message broker. The target index is rotated
+// daily (<IndexName>-YYYY.MM.DD) so retention/ILM policies can be applied
+// per day without nuclei needing to manage index lifecycle itself.
+package es
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	defaultRingBufferSize = 4096
+	defaultBatchSize      = 100
+	defaultLingerDuration = 2 * time.Second
+	defaultIndexName      = "nuclei"
+)
+
+// ErrPartialBatchFailure is returned by Close when one or more buffered
+// batches failed to flush before the deadline.
+var ErrPartialBatchFailure = errors.New("es: one or more batches failed to flush")
+
+// Options contains the configuration options for the Elasticsearch exporter.
+type Options struct {
+	// URL is the Elasticsearch endpoint, e.g. https://localhost:9200.
+	URL string `yaml:"url"`
+	// IndexName is the base index name findings are bulk-indexed under,
+	// rotated daily as "<IndexName>-YYYY.MM.DD". Defaults to "nuclei".
+	IndexName string `yaml:"index-name"`
+	// Username, if set, is sent as HTTP basic auth alongside Password.
+	Username string `yaml:"username"`
+	// Password is the HTTP basic auth password.
+	Password string `yaml:"password"`
+	// APIKey, if set, is sent as an "ApiKey" Authorization header instead
+	// of basic auth.
+	APIKey string `yaml:"api-key"`
+	// SkipTLSVerify disables TLS certificate verification, for self-signed
+	// Elasticsearch deployments.
+	SkipTLSVerify bool `yaml:"skip-tls-verify"`
+	// RingBufferSize bounds how many buffered findings may be queued for
+	// indexing before Export starts blocking the caller. Defaults to 4096.
+	RingBufferSize int `yaml:"ring-buffer-size"`
+	// BatchSize is the number of findings accumulated before a bulk request
+	// is sent early. Defaults to 100.
+	BatchSize int `yaml:"batch-size"`
+	// LingerDuration is the maximum time a partial batch waits for more
+	// findings before being flushed anyway. Defaults to 2s.
+	LingerDuration time.Duration `yaml:"linger-duration"`
+	// AllowList contains a list of allowed events for this exporter.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this exporter.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Exporter is a streaming Elasticsearch bulk-indexing exporter for nuclei findings.
+type Exporter struct {
+	options *Options
+
+	buffer chan *output.ResultEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu          sync.Mutex
+	batch       []*output.ResultEvent
+	flushErrors []error
+}
+
+// New creates a new Elasticsearch exporter and starts its background batching loop.
+func New(options *Options) (*Exporter, error) {
+	if options.URL == "" {
+		return nil, errors.New("url is required for the elasticsearch exporter")
+	}
+	if options.IndexName == "" {
+		options.IndexName = defaultIndexName
+	}
+	if options.RingBufferSize == 0 {
+		options.RingBufferSize = defaultRingBufferSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	if options.LingerDuration == 0 {
+		options.LingerDuration = defaultLingerDuration
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	if options.SkipTLSVerify {
+		if transport, ok := options.HTTPClient.HTTPClient.Transport.(*http.Transport); ok {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+	}
+
+	exporter := &Exporter{
+		options: options,
+		buffer:  make(chan *output.ResultEvent, options.RingBufferSize),
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.batchLoop()
+	return exporter, nil
+}
+
+// Export queues event for bulk-indexing, applying the configured allow/deny
+// lists first. It blocks only if the ring buffer is full.
+func (e *Exporter) Export(event *output.ResultEvent) error {
+	if !e.options.AllowList.GetMatch(event) || (e.options.DenyList != nil && e.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+	select {
+	case e.buffer <- event:
+		return nil
+	case <-e.done:
+		return errors.New("es: exporter is closed")
+	}
+}
+
+// batchLoop accumulates findings off the buffer and flushes the current
+// batch either when BatchSize is reached or LingerDuration elapses.
+func (e *Exporter) batchLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.options.LingerDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			flush := len(e.batch) >= e.options.BatchSize
+			e.mu.Unlock()
+			if flush {
+				e.flush()
+			}
+		case <-ticker.C:
+			e.flush()
+		case <-e.done:
+			e.drain()
+			e.flush()
+			return
+		}
+	}
+}
+
+// drain moves any findings left in the buffer into the pending batch after
+// Close signals done.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			e.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+// flush builds a _bulk NDJSON request body (one action line, one source
+// line per finding) and POSTs it to the day-rotated index.
+func (e *Exporter) flush() {
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	index := fmt.Sprintf("%s-%s", e.options.IndexName, time.Now().Format("2006.01.02"))
+
+	var body bytes.Buffer
+	for _, event := range batch {
+		action := map[string]interface{}{"index": map[string]string{"_index": index}}
+		if err := json.NewEncoder(&body).Encode(action); err != nil {
+			e.recordFailure(err)
+			return
+		}
+		if err := json.NewEncoder(&body).Encode(event); err != nil {
+			e.recordFailure(err)
+			return
+		}
+	}
+
+	if err := e.bulkIndex(&body); err != nil {
+		e.recordFailure(err)
+	}
+}
+
+func (e *Exporter) bulkIndex(body *bytes.Buffer) error {
+	url := strings.TrimSuffix(e.options.URL, "/") + "/_bulk"
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if e.options.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+e.options.APIKey)
+	} else if e.options.Username != "" {
+		req.SetBasicAuth(e.options.Username, e.options.Password)
+	}
+
+	resp, err := e.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not perform bulk index request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not bulk index: got status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (e *Exporter) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushErrors = append(e.flushErrors, err)
+}
+
+// Close flushes any pending findings with a deadline and shuts down the
+// exporter, returning ErrPartialBatchFailure (wrapping the individual
+// causes) if one or more batches failed to index.
+func (e *Exporter) Close() error {
+	close(e.done)
+	e.wg.Wait()
+	close(e.buffer)
+
+	e.mu.Lock()
+	failures := e.flushErrors
+	e.mu.Unlock()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var detail bytes.Buffer
+	for i, err := range failures {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(err.Error())
+	}
+	return errors.Wrapf(ErrPartialBatchFailure, "%d batch(es) failed: %s", len(failures), detail.String())
+}
+
+// Name returns the name of the exporter.
+func (e *Exporter) Name() string {
+	return "elasticsearch"
+}
@@ -0,0 +1,259 @@
+// Package kafka implements a streaming exporter that publishes nuclei
+// findings onto a Kafka topic, so a firehose of results can feed a SIEM/ETL
+// pipeline directly instead of being polled from a file. Unlike the
+// HTTP-push exporters (splunk, es), publishing is batched client-side over
+// a bounded ring buffer rather than one request per finding.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// KeyStrategy derives the partition key for a published message, so
+// consumers can rely on related findings landing on the same partition.
+type KeyStrategy string
+
+const (
+	// KeyByHost derives the message key from the finding's host.
+	KeyByHost KeyStrategy = "host"
+	// KeyByTemplate derives the message key from the matched template ID.
+	KeyByTemplate KeyStrategy = "template"
+	// KeyBySeverity derives the message key from the finding's severity.
+	KeyBySeverity KeyStrategy = "severity"
+)
+
+const (
+	defaultRingBufferSize = 4096
+	defaultBatchSize      = 100
+	defaultLingerDuration = 2 * time.Second
+	defaultFlushTimeout   = 10 * time.Second
+)
+
+// ErrPartialBatchFailure is returned by Close when one or more buffered
+// batches failed to flush before the deadline.
+var ErrPartialBatchFailure = errors.New("kafka: one or more batches failed to flush")
+
+// Options contains the configuration options for the Kafka exporter.
+type Options struct {
+	// Brokers is the list of Kafka broker addresses (host:port).
+	Brokers []string `yaml:"brokers"`
+	// Topic is the Kafka topic findings are published to.
+	Topic string `yaml:"topic"`
+	// RingBufferSize bounds how many buffered findings may be queued for
+	// publish before Export starts blocking the caller. Defaults to 4096.
+	RingBufferSize int `yaml:"ring-buffer-size"`
+	// BatchSize is the number of findings accumulated before a batch is
+	// flushed early. Defaults to 100.
+	BatchSize int `yaml:"batch-size"`
+	// LingerDuration is the maximum time a partial batch waits for more
+	// findings before being flushed anyway. Defaults to 2s.
+	LingerDuration time.Duration `yaml:"linger-duration"`
+	// Async, if true, publishes batches without waiting for broker
+	// acknowledgement. Defaults to false (synchronous, acked writes).
+	Async bool `yaml:"async"`
+	// Compression is the on-wire compression codec: "none", "gzip", "snappy",
+	// "lz4" or "zstd". Defaults to "none".
+	Compression string `yaml:"compression"`
+	// KeyStrategy selects how the partition key is derived from a finding.
+	// Defaults to KeyByHost.
+	KeyStrategy KeyStrategy `yaml:"key-strategy"`
+	// AllowList contains a list of allowed events for this exporter.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this exporter.
+	DenyList *filters.Filter `yaml:"deny-list"`
+}
+
+// Exporter is a streaming Kafka exporter for nuclei findings.
+type Exporter struct {
+	options *Options
+	writer  *kafkago.Writer
+
+	buffer chan *output.ResultEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu           sync.Mutex
+	flushErrors  []error
+	pendingCount int
+}
+
+// New creates a new Kafka exporter and starts its background batching loop.
+func New(options *Options) (*Exporter, error) {
+	if len(options.Brokers) == 0 || options.Topic == "" {
+		return nil, errors.New("brokers and topic are required for the kafka exporter")
+	}
+	if options.RingBufferSize == 0 {
+		options.RingBufferSize = defaultRingBufferSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	if options.LingerDuration == 0 {
+		options.LingerDuration = defaultLingerDuration
+	}
+	if options.KeyStrategy == "" {
+		options.KeyStrategy = KeyByHost
+	}
+
+	writer := &kafkago.Writer{
+		Addr:         kafkago.TCP(options.Brokers...),
+		Topic:        options.Topic,
+		Balancer:     &kafkago.Hash{},
+		Async:        options.Async,
+		Compression:  compressionCodec(options.Compression),
+		BatchSize:    options.BatchSize,
+		BatchTimeout: options.LingerDuration,
+	}
+
+	exporter := &Exporter{
+		options: options,
+		writer:  writer,
+		buffer:  make(chan *output.ResultEvent, options.RingBufferSize),
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.batchLoop()
+	return exporter, nil
+}
+
+// Export queues event for publishing, applying the configured allow/deny
+// lists first. It blocks only if the ring buffer is full.
+func (e *Exporter) Export(event *output.ResultEvent) error {
+	if !e.options.AllowList.GetMatch(event) || (e.options.DenyList != nil && e.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+	select {
+	case e.buffer <- event:
+		return nil
+	case <-e.done:
+		return errors.New("kafka: exporter is closed")
+	}
+}
+
+// batchLoop accumulates findings off the buffer and flushes them either
+// when BatchSize is reached or LingerDuration elapses, whichever first -
+// kafkago.Writer already does this internally via BatchSize/BatchTimeout,
+// so batchLoop's job is simply to keep draining the ring buffer into it
+// and track in-flight/failed writes for Close.
+func (e *Exporter) batchLoop() {
+	defer e.wg.Done()
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.publish(event)
+		case <-e.done:
+			e.drain()
+			return
+		}
+	}
+}
+
+// drain flushes any findings left in the buffer after Close signals done.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.publish(event)
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) publish(event *output.ResultEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		e.recordFailure(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultFlushTimeout)
+	defer cancel()
+
+	msg := kafkago.Message{Key: []byte(messageKey(e.options.KeyStrategy, event)), Value: data}
+	if err := e.writer.WriteMessages(ctx, msg); err != nil {
+		e.recordFailure(err)
+	}
+}
+
+func (e *Exporter) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushErrors = append(e.flushErrors, err)
+}
+
+// Close flushes any pending findings with a deadline and shuts down the
+// underlying writer, returning ErrPartialBatchFailure (wrapping the
+// individual causes) if one or more batches failed to publish.
+func (e *Exporter) Close() error {
+	close(e.done)
+	e.wg.Wait()
+	close(e.buffer)
+
+	writerErr := e.writer.Close()
+
+	e.mu.Lock()
+	failures := e.flushErrors
+	e.mu.Unlock()
+
+	if len(failures) == 0 {
+		return writerErr
+	}
+
+	var detail bytes.Buffer
+	for i, err := range failures {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(err.Error())
+	}
+	return errors.Wrapf(ErrPartialBatchFailure, "%d batch(es) failed: %s", len(failures), detail.String())
+}
+
+func messageKey(strategy KeyStrategy, event *output.ResultEvent) string {
+	switch strategy {
+	case KeyByTemplate:
+		return event.TemplateID
+	case KeyBySeverity:
+		return event.Info.SeverityHolder.Severity.String()
+	case KeyByHost:
+		fallthrough
+	default:
+		return event.Host
+	}
+}
+
+func compressionCodec(name string) kafkago.Compression {
+	switch name {
+	case "gzip":
+		return kafkago.Gzip
+	case "snappy":
+		return kafkago.Snappy
+	case "lz4":
+		return kafkago.Lz4
+	case "zstd":
+		return kafkago.Zstd
+	default:
+		return 0
+	}
+}
+
+// Name returns the name of the exporter.
+func (e *Exporter) Name() string {
+	return "kafka"
+}
@@ -0,0 +1,19 @@
+package nats
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// gzipBytes compresses data using gzip at the default compression level.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
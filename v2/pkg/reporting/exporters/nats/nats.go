@@ -0,0 +1,287 @@
+// Package nats implements a streaming exporter that publishes nuclei
+// findings onto a NATS subject, mirroring the kafka exporter's bounded
+// ring buffer and batching model. Plain NATS has no native notion of a
+// "batch" the way Kafka's producer does, so batching here is purely
+// client-side: findings are accumulated and published as a single
+// newline-delimited-JSON message per batch.
+package nats
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+)
+
+// KeyStrategy derives the subject suffix a finding is published under, so
+// consumers can subscribe to a narrower slice of the firehose.
+type KeyStrategy string
+
+const (
+	// KeyByHost derives the subject suffix from the finding's host.
+	KeyByHost KeyStrategy = "host"
+	// KeyByTemplate derives the subject suffix from the matched template ID.
+	KeyByTemplate KeyStrategy = "template"
+	// KeyBySeverity derives the subject suffix from the finding's severity.
+	KeyBySeverity KeyStrategy = "severity"
+	// KeyNone publishes every finding to the bare configured subject.
+	KeyNone KeyStrategy = "none"
+)
+
+const (
+	defaultRingBufferSize = 4096
+	defaultBatchSize      = 100
+	defaultLingerDuration = 2 * time.Second
+	defaultFlushTimeout   = 10 * time.Second
+)
+
+// ErrPartialBatchFailure is returned by Close when one or more buffered
+// batches failed to publish before the deadline.
+var ErrPartialBatchFailure = errors.New("nats: one or more batches failed to flush")
+
+// Options contains the configuration options for the NATS exporter.
+type Options struct {
+	// URL is the NATS server URL, e.g. nats://localhost:4222.
+	URL string `yaml:"url"`
+	// Subject is the base NATS subject findings are published to.
+	Subject string `yaml:"subject"`
+	// RingBufferSize bounds how many buffered findings may be queued for
+	// publish before Export starts blocking the caller. Defaults to 4096.
+	RingBufferSize int `yaml:"ring-buffer-size"`
+	// BatchSize is the number of findings accumulated before a batch is
+	// flushed early. Defaults to 100.
+	BatchSize int `yaml:"batch-size"`
+	// LingerDuration is the maximum time a partial batch waits for more
+	// findings before being flushed anyway. Defaults to 2s.
+	LingerDuration time.Duration `yaml:"linger-duration"`
+	// Async, if true, publishes without waiting for the server to flush
+	// the underlying connection. Defaults to false.
+	Async bool `yaml:"async"`
+	// Compression enables gzip compression of the batch payload when true.
+	Compression bool `yaml:"compression"`
+	// KeyStrategy selects how the subject suffix is derived from a finding.
+	// Defaults to KeyByHost.
+	KeyStrategy KeyStrategy `yaml:"key-strategy"`
+	// AllowList contains a list of allowed events for this exporter.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this exporter.
+	DenyList *filters.Filter `yaml:"deny-list"`
+}
+
+// Exporter is a streaming NATS exporter for nuclei findings.
+type Exporter struct {
+	options *Options
+	conn    *nats.Conn
+
+	buffer chan *output.ResultEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu          sync.Mutex
+	batch       []*output.ResultEvent
+	flushErrors []error
+}
+
+// New creates a new NATS exporter, connects to the configured server, and
+// starts its background batching loop.
+func New(options *Options) (*Exporter, error) {
+	if options.URL == "" || options.Subject == "" {
+		return nil, errors.New("url and subject are required for the nats exporter")
+	}
+	if options.RingBufferSize == 0 {
+		options.RingBufferSize = defaultRingBufferSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	if options.LingerDuration == 0 {
+		options.LingerDuration = defaultLingerDuration
+	}
+	if options.KeyStrategy == "" {
+		options.KeyStrategy = KeyByHost
+	}
+
+	connOpts := []nats.Option{nats.Name("nuclei-reporting")}
+	if options.Async {
+		connOpts = append(connOpts, nats.DontRandomize())
+	}
+	conn, err := nats.Connect(options.URL, connOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to nats server")
+	}
+
+	exporter := &Exporter{
+		options: options,
+		conn:    conn,
+		buffer:  make(chan *output.ResultEvent, options.RingBufferSize),
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.batchLoop()
+	return exporter, nil
+}
+
+// Export queues event for publishing, applying the configured allow/deny
+// lists first. It blocks only if the ring buffer is full.
+func (e *Exporter) Export(event *output.ResultEvent) error {
+	if !e.options.AllowList.GetMatch(event) || (e.options.DenyList != nil && e.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+	select {
+	case e.buffer <- event:
+		return nil
+	case <-e.done:
+		return errors.New("nats: exporter is closed")
+	}
+}
+
+// batchLoop accumulates findings off the buffer and flushes the current
+// batch either when BatchSize is reached or LingerDuration elapses.
+func (e *Exporter) batchLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.options.LingerDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			flush := len(e.batch) >= e.options.BatchSize
+			e.mu.Unlock()
+			if flush {
+				e.flush()
+			}
+		case <-ticker.C:
+			e.flush()
+		case <-e.done:
+			e.drain()
+			e.flush()
+			return
+		}
+	}
+}
+
+// drain moves any findings left in the buffer into the pending batch after
+// Close signals done.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			e.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) flush() {
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var payload bytes.Buffer
+	encoder := json.NewEncoder(&payload)
+	for _, event := range batch {
+		if err := encoder.Encode(event); err != nil {
+			e.recordFailure(err)
+			return
+		}
+	}
+
+	data := payload.Bytes()
+	if e.options.Compression {
+		compressed, err := gzipBytes(data)
+		if err != nil {
+			e.recordFailure(err)
+			return
+		}
+		data = compressed
+	}
+
+	subject := e.options.Subject
+	if e.options.KeyStrategy != KeyNone {
+		subject = subject + "." + subjectSuffix(e.options.KeyStrategy, batch[0])
+	}
+
+	if err := e.conn.Publish(subject, data); err != nil {
+		e.recordFailure(err)
+		return
+	}
+	if !e.options.Async {
+		if err := e.conn.FlushTimeout(defaultFlushTimeout); err != nil {
+			e.recordFailure(err)
+		}
+	}
+}
+
+func (e *Exporter) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushErrors = append(e.flushErrors, err)
+}
+
+// Close flushes any pending findings with a deadline and closes the
+// underlying connection, returning ErrPartialBatchFailure (wrapping the
+// individual causes) if one or more batches failed to publish.
+func (e *Exporter) Close() error {
+	close(e.done)
+	e.wg.Wait()
+	close(e.buffer)
+
+	e.conn.Close()
+
+	e.mu.Lock()
+	failures := e.flushErrors
+	e.mu.Unlock()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var detail bytes.Buffer
+	for i, err := range failures {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(err.Error())
+	}
+	return errors.Wrapf(ErrPartialBatchFailure, "%d batch(es) failed: %s", len(failures), detail.String())
+}
+
+func subjectSuffix(strategy KeyStrategy, event *output.ResultEvent) string {
+	switch strategy {
+	case KeyByTemplate:
+		return event.TemplateID
+	case KeyBySeverity:
+		return event.Info.SeverityHolder.Severity.String()
+	case KeyByHost:
+		fallthrough
+	default:
+		return event.Host
+	}
+}
+
+// Name returns the name of the exporter.
+func (e *Exporter) Name() string {
+	return "nats"
+}
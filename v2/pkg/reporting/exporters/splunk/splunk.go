@@ -0,0 +1,274 @@
+// Package splunk implements a streaming exporter that pushes nuclei
+// findings to a Splunk HTTP Event Collector (HEC), mirroring the
+// kafka/nats/es exporters' bounded ring buffer and batching model. HEC
+// natively accepts a batch as several raw JSON event objects concatenated
+// back-to-back in one request body, so flush needs no extra framing beyond
+// that concatenation.
+package splunk
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	defaultRingBufferSize = 4096
+	defaultBatchSize      = 100
+	defaultLingerDuration = 2 * time.Second
+	defaultSourcetype     = "nuclei"
+)
+
+// ErrPartialBatchFailure is returned by Close when one or more buffered
+// batches failed to flush before the deadline.
+var ErrPartialBatchFailure = errors.New("splunk: one or more batches failed to flush")
+
+// hecEvent wraps a finding in the envelope Splunk HEC's /collector/event
+// endpoint expects.
+type hecEvent struct {
+	Event      *output.ResultEvent `json:"event"`
+	Sourcetype string              `json:"sourcetype,omitempty"`
+	Index      string              `json:"index,omitempty"`
+}
+
+// Options contains the configuration options for the Splunk HEC exporter.
+type Options struct {
+	// URL is the Splunk HEC endpoint, e.g. https://splunk.internal:8088.
+	URL string `yaml:"url"`
+	// Token is the HEC auth token, sent as "Authorization: Splunk <token>".
+	Token string `yaml:"token"`
+	// Sourcetype is the Splunk sourcetype assigned to each event. Defaults
+	// to "nuclei".
+	Sourcetype string `yaml:"sourcetype"`
+	// Index is the Splunk index events are routed to. Empty uses HEC's
+	// default index for the token.
+	Index string `yaml:"index"`
+	// SkipTLSVerify disables TLS certificate verification, for self-signed
+	// Splunk deployments.
+	SkipTLSVerify bool `yaml:"skip-tls-verify"`
+	// RingBufferSize bounds how many buffered findings may be queued for
+	// delivery before Export starts blocking the caller. Defaults to 4096.
+	RingBufferSize int `yaml:"ring-buffer-size"`
+	// BatchSize is the number of findings accumulated before a batch is
+	// flushed early. Defaults to 100.
+	BatchSize int `yaml:"batch-size"`
+	// LingerDuration is the maximum time a partial batch waits for more
+	// findings before being flushed anyway. Defaults to 2s.
+	LingerDuration time.Duration `yaml:"linger-duration"`
+	// AllowList contains a list of allowed events for this exporter.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this exporter.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Exporter is a streaming Splunk HEC exporter for nuclei findings.
+type Exporter struct {
+	options *Options
+
+	buffer chan *output.ResultEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu          sync.Mutex
+	batch       []*output.ResultEvent
+	flushErrors []error
+}
+
+// New creates a new Splunk HEC exporter and starts its background batching loop.
+func New(options *Options) (*Exporter, error) {
+	if options.URL == "" || options.Token == "" {
+		return nil, errors.New("url and token are required for the splunk hec exporter")
+	}
+	if options.Sourcetype == "" {
+		options.Sourcetype = defaultSourcetype
+	}
+	if options.RingBufferSize == 0 {
+		options.RingBufferSize = defaultRingBufferSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	if options.LingerDuration == 0 {
+		options.LingerDuration = defaultLingerDuration
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	if options.SkipTLSVerify {
+		if transport, ok := options.HTTPClient.HTTPClient.Transport.(*http.Transport); ok {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+	}
+
+	exporter := &Exporter{
+		options: options,
+		buffer:  make(chan *output.ResultEvent, options.RingBufferSize),
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.batchLoop()
+	return exporter, nil
+}
+
+// Export queues event for delivery, applying the configured allow/deny
+// lists first. It blocks only if the ring buffer is full.
+func (e *Exporter) Export(event *output.ResultEvent) error {
+	if !e.options.AllowList.GetMatch(event) || (e.options.DenyList != nil && e.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+	select {
+	case e.buffer <- event:
+		return nil
+	case <-e.done:
+		return errors.New("splunk: exporter is closed")
+	}
+}
+
+// batchLoop accumulates findings off the buffer and flushes the current
+// batch either when BatchSize is reached or LingerDuration elapses.
+func (e *Exporter) batchLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.options.LingerDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			flush := len(e.batch) >= e.options.BatchSize
+			e.mu.Unlock()
+			if flush {
+				e.flush()
+			}
+		case <-ticker.C:
+			e.flush()
+		case <-e.done:
+			e.drain()
+			e.flush()
+			return
+		}
+	}
+}
+
+// drain moves any findings left in the buffer into the pending batch after
+// Close signals done.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			e.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+// flush concatenates the batch as back-to-back HEC event envelopes and
+// POSTs them in a single request, per HEC's batching convention.
+func (e *Exporter) flush() {
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range batch {
+		envelope := hecEvent{Event: event, Sourcetype: e.options.Sourcetype, Index: e.options.Index}
+		if err := encoder.Encode(envelope); err != nil {
+			e.recordFailure(err)
+			return
+		}
+	}
+
+	if err := e.send(&body); err != nil {
+		e.recordFailure(err)
+	}
+}
+
+func (e *Exporter) send(body *bytes.Buffer) error {
+	url := strings.TrimSuffix(e.options.URL, "/") + "/services/collector/event"
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+e.options.Token)
+
+	resp, err := e.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post hec event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post hec event: got status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (e *Exporter) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushErrors = append(e.flushErrors, err)
+}
+
+// Close flushes any pending findings with a deadline and shuts down the
+// exporter, returning ErrPartialBatchFailure (wrapping the individual
+// causes) if one or more batches failed to deliver.
+func (e *Exporter) Close() error {
+	close(e.done)
+	e.wg.Wait()
+	close(e.buffer)
+
+	e.mu.Lock()
+	failures := e.flushErrors
+	e.mu.Unlock()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var detail bytes.Buffer
+	for i, err := range failures {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(err.Error())
+	}
+	return errors.Wrapf(ErrPartialBatchFailure, "%d batch(es) failed: %s", len(failures), detail.String())
+}
+
+// Name returns the name of the exporter.
+func (e *Exporter) Name() string {
+	return "splunkhec"
+}
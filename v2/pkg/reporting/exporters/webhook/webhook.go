@@ -0,0 +1,246 @@
+// Package webhook implements a streaming exporter that POSTs batches of
+// nuclei findings as newline-delimited JSON to a generic HTTP endpoint,
+// mirroring the kafka/nats/es/splunk exporters' bounded ring buffer and
+// batching model. Unlike notifiers/webhook (a single HMAC-signed POST per
+// finding, meant for low-latency alerting), this exporter is meant to feed
+// a SIEM/ETL pipeline a firehose of batched results, retrying transient
+// failures with backoff via retryablehttp.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	defaultRingBufferSize = 4096
+	defaultBatchSize      = 100
+	defaultLingerDuration = 2 * time.Second
+)
+
+// ErrPartialBatchFailure is returned by Close when one or more buffered
+// batches failed to flush before the deadline.
+var ErrPartialBatchFailure = errors.New("webhook: one or more batches failed to flush")
+
+// Options contains the configuration options for the generic NDJSON webhook exporter.
+type Options struct {
+	// URL is the endpoint each batch is POSTed to.
+	URL string `yaml:"url"`
+	// Headers contains additional static headers to send with each request.
+	Headers map[string]string `yaml:"headers"`
+	// RingBufferSize bounds how many buffered findings may be queued for
+	// delivery before Export starts blocking the caller. Defaults to 4096.
+	RingBufferSize int `yaml:"ring-buffer-size"`
+	// BatchSize is the number of findings accumulated before a batch is
+	// flushed early. Defaults to 100.
+	BatchSize int `yaml:"batch-size"`
+	// LingerDuration is the maximum time a partial batch waits for more
+	// findings before being flushed anyway. Defaults to 2s.
+	LingerDuration time.Duration `yaml:"linger-duration"`
+	// AllowList contains a list of allowed events for this exporter.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this exporter.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	// HTTPClient's retry/backoff policy governs delivery retries. Defaults
+	// to retryablehttp.DefaultOptionsSingle.
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Exporter is a streaming, batched NDJSON webhook exporter for nuclei findings.
+type Exporter struct {
+	options *Options
+
+	buffer chan *output.ResultEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	mu          sync.Mutex
+	batch       []*output.ResultEvent
+	flushErrors []error
+}
+
+// New creates a new webhook exporter and starts its background batching loop.
+func New(options *Options) (*Exporter, error) {
+	if options.URL == "" {
+		return nil, errors.New("url is required for the webhook exporter")
+	}
+	if options.RingBufferSize == 0 {
+		options.RingBufferSize = defaultRingBufferSize
+	}
+	if options.BatchSize == 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	if options.LingerDuration == 0 {
+		options.LingerDuration = defaultLingerDuration
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+
+	exporter := &Exporter{
+		options: options,
+		buffer:  make(chan *output.ResultEvent, options.RingBufferSize),
+		done:    make(chan struct{}),
+	}
+	exporter.wg.Add(1)
+	go exporter.batchLoop()
+	return exporter, nil
+}
+
+// Export queues event for delivery, applying the configured allow/deny
+// lists first. It blocks only if the ring buffer is full.
+func (e *Exporter) Export(event *output.ResultEvent) error {
+	if !e.options.AllowList.GetMatch(event) || (e.options.DenyList != nil && e.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+	select {
+	case e.buffer <- event:
+		return nil
+	case <-e.done:
+		return errors.New("webhook: exporter is closed")
+	}
+}
+
+// batchLoop accumulates findings off the buffer and flushes the current
+// batch either when BatchSize is reached or LingerDuration elapses.
+func (e *Exporter) batchLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.options.LingerDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			flush := len(e.batch) >= e.options.BatchSize
+			e.mu.Unlock()
+			if flush {
+				e.flush()
+			}
+		case <-ticker.C:
+			e.flush()
+		case <-e.done:
+			e.drain()
+			e.flush()
+			return
+		}
+	}
+}
+
+// drain moves any findings left in the buffer into the pending batch after
+// Close signals done.
+func (e *Exporter) drain() {
+	for {
+		select {
+		case event, ok := <-e.buffer:
+			if !ok {
+				return
+			}
+			e.mu.Lock()
+			e.batch = append(e.batch, event)
+			e.mu.Unlock()
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) flush() {
+	e.mu.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range batch {
+		if err := encoder.Encode(event); err != nil {
+			e.recordFailure(err)
+			return
+		}
+	}
+
+	if err := e.post(&body); err != nil {
+		e.recordFailure(err)
+	}
+}
+
+func (e *Exporter) post(body *bytes.Buffer) error {
+	req, err := retryablehttp.NewRequest(http.MethodPost, e.options.URL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for key, value := range e.options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post webhook batch")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post webhook batch: got status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (e *Exporter) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flushErrors = append(e.flushErrors, err)
+}
+
+// Close flushes any pending findings with a deadline and shuts down the
+// exporter, returning ErrPartialBatchFailure (wrapping the individual
+// causes) if one or more batches failed to deliver.
+func (e *Exporter) Close() error {
+	close(e.done)
+	e.wg.Wait()
+	close(e.buffer)
+
+	e.mu.Lock()
+	failures := e.flushErrors
+	e.mu.Unlock()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var detail bytes.Buffer
+	for i, err := range failures {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(err.Error())
+	}
+	return errors.Wrapf(ErrPartialBatchFailure, "%d batch(es) failed: %s", len(failures), detail.String())
+}
+
+// Name returns the name of the exporter.
+func (e *Exporter) Name() string {
+	return "webhook"
+}
@@ -0,0 +1,54 @@
+// Package filters provides allow/deny-list matching for nuclei reporting
+// exporters and issue trackers, so a given sink can be scoped to a subset
+// of findings (e.g. only "critical" and "high" severity results) independent
+// of the global reporting.Options allow/deny lists.
+package filters
+
+import (
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// Filter restricts which findings a reporting sink acts on. A nil Filter,
+// or one with no fields set, matches everything.
+type Filter struct {
+	// Severities restricts matches to the listed severities.
+	Severities []severity.Severity `yaml:"severities"`
+	// Tags restricts matches to findings carrying at least one of the listed tags.
+	Tags []string `yaml:"tags"`
+}
+
+// GetMatch returns true if event satisfies the filter.
+func (f *Filter) GetMatch(event *output.ResultEvent) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Severities) > 0 && !f.matchesSeverity(event) {
+		return false
+	}
+	if len(f.Tags) > 0 && !f.matchesTag(event) {
+		return false
+	}
+	return true
+}
+
+func (f *Filter) matchesSeverity(event *output.ResultEvent) bool {
+	for _, s := range f.Severities {
+		if s == event.Info.SeverityHolder.Severity {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchesTag(event *output.ResultEvent) bool {
+	eventTags := event.Info.Tags.ToSlice()
+	for _, tag := range f.Tags {
+		for _, eventTag := range eventTags {
+			if tag == eventTag {
+				return true
+			}
+		}
+	}
+	return false
+}
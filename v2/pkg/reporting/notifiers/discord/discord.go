@@ -0,0 +1,95 @@
+// Package discord implements a notifier that posts findings to a Discord
+// webhook.
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Options contains the configuration options for the Discord notifier.
+type Options struct {
+	// WebhookURL is the Discord webhook URL to post messages to.
+	WebhookURL string `yaml:"webhook-url"`
+	// Username optionally overrides the webhook's display name for this message.
+	Username string `yaml:"username"`
+	// AllowList contains a list of allowed events for this notifier.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this notifier.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Notifier is a notifier for Discord webhooks.
+type Notifier struct {
+	options *Options
+}
+
+// New creates a new Discord notifier.
+func New(options *Options) (*Notifier, error) {
+	if options.WebhookURL == "" {
+		return nil, errors.New("webhook-url is required for the discord notifier")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	return &Notifier{options: options}, nil
+}
+
+type message struct {
+	Username string `json:"username,omitempty"`
+	Content  string `json:"content"`
+}
+
+// Notify posts event to the configured Discord webhook.
+func (n *Notifier) Notify(event *output.ResultEvent) error {
+	if !n.options.AllowList.GetMatch(event) || (n.options.DenyList != nil && n.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	data, err := json.Marshal(message{Username: n.options.Username, Content: messageContent(event)})
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, n.options.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post discord message")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post discord message: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Close is a no-op for the stateless Discord notifier.
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Name returns the name of the notifier.
+func (n *Notifier) Name() string {
+	return "discord"
+}
+
+func messageContent(event *output.ResultEvent) string {
+	return fmt.Sprintf("**%s** matched on `%s` (%s)\n%s", event.Info.Name, event.Host, event.Info.SeverityHolder.Severity, event.Info.Description)
+}
@@ -0,0 +1,18 @@
+// Package notifiers holds the common interface implemented by every
+// chat/webhook sink (Slack, Discord, Teams, SMTP, generic webhook, ...)
+// that nuclei's reporting module can push findings to, as a lighter-weight
+// alternative to filing a ticket via trackers.Tracker.
+package notifiers
+
+import "github.com/projectdiscovery/nuclei/v2/pkg/output"
+
+// Notifier is implemented by a chat/webhook sink that can be notified of
+// nuclei findings in near-real-time.
+type Notifier interface {
+	// Notify delivers event to the sink.
+	Notify(event *output.ResultEvent) error
+	// Close releases any resources (e.g. SMTP connections) held by the notifier.
+	Close() error
+	// Name returns the notifier's name, used to identify it in a ReportingError.
+	Name() string
+}
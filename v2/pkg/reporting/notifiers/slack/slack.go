@@ -0,0 +1,95 @@
+// Package slack implements a notifier that posts findings to a Slack
+// incoming webhook.
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Options contains the configuration options for the Slack notifier.
+type Options struct {
+	// WebhookURL is the Slack incoming webhook URL to post messages to.
+	WebhookURL string `yaml:"webhook-url"`
+	// Channel optionally overrides the channel the webhook is bound to.
+	Channel string `yaml:"channel"`
+	// AllowList contains a list of allowed events for this notifier.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this notifier.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Notifier is a notifier for Slack incoming webhooks.
+type Notifier struct {
+	options *Options
+}
+
+// New creates a new Slack notifier.
+func New(options *Options) (*Notifier, error) {
+	if options.WebhookURL == "" {
+		return nil, errors.New("webhook-url is required for the slack notifier")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	return &Notifier{options: options}, nil
+}
+
+type message struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Notify posts event to the configured Slack webhook.
+func (n *Notifier) Notify(event *output.ResultEvent) error {
+	if !n.options.AllowList.GetMatch(event) || (n.options.DenyList != nil && n.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	data, err := json.Marshal(message{Channel: n.options.Channel, Text: messageText(event)})
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, n.options.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post slack message")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post slack message: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Close is a no-op for the stateless Slack notifier.
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Name returns the name of the notifier.
+func (n *Notifier) Name() string {
+	return "slack"
+}
+
+func messageText(event *output.ResultEvent) string {
+	return fmt.Sprintf("*%s* matched on `%s` (%s)\n%s", event.Info.Name, event.Host, event.Info.SeverityHolder.Severity, event.Info.Description)
+}
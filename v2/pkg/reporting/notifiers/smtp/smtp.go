@@ -0,0 +1,95 @@
+// Package smtp implements a notifier that emails findings through an SMTP
+// relay.
+package smtp
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+)
+
+// Options contains the configuration options for the SMTP notifier.
+type Options struct {
+	// Host is the SMTP server host.
+	Host string `yaml:"host"`
+	// Port is the SMTP server port.
+	Port int `yaml:"port"`
+	// Username is used for PLAIN authentication against the SMTP server, if set.
+	Username string `yaml:"username"`
+	// Password is used for PLAIN authentication against the SMTP server, if set.
+	Password string `yaml:"password"`
+	// From is the sender email address.
+	From string `yaml:"from"`
+	// To is the list of recipient email addresses.
+	To []string `yaml:"to"`
+	// AllowList contains a list of allowed events for this notifier.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this notifier.
+	DenyList *filters.Filter `yaml:"deny-list"`
+}
+
+// Notifier is a notifier that delivers findings via email.
+type Notifier struct {
+	options *Options
+	auth    smtp.Auth
+}
+
+// New creates a new SMTP notifier.
+func New(options *Options) (*Notifier, error) {
+	if options.Host == "" || options.From == "" || len(options.To) == 0 {
+		return nil, errors.New("host, from and to are required for the smtp notifier")
+	}
+	if options.Port == 0 {
+		options.Port = 587
+	}
+
+	notifier := &Notifier{options: options}
+	if options.Username != "" {
+		notifier.auth = smtp.PlainAuth("", options.Username, options.Password, options.Host)
+	}
+	return notifier, nil
+}
+
+// Notify emails event to the configured recipients.
+func (n *Notifier) Notify(event *output.ResultEvent) error {
+	if !n.options.AllowList.GetMatch(event) || (n.options.DenyList != nil && n.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.options.Host, n.options.Port)
+	message := buildMessage(n.options.From, n.options.To, event)
+
+	if err := smtp.SendMail(addr, n.auth, n.options.From, n.options.To, message); err != nil {
+		return errors.Wrap(err, "could not send smtp notification")
+	}
+	return nil
+}
+
+// Close is a no-op: the SMTP notifier dials a fresh connection per message.
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Name returns the name of the notifier.
+func (n *Notifier) Name() string {
+	return "smtp"
+}
+
+func buildMessage(from string, to []string, event *output.ResultEvent) []byte {
+	subject := fmt.Sprintf("[nuclei] %s matched on %s", event.Info.Name, event.Host)
+	body := fmt.Sprintf("Template: %s\nSeverity: %s\nHost: %s\nMatched at: %s\n\n%s",
+		event.TemplateID, event.Info.SeverityHolder.Severity, event.Host, event.Matched, event.Info.Description)
+
+	headers := []string{
+		fmt.Sprintf("From: %s", from),
+		fmt.Sprintf("To: %s", strings.Join(to, ", ")),
+		fmt.Sprintf("Subject: %s", subject),
+		"",
+		body,
+	}
+	return []byte(strings.Join(headers, "\r\n"))
+}
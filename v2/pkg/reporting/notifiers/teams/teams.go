@@ -0,0 +1,131 @@
+// Package teams implements a notifier that posts findings to a Microsoft
+// Teams incoming webhook as a MessageCard.
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Options contains the configuration options for the Teams notifier.
+type Options struct {
+	// WebhookURL is the Teams incoming webhook URL to post messages to.
+	WebhookURL string `yaml:"webhook-url"`
+	// AllowList contains a list of allowed events for this notifier.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this notifier.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Notifier is a notifier for Microsoft Teams webhooks.
+type Notifier struct {
+	options *Options
+}
+
+// New creates a new Teams notifier.
+func New(options *Options) (*Notifier, error) {
+	if options.WebhookURL == "" {
+		return nil, errors.New("webhook-url is required for the teams notifier")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	return &Notifier{options: options}, nil
+}
+
+// messageCard is the legacy Office 365 Connector card format accepted by
+// Teams incoming webhooks. card() below always builds this format; Adaptive
+// Cards and their attachments envelope are not produced.
+type messageCard struct {
+	Type       string           `json:"@type"`
+	Context    string           `json:"@context"`
+	Summary    string           `json:"summary"`
+	ThemeColor string           `json:"themeColor,omitempty"`
+	Title      string           `json:"title"`
+	Text       string           `json:"text"`
+	Sections   []messageSection `json:"sections,omitempty"`
+}
+
+type messageSection struct {
+	ActivityTitle    string `json:"activityTitle,omitempty"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+}
+
+// Notify posts event to the configured Teams webhook as a MessageCard.
+func (n *Notifier) Notify(event *output.ResultEvent) error {
+	if !n.options.AllowList.GetMatch(event) || (n.options.DenyList != nil && n.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	data, err := json.Marshal(card(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, n.options.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post teams message")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post teams message: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Close is a no-op for the stateless Teams notifier.
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Name returns the name of the notifier.
+func (n *Notifier) Name() string {
+	return "teams"
+}
+
+func card(event *output.ResultEvent) messageCard {
+	return messageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		Summary:    fmt.Sprintf("%s matched on %s", event.Info.Name, event.Host),
+		ThemeColor: severityColor(event.Info.SeverityHolder.Severity.String()),
+		Title:      event.Info.Name,
+		Text:       event.Info.Description,
+		Sections: []messageSection{
+			{ActivityTitle: event.Host, ActivitySubtitle: event.Info.SeverityHolder.Severity.String()},
+		},
+	}
+}
+
+func severityColor(severityName string) string {
+	switch severityName {
+	case "critical":
+		return "8B0000"
+	case "high":
+		return "FF0000"
+	case "medium":
+		return "FFA500"
+	case "low":
+		return "FFFF00"
+	default:
+		return "808080"
+	}
+}
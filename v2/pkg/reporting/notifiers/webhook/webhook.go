@@ -0,0 +1,127 @@
+// Package webhook implements a generic, HMAC-signed HTTP notifier whose
+// payload is shaped by a user-supplied Go text/template, so findings can be
+// delivered to arbitrary receivers (PagerDuty, OpsGenie, internal tooling)
+// without adding dedicated code for each one.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// defaultTemplate renders event as a minimal JSON payload when no custom
+// Template is configured.
+const defaultTemplate = `{"template_id":"{{.TemplateID}}","name":{{.Info.Name | printf "%q"}},"host":{{.Host | printf "%q"}},"severity":{{.Info.SeverityHolder.Severity.String | printf "%q"}}}`
+
+// signatureHeader carries the hex-encoded HMAC-SHA256 signature of the
+// request body, computed with SecretKey, when SecretKey is configured.
+const signatureHeader = "X-Nuclei-Signature"
+
+// Options contains the configuration options for the generic webhook notifier.
+type Options struct {
+	// URL is the endpoint the webhook payload is POSTed to.
+	URL string `yaml:"url"`
+	// SecretKey, if set, is used to HMAC-SHA256 sign the request body. The
+	// signature is sent in the X-Nuclei-Signature header.
+	SecretKey string `yaml:"secret-key"`
+	// Template is a Go text/template, executed against an *output.ResultEvent,
+	// that produces the request body. Defaults to a minimal JSON payload.
+	Template string `yaml:"template"`
+	// Headers contains additional static headers to send with the request.
+	Headers map[string]string `yaml:"headers"`
+	// AllowList contains a list of allowed events for this notifier.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this notifier.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Notifier is a generic, template-driven webhook notifier.
+type Notifier struct {
+	options  *Options
+	template *template.Template
+}
+
+// New creates a new generic webhook notifier.
+func New(options *Options) (*Notifier, error) {
+	if options.URL == "" {
+		return nil, errors.New("url is required for the webhook notifier")
+	}
+	if options.Template == "" {
+		options.Template = defaultTemplate
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+
+	tmpl, err := template.New("webhook").Parse(options.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse webhook template")
+	}
+	return &Notifier{options: options, template: tmpl}, nil
+}
+
+// Notify renders event through the configured template and POSTs the
+// result to URL, signing the body when SecretKey is set.
+func (n *Notifier) Notify(event *output.ResultEvent) error {
+	if !n.options.AllowList.GetMatch(event) || (n.options.DenyList != nil && n.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := n.template.Execute(&body, event); err != nil {
+		return errors.Wrap(err, "could not render webhook template")
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, n.options.URL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.options.Headers {
+		req.Header.Set(key, value)
+	}
+	if n.options.SecretKey != "" {
+		req.Header.Set(signatureHeader, sign(n.options.SecretKey, body.Bytes()))
+	}
+
+	resp, err := n.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not post webhook notification")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not post webhook notification: got status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// Close is a no-op for the stateless webhook notifier.
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// Name returns the name of the notifier.
+func (n *Notifier) Name() string {
+	return "webhook"
+}
+
+func sign(secretKey string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
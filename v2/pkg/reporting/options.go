@@ -2,9 +2,21 @@ package reporting
 
 import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/es"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/kafka"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/markdown"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/nats"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/sarif"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/splunk"
+	exporterwebhook "github.com/projectdiscovery/nuclei/v2/pkg/reporting/exporters/webhook"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/discord"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/slack"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/smtp"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/teams"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/notifiers/webhook"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/bitbucket"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/forgejo"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/gitea"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/github"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/gitlab"
 	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/trackers/jira"
@@ -14,15 +26,21 @@ import (
 // Options is a configuration file for nuclei reporting module
 type Options struct {
 	// AllowList contains a list of allowed events for reporting module
-	AllowList *Filter `yaml:"allow-list"`
+	AllowList *filters.Filter `yaml:"allow-list"`
 	// DenyList contains a list of denied events for reporting module
-	DenyList *Filter `yaml:"deny-list"`
+	DenyList *filters.Filter `yaml:"deny-list"`
 	// GitHub contains configuration options for GitHub Issue Tracker
 	GitHub *github.Options `yaml:"github"`
 	// GitLab contains configuration options for GitLab Issue Tracker
 	GitLab *gitlab.Options `yaml:"gitlab"`
 	// Jira contains configuration options for Jira Issue Tracker
 	Jira *jira.Options `yaml:"jira"`
+	// Gitea contains configuration options for Gitea Issue Tracker
+	Gitea *gitea.Options `yaml:"gitea"`
+	// Forgejo contains configuration options for Forgejo Issue Tracker
+	Forgejo *forgejo.Options `yaml:"forgejo"`
+	// Bitbucket contains configuration options for Bitbucket Issue Tracker
+	Bitbucket *bitbucket.Options `yaml:"bitbucket"`
 	// MarkdownExporter contains configuration options for Markdown Exporter Module
 	MarkdownExporter *markdown.Options `yaml:"markdown"`
 	// SarifExporter contains configuration options for Sarif Exporter Module
@@ -31,6 +49,35 @@ type Options struct {
 	ElasticsearchExporter *es.Options `yaml:"elasticsearch"`
 	// SplunkExporter contains configuration options for splunkhec Exporter Module
 	SplunkExporter *splunk.Options `yaml:"splunkhec"`
+	// KafkaExporter contains configuration options for the Kafka streaming Exporter Module
+	KafkaExporter *kafka.Options `yaml:"kafka"`
+	// NatsExporter contains configuration options for the NATS streaming Exporter Module
+	NatsExporter *nats.Options `yaml:"nats"`
+	// WebhookExporter contains configuration options for the generic,
+	// batched NDJSON webhook Exporter Module, distinct from Webhook (a
+	// single signed POST per finding, below).
+	WebhookExporter *exporterwebhook.Options `yaml:"webhook-exporter"`
+	// Slack contains configuration options for the Slack notifier
+	Slack *slack.Options `yaml:"slack"`
+	// Discord contains configuration options for the Discord notifier
+	Discord *discord.Options `yaml:"discord"`
+	// Teams contains configuration options for the Microsoft Teams notifier
+	Teams *teams.Options `yaml:"teams"`
+	// SMTP contains configuration options for the SMTP notifier
+	SMTP *smtp.Options `yaml:"smtp"`
+	// Webhook contains configuration options for the generic HMAC-signed webhook notifier
+	Webhook *webhook.Options `yaml:"webhook"`
+
+	// MinNotifySeverity, if set, restricts the Slack/Discord/Teams chat
+	// notifiers to findings at or above this severity ("info".."critical"),
+	// so a high-volume scan doesn't page a channel for every info finding.
+	// Does not affect trackers or exporters, which see every finding
+	// (subject to their own AllowList/DenyList).
+	MinNotifySeverity string `yaml:"min-notify-severity"`
+
+	// RetryPolicy, if set, is consulted by Client.CreateIssue for every
+	// Retryable tracker/notifier failure before it's given up on.
+	RetryPolicy RetryPolicy `yaml:"-"`
 
 	HttpClient *retryablehttp.Client `yaml:"-"`
 }
@@ -0,0 +1,277 @@
+// Package bitbucket implements an issue tracker integration for Bitbucket.
+//
+// Bitbucket Cloud and Bitbucket Server/Data Center expose unrelated APIs for
+// issue tracking (Cloud has a first-class Issues REST resource; Server has
+// no native issue tracker and is instead reached through its generic REST
+// API for a project's repository). Options.Variant selects which of the two
+// request shapes this integration speaks.
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// ErrRateLimited is returned when the Bitbucket API responds with a 429, so
+// callers can tell a transient rate-limit apart from a permanent failure
+// via errors.Is.
+var ErrRateLimited = errors.New("bitbucket: rate limited")
+
+// Variant selects which Bitbucket flavor an Integration talks to.
+type Variant string
+
+const (
+	// VariantCloud targets the hosted bitbucket.org Issues API.
+	VariantCloud Variant = "cloud"
+	// VariantServer targets a self-hosted Bitbucket Server/Data Center instance.
+	VariantServer Variant = "server"
+)
+
+// Options contains the configuration options for the Bitbucket issue tracker.
+type Options struct {
+	// Variant is either "cloud" (bitbucket.org) or "server" (self-hosted
+	// Bitbucket Server/Data Center). Defaults to "cloud".
+	Variant Variant `yaml:"variant"`
+	// BaseURL is the URL of the Bitbucket Server instance. Unused for the
+	// cloud variant, which always talks to api.bitbucket.org.
+	BaseURL string `yaml:"base-url"`
+	// Username is the Bitbucket username (or email, for Cloud) to authenticate as.
+	Username string `yaml:"username"`
+	// Workspace is the Bitbucket Cloud workspace that owns the repository.
+	Workspace string `yaml:"workspace"`
+	// Project is the Bitbucket Server project key that owns the repository.
+	Project string `yaml:"project"`
+	// Token is an app password (Cloud) or personal access token (Server).
+	Token string `yaml:"token"`
+	// ProjectName is the name (slug) of the repository issues are filed against.
+	ProjectName string `yaml:"project-name"`
+	// IssueLabel is an optional label/kind applied to every created issue.
+	IssueLabel string `yaml:"issue-label"`
+	// SeverityAsLabel, if true, also applies the finding's severity as a label.
+	SeverityAsLabel bool `yaml:"severity-as-label"`
+	// DuplicateIssueCheck enables searching for an already-open issue with
+	// the same title before creating a new one. Cloud-only: Bitbucket
+	// Server has no issue-search endpoint to check against.
+	DuplicateIssueCheck bool `yaml:"duplicate-issue-check"`
+	// AllowList contains a list of allowed events for this tracker.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this tracker.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Integration is a client for the Bitbucket issue tracker.
+type Integration struct {
+	options *Options
+}
+
+// New creates a new Bitbucket tracker integration client.
+func New(options *Options) (*Integration, error) {
+	if options.Variant == "" {
+		options.Variant = VariantCloud
+	}
+	if options.Token == "" || options.ProjectName == "" {
+		return nil, errors.New("token and project-name are required for the bitbucket tracker")
+	}
+	switch options.Variant {
+	case VariantCloud:
+		if options.Workspace == "" {
+			return nil, errors.New("workspace is required for the bitbucket cloud tracker")
+		}
+	case VariantServer:
+		if options.BaseURL == "" || options.Project == "" {
+			return nil, errors.New("base-url and project are required for the bitbucket server tracker")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bitbucket variant: %s", options.Variant)
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	return &Integration{options: options}, nil
+}
+
+type cloudIssueRequest struct {
+	Title    string            `json:"title"`
+	Content  cloudIssueContent `json:"content"`
+	Kind     string            `json:"kind,omitempty"`
+	Priority string            `json:"priority,omitempty"`
+}
+
+type cloudIssueContent struct {
+	Raw string `json:"raw"`
+}
+
+type cloudIssueResponse struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+type cloudIssueListResponse struct {
+	Values []cloudIssueResponse `json:"values"`
+}
+
+// CreateIssue creates a new issue for event on the configured Bitbucket variant.
+func (i *Integration) CreateIssue(event *output.ResultEvent) error {
+	if !i.options.AllowList.GetMatch(event) || (i.options.DenyList != nil && i.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	if i.options.Variant == VariantServer {
+		return i.createServerTask(event)
+	}
+	return i.createCloudIssue(event)
+}
+
+// createCloudIssue files (or skips, on a detected duplicate) an issue
+// against the Bitbucket Cloud Issues REST API.
+func (i *Integration) createCloudIssue(event *output.ResultEvent) error {
+	title := issueTitle(event)
+
+	if i.options.DuplicateIssueCheck {
+		exists, err := i.cloudIssueExists(title)
+		if err != nil {
+			return errors.Wrap(err, "could not check for duplicate bitbucket issue")
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	issueReq := cloudIssueRequest{Title: title, Content: cloudIssueContent{Raw: issueBody(event)}}
+	if i.options.IssueLabel != "" {
+		issueReq.Kind = i.options.IssueLabel
+	}
+	if i.options.SeverityAsLabel && event.Info.SeverityHolder.Severity != severity.Unknown {
+		issueReq.Priority = event.Info.SeverityHolder.Severity.String()
+	}
+
+	data, err := json.Marshal(issueReq)
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, i.cloudIssuesURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	i.setCloudHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not create bitbucket issue")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not create bitbucket issue: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (i *Integration) cloudIssueExists(title string) (bool, error) {
+	searchURL := fmt.Sprintf("%s?q=title~%q", i.cloudIssuesURL(), title)
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, searchURL, nil)
+	if err != nil {
+		return false, err
+	}
+	i.setCloudHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var list cloudIssueListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return false, err
+	}
+	for _, existing := range list.Values {
+		if existing.Title == title {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i *Integration) cloudIssuesURL() string {
+	return fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/issues", i.options.Workspace, i.options.ProjectName)
+}
+
+func (i *Integration) setCloudHeaders(req *retryablehttp.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(i.options.Username, i.options.Token)
+}
+
+// createServerTask reports event against a Bitbucket Server/Data Center
+// repository. Server has no issue tracker of its own, so findings are
+// recorded as a repository comment on the default branch commit instead -
+// duplicate detection isn't available here since there's no search endpoint
+// to check against.
+func (i *Integration) createServerTask(event *output.ResultEvent) error {
+	payload := map[string]string{"text": fmt.Sprintf("%s\n\n%s", issueTitle(event), issueBody(event))}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	commentsURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/comments",
+		strings.TrimSuffix(i.options.BaseURL, "/"), i.options.Project, i.options.ProjectName)
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, commentsURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(i.options.Username, i.options.Token)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not record bitbucket server finding")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not record bitbucket server finding: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func issueTitle(event *output.ResultEvent) string {
+	return fmt.Sprintf("%s %s", event.Info.Name, event.Host)
+}
+
+func issueBody(event *output.ResultEvent) string {
+	return fmt.Sprintf("Template: %s\nSeverity: %s\nHost: %s\nMatched at: %s\n\n%s",
+		event.TemplateID, event.Info.SeverityHolder.Severity, event.Host, event.Matched, event.Info.Description)
+}
+
+// Name returns the name of the integration.
+func (i *Integration) Name() string {
+	return "bitbucket"
+}
@@ -0,0 +1,247 @@
+// Package forgejo implements an issue tracker integration for Forgejo.
+//
+// Forgejo started as a hard fork of Gitea but has since diverged: it pins
+// its own versioned API under /api/forgejo/v1 and adds fields the Gitea API
+// doesn't carry (poster metadata on comments, agit push references). This
+// client is therefore independent of the gitea tracker rather than wrapping
+// it - but since plenty of Forgejo deployments still only expose the
+// inherited Gitea-compatible surface, New probes for the native API first
+// and falls back to the shared /api/v1 endpoints when it isn't available.
+package forgejo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// ErrRateLimited is returned when the Forgejo API responds with a 429, so
+// callers can tell a transient rate-limit apart from a permanent failure
+// via errors.Is.
+var ErrRateLimited = errors.New("forgejo: rate limited")
+
+// apiBasePath is the native, versioned Forgejo API prefix.
+const apiBasePath = "/api/forgejo/v1"
+
+// giteaCompatBasePath is the inherited Gitea-compatible API prefix that
+// older or minimally-patched Forgejo instances still expose.
+const giteaCompatBasePath = "/api/v1"
+
+// Options contains the configuration options for the Forgejo issue tracker.
+type Options struct {
+	// BaseURL is the URL of the Forgejo instance, e.g. https://forgejo.example.com.
+	BaseURL string `yaml:"base-url"`
+	// Username is the Forgejo username to use for authentication.
+	Username string `yaml:"username"`
+	// Owner is the owner (user or organization) of the repository.
+	Owner string `yaml:"owner"`
+	// Token is the access token used to authenticate with the Forgejo API.
+	Token string `yaml:"token"`
+	// ProjectName is the name of the repository issues are filed against.
+	ProjectName string `yaml:"project-name"`
+	// IssueLabel is an optional label applied to every created issue.
+	IssueLabel string `yaml:"issue-label"`
+	// SeverityAsLabel, if true, also applies the finding's severity as a label.
+	SeverityAsLabel bool `yaml:"severity-as-label"`
+	// DuplicateIssueCheck enables searching for an already-open issue with
+	// the same title before creating a new one.
+	DuplicateIssueCheck bool `yaml:"duplicate-issue-check"`
+	// AllowList contains a list of allowed events for this tracker.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this tracker.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Integration is a client for the Forgejo issue tracker.
+type Integration struct {
+	options *Options
+	// basePath is the API prefix to use, resolved once in New by probing
+	// the instance for native Forgejo API support.
+	basePath string
+}
+
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+// New creates a new Forgejo tracker integration client. It probes the
+// instance for the native /api/forgejo/v1 surface and transparently falls
+// back to the Gitea-compatible /api/v1 surface when that probe fails.
+func New(options *Options) (*Integration, error) {
+	if options.BaseURL == "" || options.Owner == "" || options.ProjectName == "" || options.Token == "" {
+		return nil, errors.New("base-url, owner, project-name and token are required for the forgejo tracker")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+
+	integration := &Integration{options: options, basePath: giteaCompatBasePath}
+	if integration.supportsNativeAPI() {
+		integration.basePath = apiBasePath
+	}
+	return integration, nil
+}
+
+// supportsNativeAPI reports whether the configured instance answers on the
+// versioned Forgejo API.
+func (i *Integration) supportsNativeAPI() bool {
+	req, err := retryablehttp.NewRequest(http.MethodGet, i.baseURL()+apiBasePath+"/version", nil)
+	if err != nil {
+		return false
+	}
+	i.setHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	var version versionResponse
+	return json.NewDecoder(resp.Body).Decode(&version) == nil && version.Version != ""
+}
+
+type issueRequest struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+	// Ref is the agit push reference field of the Forgejo issue API.
+	// CreateIssue does not set it, so it is always omitted from the request.
+	Ref string `json:"ref,omitempty"`
+}
+
+type issueResponse struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+// CreateIssue creates a new issue for event, skipping creation if an open
+// issue with the same title already exists and DuplicateIssueCheck is set.
+func (i *Integration) CreateIssue(event *output.ResultEvent) error {
+	if !i.options.AllowList.GetMatch(event) || (i.options.DenyList != nil && i.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	title := issueTitle(event)
+
+	if i.options.DuplicateIssueCheck {
+		exists, err := i.issueExists(title)
+		if err != nil {
+			return errors.Wrap(err, "could not check for duplicate forgejo issue")
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(issueRequest{Title: title, Body: issueBody(event), Labels: i.labelsForEvent(event)})
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, i.issuesURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	i.setHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not create forgejo issue")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not create forgejo issue: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// issueExists searches for an open issue with the given title.
+func (i *Integration) issueExists(title string) (bool, error) {
+	searchURL := fmt.Sprintf("%s?q=%s&type=issues&state=open", i.issuesURL(), strings.ReplaceAll(title, " ", "+"))
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, searchURL, nil)
+	if err != nil {
+		return false, err
+	}
+	i.setHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var issues []issueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return false, err
+	}
+	for _, existing := range issues {
+		if existing.Title == title {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// labelsForEvent returns the configured labels to apply for event.
+func (i *Integration) labelsForEvent(event *output.ResultEvent) []string {
+	var labels []string
+	if i.options.IssueLabel != "" {
+		labels = append(labels, i.options.IssueLabel)
+	}
+	if i.options.SeverityAsLabel && event.Info.SeverityHolder.Severity != severity.Unknown {
+		labels = append(labels, event.Info.SeverityHolder.Severity.String())
+	}
+	return labels
+}
+
+func issueTitle(event *output.ResultEvent) string {
+	return fmt.Sprintf("%s %s", event.Info.Name, event.Host)
+}
+
+func issueBody(event *output.ResultEvent) string {
+	return fmt.Sprintf("Template: %s\nSeverity: %s\nHost: %s\nMatched at: %s\n\n%s",
+		event.TemplateID, event.Info.SeverityHolder.Severity, event.Host, event.Matched, event.Info.Description)
+}
+
+func (i *Integration) baseURL() string {
+	return strings.TrimSuffix(i.options.BaseURL, "/")
+}
+
+func (i *Integration) issuesURL() string {
+	return fmt.Sprintf("%s%s/repos/%s/%s/issues", i.baseURL(), i.basePath, i.options.Owner, i.options.ProjectName)
+}
+
+func (i *Integration) setHeaders(req *retryablehttp.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token "+i.options.Token)
+}
+
+// Name returns the name of the integration.
+func (i *Integration) Name() string {
+	return "forgejo"
+}
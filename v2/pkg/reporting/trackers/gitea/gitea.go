@@ -0,0 +1,197 @@
+// Package gitea implements an issue tracker integration for Gitea.
+//
+// Gitea's REST API is modelled closely on GitHub's, so the shape of this
+// integration (base URL + token auth, search-then-create for dedup,
+// severity-to-label mapping) mirrors the GitHub tracker rather than
+// reinventing it.
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/reporting/filters"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// ErrRateLimited is returned by CreateIssue when the Gitea API responds
+// with a 429, so callers can tell a transient rate-limit apart from a
+// permanent failure via errors.Is.
+var ErrRateLimited = errors.New("gitea: rate limited")
+
+// Options contains the configuration options for the Gitea issue tracker.
+type Options struct {
+	// BaseURL is the URL of the Gitea instance, e.g. https://gitea.example.com.
+	BaseURL string `yaml:"base-url"`
+	// Username is the Gitea username to use for authentication.
+	Username string `yaml:"username"`
+	// Owner is the owner (user or organization) of the repository.
+	Owner string `yaml:"owner"`
+	// Token is the access token used to authenticate with the Gitea API.
+	Token string `yaml:"token"`
+	// ProjectName is the name of the repository issues are filed against.
+	ProjectName string `yaml:"project-name"`
+	// IssueLabel is an optional label applied to every created issue.
+	IssueLabel string `yaml:"issue-label"`
+	// SeverityAsLabel, if true, also applies the finding's severity as a label.
+	SeverityAsLabel bool `yaml:"severity-as-label"`
+	// DuplicateIssueCheck enables searching for an already-open issue with
+	// the same title before creating a new one.
+	DuplicateIssueCheck bool `yaml:"duplicate-issue-check"`
+	// AllowList contains a list of allowed events for this tracker.
+	AllowList *filters.Filter `yaml:"allow-list"`
+	// DenyList contains a list of denied events for this tracker.
+	DenyList *filters.Filter `yaml:"deny-list"`
+
+	HTTPClient *retryablehttp.Client `yaml:"-"`
+}
+
+// Integration is a client for the Gitea issue tracker.
+type Integration struct {
+	options *Options
+}
+
+// New creates a new Gitea tracker integration client.
+func New(options *Options) (*Integration, error) {
+	if options.BaseURL == "" || options.Owner == "" || options.ProjectName == "" || options.Token == "" {
+		return nil, errors.New("base-url, owner, project-name and token are required for the gitea tracker")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	return &Integration{options: options}, nil
+}
+
+type issueRequest struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+type issueResponse struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+// CreateIssue creates a new issue for event, skipping creation if an open
+// issue with the same title already exists and DuplicateIssueCheck is set.
+func (i *Integration) CreateIssue(event *output.ResultEvent) error {
+	if !i.options.AllowList.GetMatch(event) || (i.options.DenyList != nil && i.options.DenyList.GetMatch(event)) {
+		return nil
+	}
+
+	title := issueTitle(event)
+
+	if i.options.DuplicateIssueCheck {
+		exists, err := i.issueExists(title)
+		if err != nil {
+			return errors.Wrap(err, "could not check for duplicate gitea issue")
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(issueRequest{Title: title, Body: issueBody(event), Labels: i.labelsForEvent(event)})
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, i.issuesURL(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	i.setHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not create gitea issue")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("could not create gitea issue: got status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// issueExists searches for an open issue with the given title.
+func (i *Integration) issueExists(title string) (bool, error) {
+	searchURL := fmt.Sprintf("%s?q=%s&type=issues&state=open", i.issuesURL(), strings.ReplaceAll(title, " ", "+"))
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, searchURL, nil)
+	if err != nil {
+		return false, err
+	}
+	i.setHeaders(req)
+
+	resp, err := i.options.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var issues []issueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return false, err
+	}
+	for _, existing := range issues {
+		if existing.Title == title {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// labelsForEvent returns the configured labels to apply for event.
+func (i *Integration) labelsForEvent(event *output.ResultEvent) []string {
+	var labels []string
+	if i.options.IssueLabel != "" {
+		labels = append(labels, i.options.IssueLabel)
+	}
+	if i.options.SeverityAsLabel && event.Info.SeverityHolder.Severity != severity.Unknown {
+		labels = append(labels, event.Info.SeverityHolder.Severity.String())
+	}
+	return labels
+}
+
+// issueTitle builds a stable, human-readable title for event so the same
+// finding always maps back to the same issue.
+func issueTitle(event *output.ResultEvent) string {
+	return fmt.Sprintf("%s %s", event.Info.Name, event.Host)
+}
+
+// issueBody builds the issue body for event.
+func issueBody(event *output.ResultEvent) string {
+	return fmt.Sprintf("Template: %s\nSeverity: %s\nHost: %s\nMatched at: %s\n\n%s",
+		event.TemplateID, event.Info.SeverityHolder.Severity, event.Host, event.Matched, event.Info.Description)
+}
+
+func (i *Integration) issuesURL() string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/issues", strings.TrimSuffix(i.options.BaseURL, "/"), i.options.Owner, i.options.ProjectName)
+}
+
+func (i *Integration) setHeaders(req *retryablehttp.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "token "+i.options.Token)
+}
+
+// Name returns the name of the integration.
+func (i *Integration) Name() string {
+	return "gitea"
+}
@@ -0,0 +1,17 @@
+// Package trackers holds the common interface implemented by every
+// issue-tracker integration (GitHub, GitLab, Jira, Gitea, Bitbucket, ...)
+// that nuclei's reporting module can file findings against.
+package trackers
+
+import "github.com/projectdiscovery/nuclei/v2/pkg/output"
+
+// Tracker is implemented by an issue-tracker integration that can file
+// (and avoid re-filing) tickets for nuclei findings.
+type Tracker interface {
+	// CreateIssue creates a new issue on the tracker for event. Trackers
+	// that support duplicate detection should instead update, or simply
+	// skip, an existing open issue for the same finding.
+	CreateIssue(event *output.ResultEvent) error
+	// Name returns the tracker's name, used to identify it in a ReportingError.
+	Name() string
+}
@@ -7,11 +7,13 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"regexp"
 	"strings"
 
 	"github.com/Knetic/govaluate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/auth/challenge"
 	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
 	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
@@ -65,8 +67,29 @@ type BulkHTTPRequest struct {
 	Raw []string `yaml:"raw,omitempty"`
 	// Specify in order to skip request RFC normalization
 	Unsafe bool `yaml:"unsafe,omitempty"`
+	// Retry overrides the scan-wide retry behaviour for this request, e.g.
+	// to retry only on specific status codes or to disable retries for an
+	// endpoint where a 5xx is the actual match. Nil falls back to the
+	// executor's client-wide retry defaults.
+	Retry *RetryPolicy `yaml:"retry,omitempty"`
+	// Files injects a file's contents by path into a named multipart
+	// form field, letting raw multipart/form-data requests carry a small
+	// polyglot/webshell fixture alongside the rule instead of
+	// base64-embedding it in the YAML.
+	Files map[string]string `yaml:"files,omitempty"`
 	// Internal Finite State Machine keeping track of scan process
 	gsfm *GeneratorFSM
+	// authenticator resolves WWW-Authenticate challenges returned by the
+	// target into an Authorization header; nil disables challenge-driven
+	// auth entirely.
+	authenticator *challenge.Authenticator
+}
+
+// SetAuthenticator attaches a challenge.Authenticator to the request,
+// enabling automatic WWW-Authenticate challenge handling for targets that
+// respond with 401/407. Call before MakeHTTPRequest.
+func (r *BulkHTTPRequest) SetAuthenticator(authenticator *challenge.Authenticator) {
+	r.authenticator = authenticator
 }
 
 // GetMatchersCondition returns the condition for the matcher
@@ -132,7 +155,7 @@ func (r *BulkHTTPRequest) makeHTTPRequestFromModel(ctx context.Context, data str
 		return nil, err
 	}
 
-	return &HTTPRequest{Request: request}, nil
+	return r.toHTTPRequest(request), nil
 }
 
 // InitGenerator initializes the generator
@@ -211,6 +234,10 @@ func (r *BulkHTTPRequest) handleRawWithPaylods(ctx context.Context, raw, baseURL
 
 	// rawhttp
 	if r.Unsafe {
+		if rawRequest.Chunked {
+			rawRequest.Data = reChunk(rawRequest.Data)
+			rawRequest.Headers["Transfer-Encoding"] = "chunked"
+		}
 		return &HTTPRequest{RawRequest: rawRequest, Meta: genValues}, nil
 	}
 
@@ -230,7 +257,9 @@ func (r *BulkHTTPRequest) handleRawWithPaylods(ctx context.Context, raw, baseURL
 		return nil, err
 	}
 
-	return &HTTPRequest{Request: request, Meta: genValues}, nil
+	httpRequest := r.toHTTPRequest(request)
+	httpRequest.Meta = genValues
+	return httpRequest, nil
 }
 
 func (r *BulkHTTPRequest) fillRequest(req *http.Request, values map[string]interface{}) (*retryablehttp.Request, error) {
@@ -248,6 +277,15 @@ func (r *BulkHTTPRequest) fillRequest(req *http.Request, values map[string]inter
 		req.Header[header] = []string{replacer.Replace(value)}
 	}
 
+	// If a prior request to this host already resolved a WWW-Authenticate
+	// challenge, reuse the Authorization header instead of taking a
+	// guaranteed 401/407 round trip on every subsequent request.
+	if r.authenticator != nil {
+		if authz, ok := r.authenticator.CachedAuthorization(req.URL.Host); ok {
+			setHeader(req, "Authorization", authz)
+		}
+	}
+
 	setHeader(req, "User-Agent", "Nuclei - Open-source project (github.com/projectdiscovery/nuclei)")
 
 	// raw requests are left untouched
@@ -266,6 +304,23 @@ type HTTPRequest struct {
 	Request    *retryablehttp.Request
 	RawRequest *RawRequest
 	Meta       map[string]interface{}
+	// CheckRetry and Backoff implement this request's RetryPolicy, ready to
+	// be installed on a per-request retryablehttp.Client. Both are nil when
+	// no RetryPolicy was configured, in which case the caller's client-wide
+	// retry defaults apply.
+	CheckRetry retryablehttp.CheckRetry
+	Backoff    retryablehttp.Backoff
+}
+
+// toHTTPRequest wraps request into a HTTPRequest, attaching the per-request
+// CheckRetry/Backoff closures built from r.Retry, if any was configured.
+func (r *BulkHTTPRequest) toHTTPRequest(request *retryablehttp.Request) *HTTPRequest {
+	httpRequest := &HTTPRequest{Request: request}
+	if r.Retry != nil {
+		httpRequest.CheckRetry = r.Retry.checkRetry()
+		httpRequest.Backoff = r.Retry.backoff()
+	}
+	return httpRequest
 }
 
 func setHeader(req *http.Request, name, value string) {
@@ -315,46 +370,81 @@ type RawRequest struct {
 	Path    string
 	Data    string
 	Headers map[string]string
+	// Parts holds the individual parts of a multipart/form-data body, when
+	// the request's Content-Type is multipart/* (or BulkHTTPRequest.Files
+	// is set). Nil for non-multipart requests.
+	Parts []MultipartPart
+	// Chunked records whether the original raw request declared
+	// Transfer-Encoding: chunked. Data holds the already-decoded body;
+	// handleRawWithPaylods re-chunks it with reChunk before handing an
+	// Unsafe request off to be written to the wire.
+	Chunked bool
+}
+
+// ParseRawRequest parses a raw HTTP request against baseURL using the same
+// machinery as BulkHTTPRequest's own raw requests (textproto header
+// parsing, chunked decoding, multipart re-serialization), without any
+// per-request file injection. It lets other protocol executers - e.g. the
+// websocket executer's Upgrade handshake - reuse this request's
+// parsing instead of re-implementing it.
+func ParseRawRequest(request, baseURL string) (*RawRequest, error) {
+	empty := &BulkHTTPRequest{}
+	return empty.parseRawRequest(request, baseURL)
 }
 
-// parseRawRequest parses the raw request as supplied by the user
+// parseRawRequest parses the raw request as supplied by the user. Headers
+// are read line-by-line rather than with textproto.Reader.ReadMIMEHeader,
+// tolerating duplicate headers (last one wins) and lines without a colon
+// instead of failing the whole parse, since Unsafe/raw mode exists
+// specifically so templates can send malformed requests (e.g. request
+// smuggling PoCs); folded continuation lines are still honored on top of
+// that tolerant handling. Multipart/form-data bodies are split into Parts
+// and chunked bodies are decoded before the request is matched against or
+// templated further.
 func (r *BulkHTTPRequest) parseRawRequest(request, baseURL string) (*RawRequest, error) {
 	reader := bufio.NewReader(strings.NewReader(request))
+	tp := textproto.NewReader(reader)
 
-	rawRequest := RawRequest{
-		Headers: make(map[string]string),
-	}
-
-	s, err := reader.ReadString('\n')
+	requestLine, err := tp.ReadLine()
 	if err != nil {
 		return nil, fmt.Errorf("could not read request: %s", err)
 	}
 
-	parts := strings.Split(s, " ")
-
+	parts := strings.Split(requestLine, " ")
 	if len(parts) < three {
 		return nil, fmt.Errorf("malformed request supplied")
 	}
+
+	rawRequest := RawRequest{
+		Headers: make(map[string]string),
+	}
 	// Set the request Method
 	rawRequest.Method = parts[0]
 
-	// Accepts all malformed headers
-	var key, value string
+	// Accepts all malformed headers: duplicate header lines overwrite
+	// (last one wins) rather than being joined, and a line without a colon
+	// is kept as a header with an empty value instead of aborting the
+	// parse. A line starting with a space/tab folds into the previous
+	// header's value (RFC 7230 obs-fold) on top of that tolerant handling.
+	var lastKey string
 	for {
-		line, readErr := reader.ReadString('\n')
-		line = strings.TrimSpace(line)
-
+		line, readErr := tp.ReadLine()
 		if readErr != nil || line == "" {
 			break
 		}
-
+		if lastKey != "" && (line[0] == ' ' || line[0] == '\t') {
+			rawRequest.Headers[lastKey] += " " + strings.TrimSpace(line)
+			continue
+		}
+		line = strings.TrimSpace(line)
 		p := strings.SplitN(line, ":", two)
-		key = p[0]
+		key := p[0]
+		var value string
 		if len(p) > 1 {
 			value = p[1]
 		}
-
 		rawRequest.Headers[key] = value
+		lastKey = key
 	}
 
 	// Handle case with the full http url in path. In that case,
@@ -401,8 +491,37 @@ func (r *BulkHTTPRequest) parseRawRequest(request, baseURL string) (*RawRequest,
 	if err != nil {
 		return nil, fmt.Errorf("could not read request body: %s", err)
 	}
+	body := string(b)
+
+	if strings.EqualFold(rawRequest.Headers["Transfer-Encoding"], "chunked") {
+		if body, err = decodeChunked(body); err != nil {
+			return nil, err
+		}
+		rawRequest.Chunked = true
+		delete(rawRequest.Headers, "Transfer-Encoding")
+	}
+
+	if boundary, ok := isMultipart(rawRequest.Headers["Content-Type"]); ok || len(r.Files) > 0 {
+		var parts []MultipartPart
+		if ok {
+			if parts, err = parseMultipartParts(body, boundary); err != nil {
+				return nil, err
+			}
+		}
+		if parts, err = addFileParts(parts, r.Files); err != nil {
+			return nil, err
+		}
+
+		newBody, contentType, err := reserializeMultipart(parts)
+		if err != nil {
+			return nil, err
+		}
+		rawRequest.Parts = parts
+		rawRequest.Headers["Content-Type"] = contentType
+		body = newBody
+	}
 
-	rawRequest.Data = string(b)
+	rawRequest.Data = body
 
 	return &rawRequest, nil
 }
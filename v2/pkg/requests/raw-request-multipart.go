@@ -0,0 +1,137 @@
+package requests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http/httputil"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// MultipartPart is a single part of a multipart/form-data body, parsed out
+// of a raw request (or injected via BulkHTTPRequest.Files) so templates can
+// carry file-upload fixtures without hand-rolling boundaries in the YAML.
+type MultipartPart struct {
+	Name     string
+	Filename string
+	Headers  textproto.MIMEHeader
+	Body     string
+}
+
+// isMultipart reports whether contentType is a multipart/* media type and,
+// if so, returns its boundary parameter.
+func isMultipart(contentType string) (boundary string, ok bool) {
+	if contentType == "" {
+		return "", false
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// parseMultipartParts splits body (a multipart/form-data payload already
+// stripped of any Transfer-Encoding) into its individual parts.
+func parseMultipartParts(body, boundary string) ([]MultipartPart, error) {
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+
+	var parts []MultipartPart
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read multipart part: %s", err)
+		}
+
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("could not read multipart part body: %s", err)
+		}
+
+		parts = append(parts, MultipartPart{
+			Name:     part.FormName(),
+			Filename: part.FileName(),
+			Headers:  textproto.MIMEHeader(part.Header),
+			Body:     string(data),
+		})
+	}
+	return parts, nil
+}
+
+// addFileParts reads each configured file by path and appends it to parts
+// as a named file-upload part, letting templates inject a small
+// polyglot/webshell fixture into an otherwise ordinary form without
+// base64-embedding it in the YAML.
+func addFileParts(parts []MultipartPart, files map[string]string) ([]MultipartPart, error) {
+	for field, path := range files {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %q for field %q: %s", path, field, err)
+		}
+		parts = append(parts, MultipartPart{
+			Name:     field,
+			Filename: filepath.Base(path),
+			Body:     string(data),
+		})
+	}
+	return parts, nil
+}
+
+// reserializeMultipart writes parts out with a fresh boundary, so that
+// variable substitution (which can change a part's body length) never
+// invalidates the original boundary or a stale Content-Length.
+func reserializeMultipart(parts []MultipartPart) (body, contentType string, err error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, part := range parts {
+		var partWriter io.Writer
+		if part.Filename != "" {
+			partWriter, err = writer.CreateFormFile(part.Name, part.Filename)
+		} else {
+			partWriter, err = writer.CreateFormField(part.Name)
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("could not create multipart part %q: %s", part.Name, err)
+		}
+		if _, err = partWriter.Write([]byte(part.Body)); err != nil {
+			return "", "", fmt.Errorf("could not write multipart part %q: %s", part.Name, err)
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return "", "", fmt.Errorf("could not close multipart writer: %s", err)
+	}
+	return buf.String(), writer.FormDataContentType(), nil
+}
+
+// decodeChunked decodes a Transfer-Encoding: chunked body into its raw
+// payload, so downstream variable replacement and matching operate on the
+// actual content rather than chunk-size framing.
+func decodeChunked(body string) (string, error) {
+	reader := httputil.NewChunkedReader(strings.NewReader(body))
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("could not decode chunked body: %s", err)
+	}
+	return string(data), nil
+}
+
+// reChunk re-encodes body using HTTP/1.1 chunked transfer-encoding framing,
+// a single chunk followed by the terminating zero-length chunk. It's used
+// when resending an Unsafe raw request that declared
+// Transfer-Encoding: chunked, since decodeChunked already stripped the
+// original framing for templating and matching.
+func reChunk(body string) string {
+	if body == "" {
+		return "0\r\n\r\n"
+	}
+	return fmt.Sprintf("%x\r\n%s\r\n0\r\n\r\n", len(body), body)
+}
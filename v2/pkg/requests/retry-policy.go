@@ -0,0 +1,135 @@
+package requests
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	retryablehttp "github.com/projectdiscovery/retryablehttp-go"
+)
+
+// RetryBackoff is the delay growth strategy used between retry attempts.
+type RetryBackoff string
+
+const (
+	// BackoffConstant retries after the same Initial delay every time.
+	BackoffConstant RetryBackoff = "constant"
+	// BackoffExponential doubles the delay on every attempt, capped at MaxDelay.
+	BackoffExponential RetryBackoff = "exponential"
+)
+
+const (
+	defaultRetryInitial  = 500 * time.Millisecond
+	defaultRetryMaxDelay = 10 * time.Second
+)
+
+// RetryPolicy lets a template override the scan-wide `-retries` flag for its
+// own requests: retry only on the status codes that actually indicate a
+// flaky target, back off exponentially (optionally honoring Retry-After),
+// and give up immediately on status codes that are themselves the match.
+// A nil *RetryPolicy means the request falls back to the executor's
+// client-wide retry defaults.
+type RetryPolicy struct {
+	// Max is the maximum number of retry attempts. Zero disables retries
+	// for the request entirely.
+	Max int `yaml:"max,omitempty"`
+	// Backoff is the delay growth strategy between attempts. Defaults to
+	// BackoffExponential.
+	Backoff RetryBackoff `yaml:"backoff,omitempty"`
+	// Initial is the delay before the first retry, e.g. "500ms". Defaults
+	// to 500ms.
+	Initial string `yaml:"initial,omitempty"`
+	// MaxDelay caps the delay between retries, e.g. "10s". Defaults to 10s.
+	MaxDelay string `yaml:"max_delay,omitempty"`
+	// OnStatus restricts retries to responses with one of these status
+	// codes, in addition to connection errors. Empty means any 5xx
+	// response is retried, matching retryablehttp's default policy.
+	OnStatus []int `yaml:"on_status,omitempty"`
+	// RespectRetryAfter honors a Retry-After header on the response,
+	// overriding the computed backoff delay when present.
+	RespectRetryAfter bool `yaml:"respect_retry_after,omitempty"`
+	// GiveupStatus short-circuits retries for these status codes even if
+	// they also appear in OnStatus, for endpoints where e.g. a 404 is the
+	// actual match rather than a transient failure.
+	GiveupStatus []int `yaml:"giveup_status,omitempty"`
+}
+
+// checkRetry builds a retryablehttp.CheckRetry closure implementing the
+// policy's Max/OnStatus/GiveupStatus rules.
+func (p *RetryPolicy) checkRetry() retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if resp == nil {
+			return err != nil, nil
+		}
+		for _, status := range p.GiveupStatus {
+			if resp.StatusCode == status {
+				return false, nil
+			}
+		}
+		if len(p.OnStatus) == 0 {
+			return resp.StatusCode >= http.StatusInternalServerError, nil
+		}
+		for _, status := range p.OnStatus {
+			if resp.StatusCode == status {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// backoff builds a retryablehttp.Backoff closure implementing the policy's
+// Backoff/Initial/MaxDelay/RespectRetryAfter rules.
+func (p *RetryPolicy) backoff() retryablehttp.Backoff {
+	initial := durationOrDefault(p.Initial, defaultRetryInitial)
+	maxDelay := durationOrDefault(p.MaxDelay, defaultRetryMaxDelay)
+
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if p.RespectRetryAfter && resp != nil {
+			if delay, ok := retryAfterDelay(resp); ok {
+				return delay
+			}
+		}
+		if p.Backoff == BackoffConstant {
+			return initial
+		}
+		delay := initial << uint(attemptNum)
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		return delay
+	}
+}
+
+// retryAfterDelay returns the delay requested by a Retry-After header,
+// supporting both the delay-seconds and HTTP-date forms defined in RFC 7231.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay, true
+		}
+	}
+	return 0, false
+}
+
+func durationOrDefault(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/store"
+)
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><title>nuclei</title></head>
+<body>
+<h1>Scans</h1>
+<table border="1" cellpadding="4">
+<tr><th>ID</th><th>Targets</th><th>Status</th><th>Started</th></tr>
+{{range .}}
+<tr>
+<td><a href="/scans/{{.ID}}">{{.ID}}</a></td>
+<td>{{range .Targets}}{{.}} {{end}}</td>
+<td>{{.Status}}</td>
+<td>{{.StartedAt}}</td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>`))
+
+var scanTemplate = template.Must(template.New("scan").Parse(`<!DOCTYPE html>
+<html>
+<head><title>scan {{.ID}}</title></head>
+<body>
+<h1>Scan {{.ID}}</h1>
+<p>Status: {{.Status}}</p>
+<p>Targets: {{range .Targets}}{{.}} {{end}}</p>
+<p><a href="/findings/{{.ID}}">View findings</a></p>
+</body>
+</html>`))
+
+var findingsTemplate = template.Must(template.New("findings").Parse(`<!DOCTYPE html>
+<html>
+<head><title>findings for {{.Scan.ID}}</title></head>
+<body>
+<h1>Findings for scan {{.Scan.ID}}</h1>
+<table border="1" cellpadding="4">
+<tr><th>Template</th><th>Host</th><th>Matched</th></tr>
+{{range .Findings}}
+<tr><td>{{.TemplateID}}</td><td>{{.Host}}</td><td>{{.Matched}}</td></tr>
+{{end}}
+</table>
+</body>
+</html>`))
+
+// handleIndex serves the scan list ("/") and the per-scan findings HTML page
+// (/findings/{id}). The per-scan detail page at /scans/{id} is not served
+// here; handleScanByID renders it when the client sends Accept: text/html.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/":
+		s.renderIndex(w)
+	case len(r.URL.Path) > len("/findings/") && r.URL.Path[:len("/findings/")] == "/findings/":
+		s.renderFindings(w, r.URL.Path[len("/findings/"):])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// renderScan serves the HTML detail page for scan when the client asked
+// for it via Accept: text/html - the JSON form of the same route is the
+// REST API's default.
+func (s *Server) renderScan(w http.ResponseWriter, scan *store.Scan) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := scanTemplate.Execute(w, scan); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) renderIndex(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, s.store.List()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) renderFindings(w http.ResponseWriter, id string) {
+	scan, ok := s.store.Get(id)
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	data := struct {
+		Scan     *store.Scan
+		Findings interface{}
+	}{Scan: scan, Findings: scan.Findings()}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := findingsTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
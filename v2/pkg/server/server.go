@@ -0,0 +1,181 @@
+// Package server implements nuclei's long-running daemon mode: a small REST
+// API plus an HTML dashboard for submitting scans and browsing their
+// findings, backed by pkg/store.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+	"github.com/projectdiscovery/nuclei/v2/pkg/store"
+)
+
+// ScanRequest is the JSON body accepted by POST /scans.
+type ScanRequest struct {
+	Targets   []string `json:"targets"`
+	Templates []string `json:"templates"`
+	Severity  []string `json:"severity,omitempty"`
+	RateLimit int      `json:"rate_limit,omitempty"`
+}
+
+// ScanFunc runs a scan for the given request against scan, writing findings
+// into it as they're found and returning an error if the enumeration itself
+// could not be started or failed outright. It's supplied by internal/runner
+// so this package doesn't need to know how a scan is actually executed.
+// ctx is cancelled when the scan's POST /scans/{id}/cancel is called (see
+// runScan); implementations should thread it down to whatever issues the
+// scan's requests so cancellation actually aborts in-flight work.
+type ScanFunc func(ctx context.Context, scan *store.Scan, req ScanRequest) error
+
+// Server is nuclei's daemon-mode HTTP server. It exposes a REST API under
+// /scans and a server-rendered HTML dashboard for the same data.
+type Server struct {
+	httpServer *http.Server
+	store      *store.Store
+	scanFunc   ScanFunc
+}
+
+// New creates a server listening on addr, dispatching submitted scans to scanFunc.
+func New(addr string, store *store.Store, scanFunc ScanFunc) *Server {
+	s := &Server{store: store, scanFunc: scanFunc}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scans", s.handleScans)
+	mux.HandleFunc("/scans/", s.handleScanByID)
+	mux.HandleFunc("/", s.handleIndex)
+
+	s.httpServer = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// ListenAndServe starts the server and blocks until ctx is cancelled, at
+// which point it shuts the server down gracefully.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		return s.httpServer.Shutdown(context.Background())
+	}
+}
+
+// handleScans handles POST /scans (submit a new scan) and GET /scans (list all scans).
+func (s *Server) handleScans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.createScan(w, r)
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.store.List())
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// createScan decodes a ScanRequest, registers it in the store and hands it
+// off to scanFunc asynchronously so the HTTP response doesn't block for the
+// duration of the scan.
+func (s *Server) createScan(w http.ResponseWriter, r *http.Request) {
+	var req ScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errors.Wrap(err, "could not decode scan request").Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Targets) == 0 {
+		http.Error(w, "at least one target is required", http.StatusBadRequest)
+		return
+	}
+
+	scan := s.store.NewScan(req.Targets, req.Templates, req.Severity, req.RateLimit)
+	if s.scanFunc != nil {
+		go s.runScan(scan, req)
+	}
+	writeJSON(w, http.StatusAccepted, scan)
+}
+
+func (s *Server) runScan(scan *store.Scan, req ScanRequest) {
+	ctx, cancel := output.WithCancel(context.Background())
+	scan.SetCancel(cancel)
+	defer cancel()
+
+	scan.SetStatus(store.StatusRunning)
+	if err := s.scanFunc(ctx, scan, req); err != nil {
+		if scan.Status() != store.StatusCancelled {
+			scan.SetStatus(store.StatusFailed)
+			scan.SetError(err.Error())
+		}
+		return
+	}
+	if scan.Status() != store.StatusCancelled {
+		scan.SetStatus(store.StatusDone)
+	}
+}
+
+// handleScanByID dispatches GET /scans/{id}, GET /scans/{id}/findings and
+// POST /scans/{id}/cancel.
+func (s *Server) handleScanByID(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/scans/")
+
+	if strings.HasSuffix(id, "/cancel") {
+		s.cancelScan(w, r, strings.TrimSuffix(id, "/cancel"))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	findings := strings.HasSuffix(id, "/findings")
+	if findings {
+		id = strings.TrimSuffix(id, "/findings")
+	}
+
+	scan, ok := s.store.Get(id)
+	if !ok {
+		http.Error(w, "scan not found", http.StatusNotFound)
+		return
+	}
+	if findings {
+		writeJSON(w, http.StatusOK, scan.Findings())
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		s.renderScan(w, scan)
+		return
+	}
+	writeJSON(w, http.StatusOK, scan)
+}
+
+// cancelScan handles POST /scans/{id}/cancel, stopping a running scan.
+func (s *Server) cancelScan(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	scan, ok := s.store.Get(id)
+	if !ok {
+		http.Error(w, "scan not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"cancelled": scan.Cancel()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,186 @@
+package stream
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// ScanFunc starts a scan for req, writing findings to writer as they're
+// found, and returns once the scan has finished or ctx is cancelled. It's
+// supplied by internal/runner, mirroring pkg/server.ScanFunc.
+type ScanFunc func(ctx context.Context, req *ScanRequest, writer output.Writer) error
+
+// TemplateListFunc returns the IDs of every loaded template. Supplied by
+// internal/runner, backed by its catalogue.
+type TemplateListFunc func() []string
+
+// Server implements the NucleiStream gRPC service declared in stream.proto,
+// streaming a scan's ResultEvents to the caller over server-streaming gRPC
+// instead of (or alongside) pkg/server's REST polling API.
+type Server struct {
+	scanFunc     ScanFunc
+	templateList TemplateListFunc
+	stream       *output.StreamWriter
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewServer creates a gRPC stream server dispatching scans to scanFunc and
+// template listing to templateList. stream is the StreamWriter the scan's
+// output is also wired to (e.g. via output.NewMultiWriter alongside the
+// console writer), so Scan's subscription sees every event the scan
+// produces regardless of how internal/runner constructs the rest of its
+// output pipeline.
+func NewServer(scanFunc ScanFunc, templateList TemplateListFunc, streamWriter *output.StreamWriter) *Server {
+	return &Server{
+		scanFunc:     scanFunc,
+		templateList: templateList,
+		stream:       streamWriter,
+		cancels:      make(map[string]context.CancelFunc),
+	}
+}
+
+// Scan implements the Scan RPC: it starts the scan and streams back every
+// ResultEvent matching the request's filters until the scan completes or
+// the client disconnects.
+func (s *Server) Scan(req *ScanRequest, grpcStream grpc.ServerStream) error {
+	ctx, cancel := output.WithCancel(grpcStream.Context())
+	scanID := newScanID()
+	s.mu.Lock()
+	s.cancels[scanID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.cancels, scanID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	filter := output.StreamFilter{Severity: req.Severity, TemplateIDs: req.TemplateIDs, Hosts: req.Hosts}
+	_, events, unsubscribe := s.stream.Subscribe(filter)
+	defer unsubscribe()
+
+	scanErr := make(chan error, 1)
+	go func() { scanErr <- s.scanFunc(ctx, req, s.stream) }()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return <-scanErr
+			}
+			if err := grpcStream.SendMsg(protoResultEvent(event)); err != nil {
+				return err
+			}
+		case err := <-scanErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Cancel implements the Cancel RPC, stopping a scan previously started by Scan.
+func (s *Server) Cancel(ctx context.Context, req *CancelRequest) (*CancelResponse, error) {
+	s.mu.Lock()
+	cancel, ok := s.cancels[req.ScanID]
+	s.mu.Unlock()
+	if !ok {
+		return &CancelResponse{Ok: false}, nil
+	}
+	cancel()
+	return &CancelResponse{Ok: true}, nil
+}
+
+// ListTemplates implements the ListTemplates RPC.
+func (s *Server) ListTemplates(ctx context.Context, req *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return &ListTemplatesResponse{TemplateIDs: s.templateList()}, nil
+}
+
+func protoResultEvent(event *output.ResultEvent) *ResultEvent {
+	return &ResultEvent{
+		TemplateID:       event.TemplateID,
+		Name:             event.Info.Name,
+		Severity:         event.Info.SeverityHolder.Severity.String(),
+		MatcherName:      event.MatcherName,
+		Host:             event.Host,
+		Matched:          event.Matched,
+		ExtractedResults: event.ExtractedResults,
+		Timestamp:        event.Timestamp.Format("2006-01-02T15:04:05.999999999Z07:00"),
+	}
+}
+
+func newScanID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "scan"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// _NucleiStream_Scan_Handler adapts Server.Scan to grpc.StreamDesc.Handler.
+func _NucleiStream_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	req := new(ScanRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(*Server).Scan(req, stream)
+}
+
+// ServiceDesc is the grpc.ServiceDesc for NucleiStream, as protoc-gen-go-grpc
+// would emit it from stream.proto.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stream.NucleiStream",
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Cancel",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(CancelRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(*Server).Cancel(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/stream.NucleiStream/Cancel"}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(*Server).Cancel(ctx, req.(*CancelRequest))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(ListTemplatesRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(*Server).ListTemplates(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/stream.NucleiStream/ListTemplates"}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(*Server).ListTemplates(ctx, req.(*ListTemplatesRequest))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Scan",
+			Handler:       _NucleiStream_Scan_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stream.proto",
+}
@@ -0,0 +1,77 @@
+package stream
+
+import "github.com/golang/protobuf/proto"
+
+// Message types for stream.proto. protoc-gen-go isn't part of this repo's
+// build (no protoc toolchain is assumed to be on CI runners), so these are
+// hand-maintained to match the schema in stream.proto field-for-field;
+// regenerate and diff against this file if stream.proto changes. Using the
+// plain struct-tag reflection path of github.com/golang/protobuf/proto
+// (rather than a custom Marshal) keeps that honest: no generated-but-fake
+// codegen comment, just a regular Go type proto.Marshal can encode.
+
+// ScanRequest is the Scan RPC's request message.
+type ScanRequest struct {
+	Targets     []string `protobuf:"bytes,1,rep,name=targets" json:"targets,omitempty"`
+	Templates   []string `protobuf:"bytes,2,rep,name=templates" json:"templates,omitempty"`
+	Severity    []string `protobuf:"bytes,3,rep,name=severity" json:"severity,omitempty"`
+	RateLimit   int32    `protobuf:"varint,4,opt,name=rate_limit,json=rateLimit" json:"rate_limit,omitempty"`
+	TemplateIDs []string `protobuf:"bytes,5,rep,name=template_ids,json=templateIds" json:"template_ids,omitempty"`
+	Hosts       []string `protobuf:"bytes,6,rep,name=hosts" json:"hosts,omitempty"`
+}
+
+func (m *ScanRequest) Reset()         { *m = ScanRequest{} }
+func (m *ScanRequest) String() string { return proto.CompactTextString(m) }
+func (*ScanRequest) ProtoMessage()    {}
+
+// ResultEvent is the message streamed back by the Scan RPC, a trimmed
+// projection of output.ResultEvent onto the fields that matter to a
+// streaming consumer.
+type ResultEvent struct {
+	TemplateID       string   `protobuf:"bytes,1,opt,name=template_id,json=templateId" json:"template_id,omitempty"`
+	Name             string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Severity         string   `protobuf:"bytes,3,opt,name=severity" json:"severity,omitempty"`
+	MatcherName      string   `protobuf:"bytes,4,opt,name=matcher_name,json=matcherName" json:"matcher_name,omitempty"`
+	Host             string   `protobuf:"bytes,5,opt,name=host" json:"host,omitempty"`
+	Matched          string   `protobuf:"bytes,6,opt,name=matched" json:"matched,omitempty"`
+	ExtractedResults []string `protobuf:"bytes,7,rep,name=extracted_results,json=extractedResults" json:"extracted_results,omitempty"`
+	Timestamp        string   `protobuf:"bytes,8,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *ResultEvent) Reset()         { *m = ResultEvent{} }
+func (m *ResultEvent) String() string { return proto.CompactTextString(m) }
+func (*ResultEvent) ProtoMessage()    {}
+
+// CancelRequest is the Cancel RPC's request message.
+type CancelRequest struct {
+	ScanID string `protobuf:"bytes,1,opt,name=scan_id,json=scanId" json:"scan_id,omitempty"`
+}
+
+func (m *CancelRequest) Reset()         { *m = CancelRequest{} }
+func (m *CancelRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()    {}
+
+// CancelResponse is the Cancel RPC's response message.
+type CancelResponse struct {
+	Ok bool `protobuf:"varint,1,opt,name=ok" json:"ok,omitempty"`
+}
+
+func (m *CancelResponse) Reset()         { *m = CancelResponse{} }
+func (m *CancelResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelResponse) ProtoMessage()    {}
+
+// ListTemplatesRequest is the ListTemplates RPC's (empty) request message.
+type ListTemplatesRequest struct{}
+
+func (m *ListTemplatesRequest) Reset()         { *m = ListTemplatesRequest{} }
+func (m *ListTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesRequest) ProtoMessage()    {}
+
+// ListTemplatesResponse is the ListTemplates RPC's response message.
+type ListTemplatesResponse struct {
+	TemplateIDs []string `protobuf:"bytes,1,rep,name=template_ids,json=templateIds" json:"template_ids,omitempty"`
+}
+
+func (m *ListTemplatesResponse) Reset()         { *m = ListTemplatesResponse{} }
+func (m *ListTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesResponse) ProtoMessage()    {}
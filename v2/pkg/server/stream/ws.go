@@ -0,0 +1,55 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// upgrader accepts WebSocket connections from any origin: nuclei's daemon
+// mode is meant to be embedded behind whatever auth/reverse-proxy the
+// operator already has in front of it, same trust model as the rest of
+// pkg/server's REST API.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// WebSocketHandler upgrades the request to a WebSocket connection and
+// streams every ResultEvent stream matches as JSON, one object per
+// WebSocket text message (shaped like output.ResultEvent, not the trimmed
+// gRPC ResultEvent message), until the client disconnects. Filters are read from the query
+// string: severity, template_id, and host, each repeatable.
+func WebSocketHandler(stream *output.StreamWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			http.Error(w, errors.Wrap(err, "could not upgrade to websocket").Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		query := r.URL.Query()
+		filter := output.StreamFilter{
+			Severity:    query["severity"],
+			TemplateIDs: query["template_id"],
+			Hosts:       query["host"],
+		}
+
+		_, events, unsubscribe := stream.Subscribe(filter)
+		defer unsubscribe()
+
+		for event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+	}
+}
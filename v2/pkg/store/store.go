@@ -0,0 +1,243 @@
+// Package store keeps an in-memory record of scans submitted to nuclei's
+// HTTP server mode (see pkg/server), so their status and findings can be
+// queried after the triggering request has returned.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/output"
+)
+
+// Status is the lifecycle state of a Scan.
+type Status string
+
+// Supported scan statuses.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Scan is a single submitted scan and its accumulated results. Status and
+// Error are mutated from the goroutine running the scan (see
+// pkg/server.runScan) while being read concurrently from whatever goroutine
+// is rendering the scan over HTTP, so unlike the other fields they're kept
+// unexported and guarded by mu; use the Status/SetStatus/Error/SetError
+// methods instead of touching them directly.
+type Scan struct {
+	ID        string    `json:"id"`
+	Targets   []string  `json:"targets"`
+	Templates []string  `json:"templates"`
+	Severity  []string  `json:"severity,omitempty"`
+	RateLimit int       `json:"rate_limit,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+
+	mu       sync.Mutex
+	status   Status
+	err      string
+	findings []*output.ResultEvent
+	cancel   context.CancelFunc
+}
+
+// scanJSON mirrors Scan's JSON representation. It exists because Status and
+// Error aren't exported struct fields (see the Scan doc comment), so Scan
+// needs a custom MarshalJSON to read them through their mutex-guarded
+// getters instead of reflecting over the struct directly.
+type scanJSON struct {
+	ID        string    `json:"id"`
+	Targets   []string  `json:"targets"`
+	Templates []string  `json:"templates"`
+	Severity  []string  `json:"severity,omitempty"`
+	RateLimit int       `json:"rate_limit,omitempty"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// MarshalJSON implements json.Marshaler, serializing Scan the same way the
+// field tags above would if Status/Error were exported.
+func (s *Scan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(scanJSON{
+		ID:        s.ID,
+		Targets:   s.Targets,
+		Templates: s.Templates,
+		Severity:  s.Severity,
+		RateLimit: s.RateLimit,
+		Status:    s.Status(),
+		Error:     s.Error(),
+		StartedAt: s.StartedAt,
+	})
+}
+
+// Status returns the scan's current lifecycle status.
+func (s *Scan) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// SetStatus updates the scan's lifecycle status.
+func (s *Scan) SetStatus(status Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Error returns the error message recorded against the scan, if it failed.
+func (s *Scan) Error() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// SetError records the error message that caused the scan to fail.
+func (s *Scan) SetError(err string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Findings returns a snapshot of the findings recorded for the scan so far.
+func (s *Scan) Findings() []*output.ResultEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	findings := make([]*output.ResultEvent, len(s.findings))
+	copy(findings, s.findings)
+	return findings
+}
+
+// addFinding appends a result event to the scan's findings.
+func (s *Scan) addFinding(event *output.ResultEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.findings = append(s.findings, event)
+}
+
+// SetCancel registers the function that aborts this scan's in-flight
+// requests, called by whoever starts the scan once it has derived a
+// cancellable context for it (see output.WithCancel).
+func (s *Scan) SetCancel(cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel = cancel
+}
+
+// Cancel stops the scan if it has a registered cancel function and marks it
+// StatusCancelled, returning true. It returns false if no cancel function
+// has been registered yet, e.g. the scan hasn't started running.
+func (s *Scan) Cancel() bool {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	s.SetStatus(StatusCancelled)
+	return true
+}
+
+// Store is a thread-safe, in-memory collection of scans.
+type Store struct {
+	mu    sync.RWMutex
+	scans map[string]*Scan
+}
+
+// New creates a new, empty scan store.
+func New() *Store {
+	return &Store{scans: make(map[string]*Scan)}
+}
+
+// NewScan registers a queued scan for targets/templates and returns it.
+func (s *Store) NewScan(targets, templates, severity []string, rateLimit int) *Scan {
+	scan := &Scan{
+		ID:        newScanID(),
+		Targets:   targets,
+		Templates: templates,
+		Severity:  severity,
+		RateLimit: rateLimit,
+		status:    StatusQueued,
+		StartedAt: time.Now(),
+	}
+	s.mu.Lock()
+	s.scans[scan.ID] = scan
+	s.mu.Unlock()
+	return scan
+}
+
+// Get returns the scan with the given id, if any.
+func (s *Store) Get(id string) (*Scan, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	scan, ok := s.scans[id]
+	return scan, ok
+}
+
+// List returns every scan known to the store, most recently started first.
+func (s *Store) List() []*Scan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	scans := make([]*Scan, 0, len(s.scans))
+	for _, scan := range s.scans {
+		scans = append(scans, scan)
+	}
+	sortScansByStartedAtDesc(scans)
+	return scans
+}
+
+func sortScansByStartedAtDesc(scans []*Scan) {
+	for i := 1; i < len(scans); i++ {
+		for j := i; j > 0 && scans[j].StartedAt.After(scans[j-1].StartedAt); j-- {
+			scans[j], scans[j-1] = scans[j-1], scans[j]
+		}
+	}
+}
+
+// newScanID generates a short random hex identifier for a scan.
+func newScanID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("scan-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Writer returns an output.Writer that records every event written to it
+// against scan, so a scan-scoped output.MultiWriter can feed both the
+// console and the store from the same enumeration run.
+func (s *Scan) Writer() output.Writer {
+	return &scanWriter{scan: s}
+}
+
+// scanWriter is an output.Writer that appends events to its owning Scan
+// instead of writing them anywhere else.
+type scanWriter struct {
+	scan *Scan
+}
+
+var _ output.Writer = &scanWriter{}
+
+func (w *scanWriter) Close() {}
+
+func (w *scanWriter) Colorizer() aurora.Aurora {
+	return aurora.NewAurora(false)
+}
+
+func (w *scanWriter) Write(result *output.ResultEvent) error {
+	w.scan.addFinding(result)
+	return nil
+}
+
+func (w *scanWriter) Request(templateID, url, requestType string, err error, trace *output.RequestTrace) {
+}
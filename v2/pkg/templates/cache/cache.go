@@ -0,0 +1,203 @@
+// Package cache implements a bounded, content-addressed cache of parsed
+// nuclei templates.
+//
+// Templates are keyed by file path, but a cached entry is only served when
+// the file's (mtime, sha256) fingerprint still matches what was stored -
+// this lets a long-running process (server/API mode) pick up edits to a
+// template on disk without restarting. Least-recently-used entries are
+// evicted once the cache grows past its configured size.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// defaultMaxSize is the number of parsed templates retained before the
+// least-recently-used entry is evicted to make room for a new one.
+const defaultMaxSize = 1024
+
+// Stats is a point-in-time snapshot of cache effectiveness counters.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// fingerprint identifies the on-disk state of a template the last time it
+// was read, so a later lookup can notice the file changed underneath the
+// cache.
+type fingerprint struct {
+	modTime int64
+	sha256  string
+}
+
+type entry struct {
+	path        string
+	value       interface{}
+	fingerprint fingerprint
+}
+
+// Cache is a bounded, content-addressed, LRU store of parsed templates. It
+// is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// New creates a Cache bounded at defaultMaxSize entries.
+func New() *Cache {
+	return NewWithSize(defaultMaxSize)
+}
+
+// NewWithSize creates a Cache bounded at maxSize entries. A non-positive
+// maxSize falls back to defaultMaxSize.
+func NewWithSize(maxSize int) *Cache {
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+	return &Cache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Has returns the cached value for path, or nil if there's no entry or the
+// file on disk no longer matches the fingerprint it was cached under. In the
+// latter case the stale entry is evicted so the caller can re-parse and
+// Store a fresh value.
+func (c *Cache) Has(path string) (interface{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	el, ok := c.entries[path]
+	if !ok {
+		c.misses++
+		c.mu.Unlock()
+		return nil, nil
+	}
+	e := el.Value.(*entry)
+	if e.fingerprint.modTime == info.ModTime().UnixNano() {
+		c.order.MoveToFront(el)
+		c.hits++
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.mu.Unlock()
+
+	// mtime moved - confirm whether the content actually changed before
+	// evicting, since some tools (checkouts, touch) bump mtime without
+	// touching the bytes.
+	sum, err := hashFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok = c.entries[path]
+	if !ok {
+		c.misses++
+		return nil, nil
+	}
+	e = el.Value.(*entry)
+	if e.fingerprint.sha256 == sum {
+		e.fingerprint.modTime = info.ModTime().UnixNano()
+		c.order.MoveToFront(el)
+		c.hits++
+		return e.value, nil
+	}
+	c.removeElement(el)
+	c.misses++
+	return nil, nil
+}
+
+// Store saves value for path, evicting the least-recently-used entry if the
+// cache is at capacity. err is accepted for API symmetry with Has but isn't
+// itself cached; a non-nil err simply skips the store.
+func (c *Cache) Store(path string, value interface{}, err error) {
+	if err != nil {
+		return
+	}
+
+	info, statErr := os.Stat(path)
+	if statErr != nil {
+		return
+	}
+	sum, hashErr := hashFile(path)
+	if hashErr != nil {
+		return
+	}
+	fp := fingerprint{modTime: info.ModTime().UnixNano(), sha256: sum}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[path]; ok {
+		e := el.Value.(*entry)
+		e.value, e.fingerprint = value, fp
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&entry{path: path, value: value, fingerprint: fp})
+	c.entries[path] = el
+
+	if c.order.Len() > c.maxSize {
+		if oldest := c.order.Back(); oldest != nil {
+			c.removeElement(oldest)
+			c.evictions++
+		}
+	}
+}
+
+// Invalidate drops path from the cache, if present, forcing the next Has to
+// miss and the caller to re-parse the template.
+func (c *Cache) Invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[path]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Stats returns a snapshot of the cache's hit/miss/eviction counters.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions}
+}
+
+// removeElement deletes el from both the LRU list and the lookup map.
+// The caller must hold c.mu.
+func (c *Cache) removeElement(el *list.Element) {
+	e := el.Value.(*entry)
+	delete(c.entries, e.path)
+	c.order.Remove(el)
+}
+
+// hashFile returns the hex-encoded sha256 of path's contents.
+func hashFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
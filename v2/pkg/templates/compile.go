@@ -2,9 +2,15 @@ package templates
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -14,6 +20,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/common/executer"
 	"github.com/projectdiscovery/nuclei/v2/pkg/protocols/offlinehttp"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates/signer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
 	"github.com/projectdiscovery/nuclei/v2/pkg/utils"
 )
 
@@ -21,10 +29,17 @@ const TemplateExecuterCreationErrorMessage = "cannot create template executer"
 
 var parsedTemplatesCache = make(map[string]*Template, 2500)
 
-// Parse parses a yaml request template file
-//nolint:gocritic // this cannot be passed by pointer
+// Parse parses a yaml request template file. filePath may be a path on
+// local disk, or an https:// / git+https:// URL, in which case it's
+// delegated to ParseURL.
 // TODO make sure reading from the disk the template parsing happens once: see parsers.ParseTemplate vs templates.Parse
+//
+//nolint:gocritic // this cannot be passed by pointer
 func Parse(filePath string, preprocessor Preprocessor, options protocols.ExecuterOptions) (*Template, error) {
+	if isRemoteTemplateURL(filePath) {
+		return ParseURL(filePath, preprocessor, options)
+	}
+
 	if value, found := parsedTemplatesCache[filePath]; found {
 		return value, nil
 	}
@@ -36,18 +51,65 @@ func Parse(filePath string, preprocessor Preprocessor, options protocols.Execute
 		return nil, err
 	}
 	defer f.Close()
-
 	data, err := ioutil.ReadAll(f)
 	if err != nil {
 		return nil, err
 	}
 
+	return finishParse(template, filePath, data, preprocessor, options)
+}
+
+// ParseURL fetches and parses a template from a remote https:// or
+// git+https:// URL (see ParseTemplateURL for the accepted URL shapes). The
+// fetched content's detached signature is verified against
+// options.Options.TrustedTemplateSigners (or signer.DefaultTrustedSigner if
+// that's empty) before the template is compiled, unless
+// options.Options.AllowUnsignedRemoteTemplates is set. Verified bodies are
+// cached both in parsedTemplatesCache (keyed by rawURL, like Parse) and on
+// disk under $XDG_CACHE_HOME/nuclei/remote-templates, so a second process
+// requesting the same URL doesn't need to re-fetch or re-verify it.
+func ParseURL(rawURL string, preprocessor Preprocessor, options protocols.ExecuterOptions) (*Template, error) {
+	if value, found := parsedTemplatesCache[rawURL]; found {
+		return value, nil
+	}
+
+	tplURL, err := ParseTemplateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := readRemoteTemplateCache(rawURL)
+	if !ok {
+		var signature string
+		data, signature, err = fetchRemoteTemplate(tplURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyRemoteTemplateSignature(data, signature, options); err != nil {
+			return nil, errors.Wrapf(err, "could not verify signature for remote template %s", rawURL)
+		}
+		writeRemoteTemplateCache(rawURL, data)
+	}
+
+	return finishParse(&Template{}, rawURL, data, preprocessor, options)
+}
+
+// isRemoteTemplateURL reports whether filePath looks like a remote
+// template location rather than a path on local disk.
+func isRemoteTemplateURL(filePath string) bool {
+	return strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://") || strings.HasPrefix(filePath, "git+https://")
+}
+
+// finishParse decodes data (already fetched/read and, for remote
+// templates, signature-verified) into template and compiles it, caching the
+// result against cacheKey.
+func finishParse(template *Template, cacheKey string, data []byte, preprocessor Preprocessor, options protocols.ExecuterOptions) (*Template, error) {
 	data = template.expandPreprocessors(data)
 	if preprocessor != nil {
 		data = preprocessor.Process(data)
 	}
 
-	err = yaml.NewDecoder(bytes.NewReader(data)).Decode(template)
+	err := yaml.NewDecoder(bytes.NewReader(data)).Decode(template)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +124,7 @@ func Parse(filePath string, preprocessor Preprocessor, options protocols.Execute
 	// Setting up variables regarding template metadata
 	options.TemplateID = template.ID
 	options.TemplateInfo = template.Info
-	options.TemplatePath = filePath
+	options.TemplatePath = cacheKey
 
 	// If no requests, and it is also not a workflow, return error.
 	if len(template.RequestsDNS)+len(template.RequestsHTTP)+len(template.RequestsFile)+len(template.RequestsNetwork)+len(template.RequestsHeadless)+len(template.Workflows) == 0 {
@@ -138,8 +200,248 @@ func Parse(filePath string, preprocessor Preprocessor, options protocols.Execute
 	if template.Executer == nil && template.CompiledWorkflow == nil {
 		return nil, errors.New(TemplateExecuterCreationErrorMessage)
 	}
-	template.Path = filePath
+	template.Path = cacheKey
 
-	parsedTemplatesCache[filePath] = template
+	parsedTemplatesCache[cacheKey] = template
 	return template, nil
 }
+
+// TemplateURL is a parsed remote template location, as produced by
+// ParseTemplateURL.
+type TemplateURL struct {
+	// Raw is the original URL, as passed to ParseTemplateURL.
+	Raw string
+	// Git is true for git+https:// URLs, where FetchURL is a repository to
+	// clone rather than a file to download directly.
+	Git bool
+	// FetchURL is the https:// URL to GET (Git == false), or the
+	// repository to clone (Git == true).
+	FetchURL string
+	// Ref is the git ref (branch or tag) to check out. Only set when Git is
+	// true; an empty Ref checks out the repository's default branch.
+	Ref string
+	// Path is the template file's path within the repository. Only set
+	// when Git is true.
+	Path string
+}
+
+// ParseTemplateURL parses a remote template location, recognizing either a
+// direct https://host/path/to/template.yaml URL, or a
+// git+https://host/org/repo.git[@ref]/path/to/template.yaml URL pointing at
+// a file inside a git repository.
+func ParseTemplateURL(rawURL string) (*TemplateURL, error) {
+	switch {
+	case strings.HasPrefix(rawURL, "git+https://"):
+		return parseGitTemplateURL(rawURL)
+	case strings.HasPrefix(rawURL, "http://"), strings.HasPrefix(rawURL, "https://"):
+		return &TemplateURL{Raw: rawURL, FetchURL: rawURL}, nil
+	default:
+		return nil, fmt.Errorf("unsupported remote template URL: %s", rawURL)
+	}
+}
+
+// parseGitTemplateURL parses the git+https://host/org/repo.git[@ref]/path
+// form described on ParseTemplateURL.
+func parseGitTemplateURL(rawURL string) (*TemplateURL, error) {
+	rest := strings.TrimPrefix(rawURL, "git+")
+
+	const gitSuffix = ".git"
+	idx := strings.Index(rest, gitSuffix)
+	if idx == -1 {
+		return nil, fmt.Errorf("git+https template URL must reference a %s repository: %s", gitSuffix, rawURL)
+	}
+	repo := rest[:idx+len(gitSuffix)]
+	remainder := rest[idx+len(gitSuffix):]
+
+	var ref string
+	if strings.HasPrefix(remainder, "@") {
+		remainder = strings.TrimPrefix(remainder, "@")
+		slash := strings.Index(remainder, "/")
+		if slash == -1 {
+			return nil, fmt.Errorf("git+https template URL is missing a file path: %s", rawURL)
+		}
+		ref, remainder = remainder[:slash], remainder[slash:]
+	}
+
+	path := strings.TrimPrefix(remainder, "/")
+	if path == "" {
+		return nil, fmt.Errorf("git+https template URL is missing a file path: %s", rawURL)
+	}
+	return &TemplateURL{Raw: rawURL, Git: true, FetchURL: repo, Ref: ref, Path: path}, nil
+}
+
+// fetchRemoteTemplate downloads a remote template's content and its
+// detached signature (see signer.Verify), from either a plain https:// URL
+// or a git+https:// repository.
+func fetchRemoteTemplate(tplURL *TemplateURL) (content []byte, signature string, err error) {
+	if tplURL.Git {
+		return fetchGitTemplate(tplURL)
+	}
+	return fetchHTTPTemplate(tplURL.FetchURL)
+}
+
+// fetchHTTPTemplate downloads a template and its detached signature
+// (url + ".sig") over plain HTTP(S).
+func fetchHTTPTemplate(url string) ([]byte, string, error) {
+	data, err := httpGetBytes(url)
+	if err != nil {
+		return nil, "", err
+	}
+	sigData, err := httpGetBytes(url + ".sig")
+	if err != nil {
+		// A missing .sig is "not signed", not a fetch failure - content is
+		// still returned so verifyRemoteTemplateSignature can reject it
+		// with ErrTemplateNotSigned instead of a generic fetch error.
+		return data, "", nil
+	}
+	return data, strings.TrimSpace(string(sigData)), nil
+}
+
+func httpGetBytes(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not fetch %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch %s: got status code %d", url, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// fetchGitTemplate clones tplURL.FetchURL into a scratch directory and
+// reads tplURL.Path (and its detached tplURL.Path + ".sig") from the
+// checkout.
+func fetchGitTemplate(tplURL *TemplateURL) ([]byte, string, error) {
+	dir, err := ioutil.TempDir("", "nuclei-remote-template-")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not create scratch directory for git clone")
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if tplURL.Ref != "" {
+		args = append(args, "--branch", tplURL.Ref)
+	}
+	args = append(args, tplURL.FetchURL, dir)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return nil, "", fmt.Errorf("could not clone %s: %w: %s", tplURL.FetchURL, err, string(out))
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, tplURL.Path))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not read template from cloned repository")
+	}
+	sigData, err := ioutil.ReadFile(filepath.Join(dir, tplURL.Path+".sig"))
+	if err != nil {
+		// A missing .sig is "not signed", not a fetch failure.
+		return data, "", nil
+	}
+	return data, strings.TrimSpace(string(sigData)), nil
+}
+
+// Sentinel errors returned by verifyRemoteTemplateSignature, distinct so
+// callers running against a gradually-adopting community mirror can tell
+// "not signed yet" apart from "actively tampered with or wrong signer".
+var (
+	// ErrTemplateNotSigned is returned when a remote template carries no
+	// detached signature at all.
+	ErrTemplateNotSigned = errors.New("remote template has no detached signature")
+	// ErrInvalidSignature is returned when a remote template's detached
+	// signature is malformed (not valid base64, or the wrong length).
+	ErrInvalidSignature = errors.New("remote template signature is malformed")
+	// ErrSignerNotTrusted is returned when a remote template's detached
+	// signature is well-formed but doesn't verify against any of the
+	// configured trusted signers.
+	ErrSignerNotTrusted = errors.New("remote template is not signed by a trusted signer")
+)
+
+// verifyRemoteTemplateSignature checks content's detached signature against
+// options.Options.TrustedTemplateSigners (falling back to
+// signer.DefaultTrustedSigner when that's empty). Verification is skipped
+// entirely when options.Options.AllowUnsignedRemoteTemplates is set.
+func verifyRemoteTemplateSignature(content []byte, signature string, options protocols.ExecuterOptions) error {
+	if options.Options != nil && options.Options.AllowUnsignedRemoteTemplates {
+		return nil
+	}
+	if signature == "" {
+		return ErrTemplateNotSigned
+	}
+
+	signers := trustedTemplateSigners(options.Options)
+	if len(signers) == 0 {
+		return ErrSignerNotTrusted
+	}
+
+	ok, err := signer.VerifyAny(signers, content, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if !ok {
+		return ErrSignerNotTrusted
+	}
+	return nil
+}
+
+// trustedTemplateSigners returns the configured trusted signers, falling
+// back to signer.DefaultTrustedSigner when options has none configured.
+func trustedTemplateSigners(options *types.Options) []ed25519.PublicKey {
+	if options != nil && len(options.TrustedTemplateSigners) > 0 {
+		return options.TrustedTemplateSigners
+	}
+	if signer.DefaultTrustedSigner != nil {
+		return []ed25519.PublicKey{signer.DefaultTrustedSigner}
+	}
+	return nil
+}
+
+// remoteTemplateCacheDir is $XDG_CACHE_HOME/nuclei/remote-templates (or the
+// platform equivalent of XDG_CACHE_HOME via os.UserCacheDir).
+func remoteTemplateCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "nuclei", "remote-templates"), nil
+}
+
+// remoteTemplateCachePath returns the on-disk path a verified remote
+// template's body is cached under, keyed by the sha256 of its source URL.
+func remoteTemplateCachePath(rawURL string) (string, error) {
+	dir, err := remoteTemplateCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])), nil
+}
+
+// readRemoteTemplateCache returns a previously verified template body
+// cached for rawURL, if any.
+func readRemoteTemplateCache(rawURL string) ([]byte, bool) {
+	path, err := remoteTemplateCachePath(rawURL)
+	if err != nil {
+		return nil, false
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// writeRemoteTemplateCache persists a verified template body for rawURL.
+// Failures are ignored: the cache is a performance optimization, not a
+// correctness requirement, since every miss simply re-fetches and
+// re-verifies.
+func writeRemoteTemplateCache(rawURL string, data []byte) {
+	path, err := remoteTemplateCachePath(rawURL)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(path, data, 0o644)
+}
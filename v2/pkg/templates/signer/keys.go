@@ -0,0 +1,24 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// defaultPublicKeyB64 is the base64 encoded raw ed25519 public key bundled
+// with nuclei to verify the signatures of official remote templates when
+// Options.TrustedTemplateSigners is left empty. See DefaultTrustedSigner.
+const defaultPublicKeyB64 = "cEvSG8G/SZTt2VXRoMe50UCD1VyAnSJI8ENU2s+LW0s="
+
+// DefaultTrustedSigner is nuclei's bundled community signing key, used to
+// verify remote templates when the caller hasn't configured its own
+// Options.TrustedTemplateSigners.
+var DefaultTrustedSigner ed25519.PublicKey
+
+func init() {
+	decoded, err := base64.StdEncoding.DecodeString(defaultPublicKeyB64)
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		return
+	}
+	DefaultTrustedSigner = ed25519.PublicKey(decoded)
+}
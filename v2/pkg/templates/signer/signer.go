@@ -0,0 +1,95 @@
+// Package signer implements Ed25519 signing and verification of nuclei
+// template content, used to authenticate templates fetched from a remote
+// (https:// or git+https://) source before they're compiled and run.
+package signer
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Options contains the configuration required to construct a Signer.
+type Options struct {
+	// PrivateKeyData is the raw ed25519.PrivateKeySize byte private key used
+	// for signing.
+	PrivateKeyData []byte
+	// PublicKeyData is the raw ed25519.PublicKeySize byte public key used
+	// for verification.
+	PublicKeyData []byte
+}
+
+// Signer signs and/or verifies the detached signature of nuclei template
+// content.
+type Signer struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// NewSigner creates a Signer that can both sign and verify template content
+// using the configured private key.
+func NewSigner(options *Options) (*Signer, error) {
+	if options == nil || len(options.PrivateKeyData) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("a %d byte ed25519 private key is required to create a signer", ed25519.PrivateKeySize)
+	}
+	key := ed25519.PrivateKey(options.PrivateKeyData)
+	return &Signer{privateKey: key, publicKey: key.Public().(ed25519.PublicKey)}, nil
+}
+
+// NewVerifier creates a Signer that can only verify template signatures,
+// using the configured public key.
+func NewVerifier(options *Options) (*Signer, error) {
+	if options == nil || len(options.PublicKeyData) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("a %d byte ed25519 public key is required to create a verifier", ed25519.PublicKeySize)
+	}
+	return &Signer{publicKey: ed25519.PublicKey(options.PublicKeyData)}, nil
+}
+
+// Sign returns a base64 encoded detached signature of data.
+func (s *Signer) Sign(data []byte) (string, error) {
+	if s.privateKey == nil {
+		return "", errors.New("signer was not configured with a private key")
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, data)), nil
+}
+
+// Verify reports whether signature is a valid detached signature of data
+// for this Signer's public key.
+func (s *Signer) Verify(data []byte, signature string) (bool, error) {
+	if s.publicKey == nil {
+		return false, errors.New("verifier was not configured with a public key")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, fmt.Errorf("could not decode signature: %w", err)
+	}
+	return ed25519.Verify(s.publicKey, data, decoded), nil
+}
+
+// PublicKey returns the Signer's public key.
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// VerifyAny reports whether signature is a valid detached signature of data
+// for any of the given public keys. An error is returned only if signature
+// itself is malformed (not valid base64, or the wrong length once decoded)
+// - a well-formed signature that simply doesn't match any of signers
+// returns (false, nil), since that's indistinguishable from "signed by
+// someone else" rather than corrupt.
+func VerifyAny(signers []ed25519.PublicKey, data []byte, signature string) (bool, error) {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, fmt.Errorf("could not decode signature: %w", err)
+	}
+	if len(decoded) != ed25519.SignatureSize {
+		return false, fmt.Errorf("signature is %d bytes, expected %d", len(decoded), ed25519.SignatureSize)
+	}
+	for _, pub := range signers {
+		if ed25519.Verify(pub, data, decoded) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,128 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerSignAndVerifyRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(&Options{PrivateKeyData: priv})
+	require.NoError(t, err)
+
+	data := []byte("id: test-template\ninfo:\n  name: test\n")
+	signature, err := signer.Sign(data)
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier(&Options{PublicKeyData: pub})
+	require.NoError(t, err)
+
+	ok, err := verifier.Verify(data, signature)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestSignerVerifyRejectsTamperedData(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(&Options{PrivateKeyData: priv})
+	require.NoError(t, err)
+
+	signature, err := signer.Sign([]byte("original content"))
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier(&Options{PublicKeyData: pub})
+	require.NoError(t, err)
+
+	ok, err := verifier.Verify([]byte("tampered content"), signature)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestSignerVerifyRejectsUntrustedSigner(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(&Options{PrivateKeyData: priv})
+	require.NoError(t, err)
+	signature, err := signer.Sign([]byte("content"))
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier(&Options{PublicKeyData: otherPub})
+	require.NoError(t, err)
+
+	ok, err := verifier.Verify([]byte("content"), signature)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestNewSignerRequiresPrivateKey(t *testing.T) {
+	_, err := NewSigner(nil)
+	require.Error(t, err)
+
+	_, err = NewSigner(&Options{PrivateKeyData: []byte("too short")})
+	require.Error(t, err)
+}
+
+func TestNewVerifierRequiresPublicKey(t *testing.T) {
+	_, err := NewVerifier(nil)
+	require.Error(t, err)
+
+	_, err = NewVerifier(&Options{PublicKeyData: []byte("too short")})
+	require.Error(t, err)
+}
+
+func TestDefaultTrustedSignerIsConfigured(t *testing.T) {
+	require.Len(t, DefaultTrustedSigner, ed25519.PublicKeySize)
+}
+
+func TestVerifyAnyMatchesOneOfSeveralSigners(t *testing.T) {
+	pubA, privA, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pubB, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signerA, err := NewSigner(&Options{PrivateKeyData: privA})
+	require.NoError(t, err)
+	signature, err := signerA.Sign([]byte("content"))
+	require.NoError(t, err)
+
+	ok, err := VerifyAny([]ed25519.PublicKey{pubB, pubA}, []byte("content"), signature)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestVerifyAnyReturnsFalseForUntrustedSigner(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	s, err := NewSigner(&Options{PrivateKeyData: priv})
+	require.NoError(t, err)
+	signature, err := s.Sign([]byte("content"))
+	require.NoError(t, err)
+
+	ok, err := VerifyAny([]ed25519.PublicKey{otherPub}, []byte("content"), signature)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestVerifyAnyErrorsOnMalformedSignature(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = VerifyAny([]ed25519.PublicKey{pub}, []byte("content"), "not-base64!!")
+	require.Error(t, err)
+
+	_, err = VerifyAny([]ed25519.PublicKey{pub}, []byte("content"), "dGVzdA==")
+	require.Error(t, err)
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"context"
+	"crypto/ed25519"
+	"time"
+)
+
 // Options contains the configuration options for nuclei scanner.
 type Options struct {
 	// RandomAgent generates random User-Agent
@@ -52,12 +58,33 @@ type Options struct {
 	Retries int
 	// Rate-Limit is the maximum number of requests per specified target
 	RateLimit int
+	// RateLimitPerHost is the maximum number of requests per second sent to
+	// any single host, independent of RateLimit's overall cap.
+	RateLimitPerHost int
 	// BurpCollaboratorBiid is the Burp Collaborator BIID for polling interactions.
 	BurpCollaboratorBiid string
 	// ProjectPath allows nuclei to use a user defined project folder
 	ProjectPath string
 	// Severity filters templates based on their severity and only run the matching ones.
 	Severity []string
+	// Serve keeps the runner process alive after the initial scan and
+	// exposes an HTTP server for submitting further scans and browsing results.
+	Serve bool
+	// ListenAddress is the host:port the server binds to when Serve is enabled.
+	ListenAddress string
+	// NoCVEEnrichment disables looking up a template's classified
+	// CVE/CWE IDs against the configured vulnerability feed.
+	NoCVEEnrichment bool
+	// UpdateCVEs refreshes the local CVE enrichment cache from the feed at startup.
+	UpdateCVEs bool
+	// CVECacheDB is the path to the local CVE enrichment cache database.
+	CVECacheDB string
+	// ReportJSON is the file to write the aggregated, structured JSON
+	// report to once the run completes.
+	ReportJSON string
+	// ReportSarif is the file to write a SARIF 2.1.0 report to once the
+	// run completes, for ingestion by code-scanning dashboards.
+	ReportSarif string
 	// Target is a single URL/Domain to scan using a template
 	Target string
 	// Targets specifies the targets to scan using templates.
@@ -78,4 +105,113 @@ type Options struct {
 	ExcludedTemplates []string
 	// CustomHeaders is the list of custom global headers to send with each request.
 	CustomHeaders []string
+	// ShowBrowser specifies whether the headless browser window should be visible
+	ShowBrowser bool
+	// ScanStrategy controls how the engine iterates templates/targets:
+	// "template-spray" (default) sprays every template across all targets
+	// before moving to the next, "host-spray" sprays every template at one
+	// target before moving to the next target, and "auto" picks between
+	// the two based on the ratio of targets to templates.
+	ScanStrategy string
+	// Stream indicates targets are being consumed from a streaming input
+	// source rather than a fully enumerated, countable list.
+	Stream bool
+	// Isolated runs template execution inside a dedicated Linux network
+	// namespace, isolating scan traffic from the host's network stack.
+	Isolated bool
+	// ShutdownTimeout is the maximum time to wait for the output writer and
+	// reporting clients to flush pending results during a graceful shutdown.
+	// Defaults to 30 seconds when unset.
+	ShutdownTimeout time.Duration
+	// InteractionsShutdownWait is additional time to wait for pending
+	// interactsh correlations to arrive before a graceful shutdown closes
+	// the poller and its underlying transport.
+	InteractionsShutdownWait time.Duration
+	// TrustedTemplateSigners is the set of Ed25519 public keys a remote
+	// (https:// or git+https://) template's detached signature is verified
+	// against. A template whose signature doesn't verify against any of
+	// these is rejected. Defaults to nuclei's bundled community signing key
+	// (signer.DefaultTrustedSigner) when left empty.
+	TrustedTemplateSigners []ed25519.PublicKey
+	// AllowUnsignedRemoteTemplates allows loading templates fetched from a
+	// remote source even when they carry no, or an invalid, signature - the
+	// --allow-unsigned-remote CLI escape hatch for users pulling from
+	// unsigned community mirrors who accept the risk.
+	AllowUnsignedRemoteTemplates bool
+	// TemplateTimeout is the wall-clock cap for executing a single template
+	// against a single target, across all of its generator iterations.
+	// A zero value disables the deadline and leaves cancellation to the
+	// caller-supplied context only.
+	TemplateTimeout time.Duration
+	// EsURL is the Elasticsearch endpoint findings are bulk-indexed to.
+	// Empty disables the Elasticsearch output sink.
+	EsURL string
+	// EsIndex is the base Elasticsearch index name findings are indexed
+	// under when EsURL is set, rotated daily. Defaults to "nuclei".
+	EsIndex string
+	// SplunkHEC is the Splunk HTTP Event Collector endpoint findings are
+	// sent to. Empty disables the Splunk output sink.
+	SplunkHEC string
+	// SplunkHECToken is the auth token for SplunkHEC.
+	SplunkHECToken string
+	// WebhookURL is a generic HTTP endpoint findings are batch-POSTed to as
+	// newline-delimited JSON. Empty disables the generic webhook output sink.
+	WebhookURL string
+	// SlackWebhook is a Slack incoming webhook URL notified of findings at
+	// or above MinNotifySeverity. Empty disables the Slack output sink.
+	SlackWebhook string
+	// MinNotifySeverity is the minimum severity ("info".."critical") a
+	// finding must have to be pushed to SlackWebhook. Empty notifies at
+	// every severity.
+	MinNotifySeverity string
+	// SarifExport is the file to write a SARIF 2.1.0 log to once the run
+	// completes. Unlike ReportSarif (built from the separate, aggregated
+	// pkg/report.Report), this is built directly by output.StandardWriter
+	// from every ResultEvent it's written, carrying CVSS/CWE properties
+	// and a per-result fingerprint suited to CI/code-scanning upload.
+	SarifExport string
+	// HARFile is the file to write a HAR 1.2 archive of every traced
+	// request/response to once the run completes, for loading into Chrome
+	// DevTools or Burp for post-scan review. Built from the same trace
+	// entries recorded to TraceLogFile, so it's only populated for
+	// protocols (currently http) that plumb a *output.RequestTrace through
+	// to output.Writer.Request.
+	HARFile string
+	// StreamAddress, if set, serves a WebSocket endpoint at /stream on this
+	// host:port streaming every matched ResultEvent live, alongside
+	// whatever other output sinks are configured.
+	StreamAddress string
+	// GRPCAddress, if set, serves the stream.NucleiStream gRPC service
+	// (Scan/Cancel/ListTemplates) on this host:port.
+	GRPCAddress string
+	// PerTargetTimeout is an additional wall-clock cap applied alongside
+	// TemplateTimeout when deriving a request's deadline chain (see
+	// pkg/protocols/http.ExecuteWithResultsContext); whichever of the two
+	// elapses first wins. It exists as a separate knob from TemplateTimeout
+	// because a true cross-template, per-target budget belongs in the
+	// scheduler that iterates every template against one target - but this
+	// snapshot's pkg/core doesn't define the Engine type that owns that
+	// loop, so there's nowhere to track "total time spent on this target
+	// across templates so far". Until that's available, PerTargetTimeout
+	// is applied per (template,target) call, same as TemplateTimeout.
+	PerTargetTimeout time.Duration
+	// GlobalDeadline bounds the wall-clock duration of an entire scan,
+	// measured from when the scan starts. A caller that drives a scan (the
+	// CLI's RunEnumeration, pkg/server's /scans/{id}, the gRPC Scan RPC)
+	// applies it once, at scan start, as a context.WithTimeout wrapping
+	// ScanContext - not re-applied per request - so it behaves like a
+	// single net.Conn.SetDeadline for the whole run rather than a sliding
+	// per-request timeout. Zero disables it.
+	GlobalDeadline time.Duration
+	// ScanContext is the cancellable root context for the current scan.
+	// pkg/protocols/http reads it as the parent for the deadline chain it
+	// derives per request (see ExecuteWithResults), so cancelling it (via
+	// output.WithCancel) aborts every in-flight request across the scan.
+	// It's carried on Options, rather than threaded as a parameter, because
+	// none of the Request/Executer interfaces in this tree accept a
+	// context; this is a pragmatic bridge onto the config bag those
+	// interfaces already share, not the final shape. Nil falls back to
+	// context.Background(), matching the previous always-backgrounded
+	// behavior.
+	ScanContext context.Context
 }
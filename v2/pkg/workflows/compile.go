@@ -1,29 +1,89 @@
 package workflows
 
 import (
-	"os"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/goccy/go-yaml"
 	"github.com/pkg/errors"
 )
 
-// Parse a yaml workflow file
+// Parse a yaml workflow file, resolving any `include:` composition. Each
+// file in the include chain is checked by validateSchema before it is
+// merged; the merged document is not validated again, and the checks are
+// rules over the YAML AST rather than an embedded schema document.
 func Parse(file string) (*Workflow, error) {
-	workflow := &Workflow{}
+	workflow, err := parseFile(file, make(map[string]struct{}))
+	if err != nil {
+		return nil, err
+	}
+	if len(workflow.Workflows) == 0 {
+		return nil, errors.New("no workflow defined")
+	}
+	return workflow, nil
+}
 
-	f, err := os.Open(file)
+// parseFile decodes file, validates it against the workflow schema, and
+// recursively resolves any `include:` paths (relative to file's own
+// directory) into it. visited tracks the absolute paths already on the
+// current include chain so that an include cycle is reported instead of
+// recursing forever.
+func parseFile(file string, visited map[string]struct{}) (*Workflow, error) {
+	absFile, err := filepath.Abs(file)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	if _, ok := visited[absFile]; ok {
+		return nil, fmt.Errorf("workflow include cycle detected at %s", file)
+	}
+	visited[absFile] = struct{}{}
+	defer delete(visited, absFile)
 
-	err = yaml.NewDecoder(f).Decode(workflow)
+	data, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateSchema(file, data); err != nil {
+		return nil, err
+	}
 
-	if len(workflow.Workflows) == 0 {
-		return nil, errors.New("no workflow defined")
+	workflow := &Workflow{}
+	if err := yaml.Unmarshal(data, workflow); err != nil {
+		return nil, errors.Wrapf(err, "could not parse workflow %s", file)
+	}
+
+	dir := filepath.Dir(file)
+	for _, include := range workflow.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(dir, includePath)
+		}
+		included, err := parseFile(includePath, visited)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not resolve include %q from %s", include, file)
+		}
+		// Entries declared directly in file take precedence; included
+		// entries are appended afterwards, in include order, and are
+		// skipped if an entry for the same template already exists.
+		workflow.Workflows = mergeWorkflowTemplates(workflow.Workflows, included.Workflows)
 	}
 	return workflow, nil
 }
+
+// mergeWorkflowTemplates appends from entries missing a Template already
+// present in into, preserving into's order and precedence.
+func mergeWorkflowTemplates(into, from []*WorkflowTemplate) []*WorkflowTemplate {
+	seen := make(map[string]struct{}, len(into))
+	for _, wt := range into {
+		seen[wt.Template] = struct{}{}
+	}
+	for _, wt := range from {
+		if _, ok := seen[wt.Template]; ok {
+			continue
+		}
+		seen[wt.Template] = struct{}{}
+		into = append(into, wt)
+	}
+	return into
+}
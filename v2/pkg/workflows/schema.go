@@ -0,0 +1,83 @@
+package workflows
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// schemaError is returned by validateSchema for a document that fails
+// validation. It satisfies the same shape goccy/go-yaml's own
+// *yaml.SyntaxError exposes (an Error() message with the offending node's
+// source already rendered in), so callers don't need to special-case
+// schema violations versus plain YAML syntax errors.
+type schemaError struct {
+	file    string
+	message string
+	node    ast.Node
+}
+
+func (e *schemaError) Error() string {
+	if e.node == nil {
+		return fmt.Sprintf("%s: %s", e.file, e.message)
+	}
+	pos := e.node.GetToken().Position
+	return fmt.Sprintf("%s:%d:%d: %s\n%s", e.file, pos.Line, pos.Column, e.message, e.node.String())
+}
+
+// validateSchema validates a workflow document's structure - a top-level
+// `workflows:` sequence and/or `include:` block, where each `workflows`
+// entry declares at least one of `template`, `subtemplates` or `matchers` - against
+// the raw YAML AST, so a violation can be reported with the line/column of
+// the offending node rather than failing later with an opaque decode error.
+func validateSchema(file string, data []byte) error {
+	astFile, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if len(astFile.Docs) == 0 || astFile.Docs[0].Body == nil {
+		return &schemaError{file: file, message: "workflow document is empty"}
+	}
+
+	root, ok := astFile.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return &schemaError{file: file, message: "workflow document must be a mapping", node: astFile.Docs[0].Body}
+	}
+
+	workflowsNode := findMappingValue(root, "workflows")
+	includeNode := findMappingValue(root, "include")
+
+	if workflowsNode == nil && includeNode == nil {
+		return &schemaError{file: file, message: "workflow document must declare a `workflows` or `include` block", node: root}
+	}
+	if workflowsNode == nil {
+		return nil
+	}
+
+	sequence, ok := workflowsNode.(*ast.SequenceNode)
+	if !ok {
+		return &schemaError{file: file, message: "`workflows` must be a sequence", node: workflowsNode}
+	}
+	for _, entry := range sequence.Values {
+		entryMapping, ok := entry.(*ast.MappingNode)
+		if !ok {
+			return &schemaError{file: file, message: "each `workflows` entry must be a mapping", node: entry}
+		}
+		if findMappingValue(entryMapping, "template") == nil && findMappingValue(entryMapping, "subtemplates") == nil && findMappingValue(entryMapping, "matchers") == nil {
+			return &schemaError{file: file, message: "each `workflows` entry must declare a `template`", node: entryMapping}
+		}
+	}
+	return nil
+}
+
+// findMappingValue returns the value node of key in mapping, or nil if key
+// is absent.
+func findMappingValue(mapping *ast.MappingNode, key string) ast.Node {
+	for _, value := range mapping.Values {
+		if value.Key.String() == key {
+			return value.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package xfer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// responseCache is a bounded, TTL-based LRU of recently completed Results,
+// keyed by the same fingerprint used for in-flight deduplication, so a
+// duplicate request made shortly after the first one resolves can be
+// served without touching the network at all.
+type responseCache struct {
+	maxBytes int64
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	usedBytes int64
+	order     *list.List
+	entries   map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key       string
+	result    Result
+	size      int64
+	expiresAt time.Time
+}
+
+func newResponseCache(maxBytes int64, ttl time.Duration) *responseCache {
+	return &responseCache{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *responseCache) get(key string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return Result{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return Result{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+func (c *responseCache) put(key string, result Result) {
+	if result.Err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	size := int64(len(result.Body))
+	if existing, ok := c.entries[key]; ok {
+		c.removeElement(existing)
+	}
+
+	entry := &cacheEntry{key: key, result: result, size: size, expiresAt: time.Now().Add(c.ttl)}
+	elem := c.order.PushFront(entry)
+	c.entries[key] = elem
+	c.usedBytes += size
+
+	for c.usedBytes > c.maxBytes && c.order.Len() > 0 {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// removeElement must be called with c.mu held.
+func (c *responseCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	c.usedBytes -= entry.size
+}
@@ -0,0 +1,45 @@
+package xfer
+
+import "time"
+
+// gatePollInterval bounds how promptly a blocked acquire notices that its
+// cancelled predicate flipped to true.
+const gatePollInterval = 50 * time.Millisecond
+
+// weightedGate is a counting semaphore used to enforce the per-host and
+// global concurrency caps. Unlike a plain buffered-channel semaphore, its
+// acquire can be abandoned early if the caller's work has been cancelled
+// while still waiting for a slot.
+type weightedGate struct {
+	slots chan struct{}
+}
+
+func newWeightedGate(capacity int) *weightedGate {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &weightedGate{slots: make(chan struct{}, capacity)}
+}
+
+// acquire blocks until a slot is available or cancelled() reports true, in
+// which case it returns false without having taken a slot.
+func (g *weightedGate) acquire(cancelled func() bool) bool {
+	ticker := time.NewTicker(gatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case g.slots <- struct{}{}:
+			return true
+		case <-ticker.C:
+			if cancelled() {
+				return false
+			}
+		}
+	}
+}
+
+// release returns a slot taken by a successful acquire.
+func (g *weightedGate) release() {
+	<-g.slots
+}
@@ -0,0 +1,272 @@
+// Package xfer implements a shared request transfer manager that sits
+// between an executor and its underlying *retryablehttp.Client, inspired by
+// Docker's upload/download manager. Running many templates against a
+// single target otherwise means as many independent, identical requests as
+// templates that share a URL; the Manager instead deduplicates concurrent
+// and recently-completed requests, enforces per-host concurrency limits,
+// and applies backoff with jitter on transient failures.
+package xfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Result is the outcome of a Transfer, shared by every watcher. The
+// response body is fully captured to Body so multiple watchers can read it
+// independently without racing on a single *http.Response.Body stream.
+type Result struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Err        error
+}
+
+// Options configures a Manager.
+type Options struct {
+	// PerHostConcurrency bounds how many in-flight requests a single host
+	// may have at once. Defaults to 10.
+	PerHostConcurrency int
+	// GlobalConcurrency bounds how many in-flight requests the manager
+	// allows across all hosts. Defaults to 50.
+	GlobalConcurrency int
+	// MaxRetries is the number of backoff retries applied to 5xx
+	// responses and connection errors before giving up. Defaults to 3.
+	MaxRetries int
+	// CacheTTL is how long a completed Result stays eligible for reuse by
+	// a new, identical request. Defaults to 5s.
+	CacheTTL time.Duration
+	// CacheMaxBytes bounds the total size of cached response bodies kept
+	// in the LRU cache. Defaults to 50MB.
+	CacheMaxBytes int64
+}
+
+func (o *Options) setDefaults() {
+	if o.PerHostConcurrency == 0 {
+		o.PerHostConcurrency = 10
+	}
+	if o.GlobalConcurrency == 0 {
+		o.GlobalConcurrency = 50
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.CacheTTL == 0 {
+		o.CacheTTL = 5 * time.Second
+	}
+	if o.CacheMaxBytes == 0 {
+		o.CacheMaxBytes = 50 * 1024 * 1024
+	}
+}
+
+// Manager deduplicates, schedules, and retries HTTP requests shared across
+// many callers (e.g. one HTTPExecutor per template, all hitting the same
+// target).
+type Manager struct {
+	options *Options
+
+	global *weightedGate
+	hosts  sync.Map // host -> *weightedGate
+
+	cache *responseCache
+
+	mu        sync.Mutex
+	transfers map[string]*Transfer
+}
+
+// New creates a new transfer Manager.
+func New(options *Options) *Manager {
+	if options == nil {
+		options = &Options{}
+	}
+	options.setDefaults()
+	return &Manager{
+		options:   options,
+		global:    newWeightedGate(options.GlobalConcurrency),
+		cache:     newResponseCache(options.CacheMaxBytes, options.CacheTTL),
+		transfers: make(map[string]*Transfer),
+	}
+}
+
+// Do routes req through the manager: an identical in-flight request is
+// deduplicated onto the same Transfer, a recently-completed one may be
+// served straight from cache, and otherwise a new Transfer is scheduled
+// (subject to the per-host/global concurrency caps) and retried with
+// backoff on 5xx/connection errors. The returned channel receives exactly
+// one Result.
+func (m *Manager) Do(client *retryablehttp.Client, req *retryablehttp.Request) (*Transfer, <-chan Result) {
+	key := fingerprint(req)
+
+	if cached, ok := m.cache.get(key); ok {
+		transfer := newTransfer(key)
+		watch := transfer.Watch()
+		transfer.complete(cached)
+		return transfer, watch
+	}
+
+	m.mu.Lock()
+	if transfer, ok := m.transfers[key]; ok {
+		watch := transfer.Watch()
+		m.mu.Unlock()
+		return transfer, watch
+	}
+
+	transfer := newTransfer(key)
+	m.transfers[key] = transfer
+	watch := transfer.Watch()
+	m.mu.Unlock()
+
+	host := req.URL.Hostname()
+	hostGate := m.gateFor(host)
+
+	go m.run(client, req, key, transfer, hostGate)
+
+	return transfer, watch
+}
+
+func (m *Manager) gateFor(host string) *weightedGate {
+	if gate, ok := m.hosts.Load(host); ok {
+		return gate.(*weightedGate)
+	}
+	gate := newWeightedGate(m.options.PerHostConcurrency)
+	actual, _ := m.hosts.LoadOrStore(host, gate)
+	return actual.(*weightedGate)
+}
+
+// run executes req, retrying with backoff on transient failures, and
+// publishes the Result to every current and future watcher of transfer.
+// If every watcher cancels before a response is obtained, the in-flight
+// request is abandoned without consuming further retries.
+func (m *Manager) run(client *retryablehttp.Client, req *retryablehttp.Request, key string, transfer *Transfer, hostGate *weightedGate) {
+	defer func() {
+		m.mu.Lock()
+		delete(m.transfers, key)
+		m.mu.Unlock()
+	}()
+
+	if !m.global.acquire(transfer.cancelled) {
+		transfer.complete(Result{Err: errCancelled})
+		return
+	}
+	defer m.global.release()
+
+	if !hostGate.acquire(transfer.cancelled) {
+		transfer.complete(Result{Err: errCancelled})
+		return
+	}
+	defer hostGate.release()
+
+	var lastErr error
+	for attempt := 0; attempt <= m.options.MaxRetries; attempt++ {
+		if transfer.isCancelled() {
+			transfer.complete(Result{Err: errCancelled})
+			return
+		}
+		if attempt > 0 {
+			time.Sleep(backoffWithJitter(attempt))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			lastErr = readErr
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			lastErr = fmt.Errorf("server error: status code %d", resp.StatusCode)
+			continue
+		}
+
+		result := Result{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+		m.cache.put(key, result)
+		transfer.complete(result)
+		return
+	}
+	transfer.complete(Result{Err: lastErr})
+}
+
+// backoffWithJitter returns the exponential backoff delay for the given
+// retry attempt (1-indexed), with up to 50% jitter to avoid a thundering
+// herd of retries landing in lockstep.
+func backoffWithJitter(attempt int) time.Duration {
+	base := time.Duration(1<<uint(attempt-1)) * 200 * time.Millisecond
+	if base > 10*time.Second {
+		base = 10 * time.Second
+	}
+	jitter := time.Duration(rand.Int63n(int64(base) / 2))
+	return base + jitter
+}
+
+// fingerprint derives a stable dedup key for req from its method,
+// canonicalized URL, and a hash of its headers and body.
+func fingerprint(req *retryablehttp.Request) string {
+	canonicalURL := canonicalize(req.URL)
+
+	hasher := sha256.New()
+	hasher.Write([]byte(req.Method))
+	hasher.Write([]byte(canonicalURL))
+
+	headerNames := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		hasher.Write([]byte(name))
+		for _, value := range req.Header[name] {
+			hasher.Write([]byte(value))
+		}
+	}
+
+	if body, err := req.BodyBytes(); err == nil {
+		hasher.Write(body)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// canonicalize normalizes u so equivalent URLs (differing only in query
+// parameter order or trailing slash) fingerprint identically.
+func canonicalize(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(u.Scheme))
+	b.WriteString("://")
+	b.WriteString(strings.ToLower(u.Host))
+	b.WriteString(strings.TrimSuffix(u.Path, "/"))
+	for i, k := range keys {
+		if i == 0 {
+			b.WriteByte('?')
+		} else {
+			b.WriteByte('&')
+		}
+		sort.Strings(query[k])
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strings.Join(query[k], ","))
+	}
+	return b.String()
+}
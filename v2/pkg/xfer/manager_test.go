@@ -0,0 +1,153 @@
+package xfer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRequest(t *testing.T, url string) *retryablehttp.Request {
+	t.Helper()
+	req, err := retryablehttp.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return req
+}
+
+func TestManagerDeduplicatesConcurrentRequests(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	manager := New(nil)
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+
+	const watchers = 5
+	var wg sync.WaitGroup
+	results := make([]Result, watchers)
+	for i := 0; i < watchers; i++ {
+		req := newTestRequest(t, server.URL)
+		_, watch := manager.Do(client, req)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = <-watch
+		}(i)
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits), "expected only one request to reach the server")
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "ok", string(result.Body))
+	}
+}
+
+func TestManagerPartialCancellationKeepsRequestAlive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	manager := New(nil)
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	req := newTestRequest(t, server.URL)
+
+	transfer, watchA := manager.Do(client, req)
+	_, watchB := manager.Do(client, req)
+
+	transfer.Cancel() // withdraw watcher A; watcher B keeps the request alive, so it still completes
+
+	resultB := <-watchB
+	require.NoError(t, resultB.Err)
+	require.Equal(t, http.StatusOK, resultB.StatusCode)
+
+	// Watcher A is still subscribed to the same Transfer, so once the
+	// (still-alive) request completes it observes the same real result -
+	// cancelling just means "I no longer care", not "abort for everyone".
+	resultA := <-watchA
+	require.NoError(t, resultA.Err)
+}
+
+func TestManagerFullCancellationAbortsRequest(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	manager := New(&Options{GlobalConcurrency: 1, PerHostConcurrency: 1})
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+
+	// Hold the only global slot so the transfer below is forced to block in
+	// its acquire, giving Cancel a deterministic window before it would
+	// otherwise reach the server.
+	manager.global.acquire(func() bool { return false })
+	defer manager.global.release()
+
+	req := newTestRequest(t, server.URL)
+	transfer, watchA := manager.Do(client, req)
+	transfer.Cancel() // the only watcher cancels while still blocked acquiring a slot
+
+	result := <-watchA
+	require.Error(t, result.Err)
+	require.EqualValues(t, 0, atomic.LoadInt32(&hits))
+}
+
+func TestManagerCachesCompletedResults(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("cached"))
+	}))
+	defer server.Close()
+
+	manager := New(&Options{CacheTTL: time.Minute})
+	client := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+
+	req := newTestRequest(t, server.URL)
+	_, watch := manager.Do(client, req)
+	result := <-watch
+	require.NoError(t, result.Err)
+
+	req2 := newTestRequest(t, server.URL)
+	_, watch2 := manager.Do(client, req2)
+	result2 := <-watch2
+	require.NoError(t, result2.Err)
+	require.Equal(t, "cached", string(result2.Body))
+
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits), "second identical request should be served from cache")
+}
+
+func TestBackoffWithJitterIsMonotonicallyBounded(t *testing.T) {
+	// testify v1.5.1's Greater/LessOrEqual don't special-case time.Duration,
+	// so comparisons here are done as plain bools rather than through those
+	// assertions.
+	sawPositiveBase := false
+	for attempt := 1; attempt <= 5; attempt++ {
+		delay := backoffWithJitter(attempt)
+		require.True(t, delay > 0, "delay should be positive")
+
+		base := time.Duration(1<<uint(attempt-1)) * 200 * time.Millisecond
+		if base > 10*time.Second {
+			base = 10 * time.Second
+		}
+		require.True(t, delay <= base+base/2, "delay should not exceed base+50%% jitter")
+		sawPositiveBase = sawPositiveBase || base > 0
+	}
+	require.True(t, sawPositiveBase)
+}
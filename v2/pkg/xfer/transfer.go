@@ -0,0 +1,92 @@
+package xfer
+
+import (
+	"errors"
+	"sync"
+)
+
+// errCancelled is delivered to watchers of a Transfer whose last active
+// watcher cancelled before a Result was obtained.
+var errCancelled = errors.New("xfer: transfer cancelled")
+
+// Transfer is a handle onto a single, possibly-shared, in-flight (or
+// cached) request. Every caller deduplicated onto the same underlying
+// request gets its own Transfer.Watch() channel and its own Cancel, but
+// they all observe the same Result.
+type Transfer struct {
+	key string
+
+	mu       sync.Mutex
+	result   *Result
+	done     chan struct{}
+	watchers int
+}
+
+func newTransfer(key string) *Transfer {
+	return &Transfer{key: key, done: make(chan struct{})}
+}
+
+// Watch returns a channel that receives the Transfer's Result exactly
+// once, whether the underlying request completes, fails, or every watcher
+// of the Transfer cancels. The caller must eventually either read from the
+// channel or call Cancel to release its watch.
+func (t *Transfer) Watch() <-chan Result {
+	t.mu.Lock()
+	t.watchers++
+	t.mu.Unlock()
+
+	ch := make(chan Result, 1)
+	go func() {
+		<-t.done
+		t.mu.Lock()
+		result := *t.result
+		t.mu.Unlock()
+		ch <- result
+	}()
+	return ch
+}
+
+// Cancel withdraws one watch registered via Watch. The underlying request
+// is only actually aborted once every watcher has cancelled.
+func (t *Transfer) Cancel() {
+	t.mu.Lock()
+	t.watchers--
+	cancelled := t.watchers <= 0
+	t.mu.Unlock()
+
+	if cancelled {
+		t.complete(Result{Err: errCancelled})
+	}
+}
+
+// isCancelled reports whether every watcher of t has cancelled, without
+// itself completing t - used by the scheduler to poll before/while
+// blocked acquiring a concurrency slot.
+func (t *Transfer) isCancelled() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.watchers <= 0
+}
+
+// cancelled is the poll function passed to the concurrency gates.
+func (t *Transfer) cancelled() bool {
+	return t.isCancelled()
+}
+
+// complete publishes result to every current and future watcher. Only the
+// first call takes effect.
+func (t *Transfer) complete(result Result) {
+	t.mu.Lock()
+	if t.result != nil {
+		t.mu.Unlock()
+		return
+	}
+	t.result = &result
+	t.mu.Unlock()
+	close(t.done)
+}